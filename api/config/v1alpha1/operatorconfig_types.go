@@ -0,0 +1,65 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+)
+
+// TracingConfig configures how traces are sampled and exported.
+// The standard OTEL_* environment variables take precedence over it.
+type TracingConfig struct {
+	// Exporter of the traces: otlp, console (or stdout) or none. Defaults to otlp.
+	// +optional
+	Exporter string `json:"exporter,omitempty"`
+
+	// Protocol of the otlp exporter: grpc or http/protobuf. Defaults to http/protobuf.
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// Endpoint of the otlp exporter, as host:port.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Insecure disables TLS towards the otlp endpoint.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// SamplingRatio of the traces started by the operator, between 0 and 1.
+	// Traces started by a caller follow its sampling decision. Defaults to 1.
+	// +optional
+	SamplingRatio *float64 `json:"samplingRatio,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OperatorConfig is the configuration file of the operator.
+type OperatorConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ControllerManagerConfigurationSpec returns the configurations for controllers
+	cfg.ControllerManagerConfigurationSpec `json:",inline"`
+
+	// Tracing configuration.
+	// +optional
+	Tracing TracingConfig `json:"tracing,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AlertEventType is a kind of Feed event an Alert notifies about.
+// +kubebuilder:validation:Enum=Availability;Error;Download;Stale
+type AlertEventType string
+
+const (
+	// AlertEventAvailability notifies when the Available condition of a Feed changes.
+	AlertEventAvailability AlertEventType = "Availability"
+
+	// AlertEventError notifies when Put.io reports a new error for a Feed.
+	AlertEventError AlertEventType = "Error"
+
+	// AlertEventDownload notifies when Put.io completes new items of a Feed.
+	AlertEventDownload AlertEventType = "Download"
+
+	// AlertEventStale notifies when Put.io did not fetch a running Feed for stale_after.
+	AlertEventStale AlertEventType = "Stale"
+)
+
+// ProviderReference references a NotificationProvider in the same namespace.
+type ProviderReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// AlertRateLimit limits the number of notifications an Alert sends.
+type AlertRateLimit struct {
+	// Maximum number of notifications sent at once.
+	// +kubebuilder:validation:Minimum=1
+	Burst int32 `json:"burst"`
+
+	// Period after which one more notification can be sent, e.g. "1m".
+	Every metav1.Duration `json:"every"`
+}
+
+// AlertSpec defines the desired state of Alert.
+type AlertSpec struct {
+	// NotificationProvider delivering the notifications.
+	ProviderRef ProviderReference `json:"providerRef"`
+
+	// Feeds of the Alert namespace notified about, all of them when empty.
+	// +optional
+	FeedSelector *metav1.LabelSelector `json:"feed_selector,omitempty"`
+
+	// Events notified about, all of them when empty.
+	// +optional
+	Events []AlertEventType `json:"events,omitempty"`
+
+	// Duration without Put.io fetching a running Feed after which it is stale. Default to 24 hours.
+	// +kubebuilder:default="24h"
+	// +optional
+	StaleAfter metav1.Duration `json:"stale_after,omitempty"`
+
+	// Go template of the notification messages, given .Feed, .Event, .Severity and .Message.
+	// Default to "[{{ .Feed.Namespace }}/{{ .Feed.Name }}] {{ .Message }}".
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// Limits the number of notifications sent, the others are dropped. Unlimited when empty.
+	// +optional
+	RateLimit *AlertRateLimit `json:"rate_limit,omitempty"`
+
+	// Stop sending notifications.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// AlertStatus defines the observed state of Alert.
+type AlertStatus struct {
+	// Last time a notification has been sent.
+	// +optional
+	LastSent *metav1.Time `json:"last_sent,omitempty"`
+
+	// Generation of the spec the Ready condition was computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observed_generation,omitempty"`
+
+	// Conditions represent the latest available observations of an Alert state
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=".spec.providerRef.name"
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="Last sent",type="date",JSONPath=".status.last_sent"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type == "Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Alert is the Schema to notify a NotificationProvider about the events of Feeds.
+type Alert struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AlertSpec   `json:"spec,omitempty"`
+	Status AlertStatus `json:"status,omitempty"`
+}
+
+// Subscribes tells whether the Alert notifies about given event type.
+func (r *Alert) Subscribes(event AlertEventType) bool {
+	if len(r.Spec.Events) == 0 {
+		return true
+	}
+
+	for _, e := range r.Spec.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+//+kubebuilder:object:root=true
+
+// AlertList contains a list of Alert.
+type AlertList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Alert `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Alert{}, &AlertList{})
+}
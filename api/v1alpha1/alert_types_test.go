@@ -0,0 +1,39 @@
+package v1alpha1
+
+import "testing"
+
+func TestAlert_Subscribes(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []AlertEventType
+		event  AlertEventType
+		want   bool
+	}{
+		{
+			name:   "every event",
+			events: nil,
+			event:  AlertEventStale,
+			want:   true,
+		},
+		{
+			name:   "subscribed event",
+			events: []AlertEventType{AlertEventError, AlertEventDownload},
+			event:  AlertEventDownload,
+			want:   true,
+		},
+		{
+			name:   "other event",
+			events: []AlertEventType{AlertEventError},
+			event:  AlertEventAvailability,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Alert{Spec: AlertSpec{Events: tt.events}}
+			if got := r.Subscribes(tt.event); got != tt.want {
+				t.Errorf("Subscribes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit bounds the search of the next time matching a cron expression, e.g. for "0 0 30 2 *".
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// cronField describes one of the five fields of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = [...]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// CronSyntaxError tells which field of a cron expression is malformed.
+type CronSyntaxError struct {
+	Field string
+	Msg   string
+}
+
+func (e *CronSyntaxError) Error() string {
+	if e.Field == "" {
+		return e.Msg
+	}
+
+	return fmt.Sprintf("%s: %s", e.Field, e.Msg)
+}
+
+// CronExpression is a parsed cron expression, matching times at the minute.
+// +kubebuilder:object:generate=false
+type CronExpression struct {
+	minute, hour, dom, month, dow uint64
+
+	// a restricted day of month or day of week matches on its own, as with cron
+	domStar, dowStar bool
+}
+
+// ParseCronExpression parses a standard five fields cron expression: minute, hour, day of month, month and day of week.
+// Fields accept "*", values, ranges "1-5", steps "*/15" or "1-10/2", and comma-separated lists of them.
+// Months and days of week can be given by their three-letter English names, Sunday is either 0 or 7.
+func ParseCronExpression(s string) (*CronExpression, error) {
+	fields := strings.Fields(s)
+	if len(fields) != len(cronFields) {
+		return nil, &CronSyntaxError{Msg: fmt.Sprintf("expected %d fields, got %d", len(cronFields), len(fields))}
+	}
+
+	var (
+		sets [len(cronFields)]uint64
+		err  error
+	)
+
+	for i, field := range fields {
+		if sets[i], err = parseCronField(field, cronFields[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sunday is both 0 and 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	return &CronExpression{
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     sets[4],
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField returns the set of values matched by given field, as a bit set.
+func parseCronField(s string, field cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(s, ",") {
+		expr, stepExpr, hasStep := strings.Cut(item, "/")
+
+		var (
+			low, high = field.min, field.max
+			step      = 1
+			err       error
+		)
+
+		if hasStep {
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, &CronSyntaxError{Field: field.name, Msg: fmt.Sprintf("invalid step %q", stepExpr)}
+			}
+		}
+
+		if expr != "*" {
+			lowExpr, highExpr, isRange := strings.Cut(expr, "-")
+			if low, err = parseCronValue(lowExpr, field); err != nil {
+				return 0, err
+			}
+
+			high = low
+			switch {
+			case isRange:
+				if high, err = parseCronValue(highExpr, field); err != nil {
+					return 0, err
+				}
+			case hasStep:
+				high = field.max // "5/10" starts at 5
+			}
+
+			if low > high {
+				return 0, &CronSyntaxError{Field: field.name, Msg: fmt.Sprintf("invalid range %q", expr)}
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << v
+		}
+	}
+
+	return set, nil
+}
+
+// parseCronValue parses a number, or a name when the field has names.
+func parseCronValue(s string, field cronField) (int, error) {
+	if v, ok := field.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < field.min || v > field.max {
+		return 0, &CronSyntaxError{Field: field.name, Msg: fmt.Sprintf("invalid value %q, expected %d to %d", s, field.min, field.max)}
+	}
+
+	return v, nil
+}
+
+// Next returns the first time matching the expression strictly after given time, in its location.
+// It returns the zero time when there is none within 5 years.
+func (c *CronExpression) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(cronSearchLimit)
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) { // the hour repeats when leaving daylight saving time
+				next = t.Truncate(time.Hour).Add(time.Hour)
+			}
+			t = next
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchDay tells whether the day of given time matches the day of month and day of week fields.
+// As with cron, when both are restricted, a day matching either of them matches.
+func (c *CronExpression) matchDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
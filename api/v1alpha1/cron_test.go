@@ -0,0 +1,134 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr string
+	}{
+		{name: "every minute", s: "* * * * *"},
+		{name: "lists, ranges and steps", s: "0,30 8-18/2 1-15 */3 1-5"},
+		{name: "names", s: "0 20 * jan-jun MON,wed"},
+		{name: "sunday as 7", s: "0 0 * * 7"},
+		{name: "missing field", s: "0 20 * *", wantErr: "expected 5 fields, got 4"},
+		{name: "minute out of range", s: "60 * * * *", wantErr: `minute: invalid value "60", expected 0 to 59`},
+		{name: "hour out of range", s: "0 24 * * *", wantErr: `hour: invalid value "24", expected 0 to 23`},
+		{name: "day of month zero", s: "0 0 0 * *", wantErr: `day of month: invalid value "0", expected 1 to 31`},
+		{name: "unknown name", s: "0 0 * * fun", wantErr: `day of week: invalid value "fun", expected 0 to 7`},
+		{name: "reversed range", s: "0 18-8 * * *", wantErr: `hour: invalid range "18-8"`},
+		{name: "invalid step", s: "*/0 * * * *", wantErr: `minute: invalid step "0"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCronExpression(tt.s)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseCronExpression() error = %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ParseCronExpression() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronExpression_Next(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "next minute",
+			expr: "* * * * *",
+			t:    time.Date(2022, 10, 12, 20, 0, 30, 0, time.UTC),
+			want: time.Date(2022, 10, 12, 20, 1, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after",
+			expr: "0 20 * * *",
+			t:    time.Date(2022, 10, 12, 20, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 10, 13, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of week",
+			expr: "0 20 * * wed",
+			t:    time.Date(2022, 10, 13, 0, 0, 0, 0, time.UTC), // Thursday
+			want: time.Date(2022, 10, 19, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as 7",
+			expr: "30 6 * * 7",
+			t:    time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 10, 16, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 15 * mon",
+			t:    time.Date(2022, 10, 11, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2022, 10, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "next year",
+			expr: "0 0 1 jan *",
+			t:    time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "step",
+			expr: "*/15 * * * *",
+			t:    time.Date(2022, 10, 12, 20, 16, 0, 0, time.UTC),
+			want: time.Date(2022, 10, 12, 20, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "in location",
+			expr: "0 20 * * *",
+			t:    time.Date(2022, 10, 12, 19, 0, 0, 0, paris),
+			want: time.Date(2022, 10, 12, 20, 0, 0, 0, paris),
+		},
+		{
+			name: "skipped hour entering daylight saving time",
+			expr: "30 2 * * *",
+			t:    time.Date(2022, 3, 27, 0, 0, 0, 0, paris),
+			want: time.Date(2022, 3, 28, 2, 30, 0, 0, paris),
+		},
+		{
+			name: "repeated hour leaving daylight saving time",
+			expr: "0 3 * * *",
+			t:    time.Date(2022, 10, 30, 1, 0, 0, 0, paris),
+			want: time.Date(2022, 10, 30, 3, 0, 0, 0, paris),
+		},
+		{
+			name: "never",
+			expr: "0 0 30 feb *",
+			t:    time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC),
+			want: time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCronExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCronExpression() error = %v", err)
+			}
+
+			if got := c.Next(tt.t); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
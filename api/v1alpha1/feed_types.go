@@ -28,20 +28,121 @@ type AuthSecretReference struct {
 	Key string `json:"key"`
 }
 
+// SecretKeyReference references a key of a Secret in the same namespace.
+type SecretKeyReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// RssSourceURLSource reads the URL of the RSS feed, or its passkey, from a Secret.
+type RssSourceURLSource struct {
+	// Secret key holding the URL of the RSS feed, or the passkey replacing ${passkey} in rss_source_url.
+	SecretKeyRef SecretKeyReference `json:"secretKeyRef"`
+}
+
+// PasskeyPlaceholder is replaced in rss_source_url by the value of rssSourceURLFrom.
+const PasskeyPlaceholder = "${passkey}"
+
+// DeletionPolicy tells what happens to the Put.io feed when its Feed is deleted.
+// +kubebuilder:validation:Enum=Delete;Orphan;Pause
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the Put.io feed.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the Put.io feed as is, no longer managed by the operator.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	// DeletionPolicyPause pauses the Put.io feed and leaves it no longer managed by the operator.
+	DeletionPolicyPause DeletionPolicy = "Pause"
+)
+
+// ExpiryAction tells what happens to a Feed once it expired.
+// +kubebuilder:validation:Enum=Pause;Delete;Condition
+type ExpiryAction string
+
+const (
+	// ExpiryActionPause pauses the Put.io feed, without changing paused.
+	ExpiryActionPause ExpiryAction = "Pause"
+	// ExpiryActionDelete deletes the Feed, its deletion policy applying to the Put.io feed.
+	ExpiryActionDelete ExpiryAction = "Delete"
+	// ExpiryActionCondition only reports the Feed expired through its Expired condition.
+	ExpiryActionCondition ExpiryAction = "Condition"
+)
+
+// FeedExpiry tells when a Feed is done with, e.g. once its season ended. The Feed expires as soon as one is reached.
+type FeedExpiry struct {
+	// Time after which the Feed expires.
+	// +optional
+	After *metav1.Time `json:"after,omitempty"`
+
+	// Number of items Put.io successfully processed for the RSS feed after which the Feed expires.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AfterMatches *int32 `json:"afterMatches,omitempty"`
+
+	// The Feed expires once Put.io processed no new item for this long, e.g. "720h".
+	// +optional
+	InactiveFor *metav1.Duration `json:"inactiveFor,omitempty"`
+
+	// What happens once the Feed expired: Pause the Put.io feed, Delete the Feed, or only set its Expired Condition.
+	// Default to Pause.
+	// +optional
+	OnExpiry ExpiryAction `json:"onExpiry,omitempty"`
+}
+
+// Keywords selects the items to transfer by the words in their title.
+type Keywords struct {
+	// Words which must all be in the title.
+	// +optional
+	AllOf []string `json:"allOf,omitempty"`
+
+	// Words of which at least one must be in the title, along with every allOf word.
+	// +optional
+	AnyOf []string `json:"anyOf,omitempty"`
+
+	// Words of which none must be in the title.
+	// +optional
+	NoneOf []string `json:"noneOf,omitempty"`
+}
+
 // FeedSpec defines the desired state of Feed.
 type FeedSpec struct {
 	// +kubebuilder:validation:MinLength:=1
 	// Title of the RSS feed as will appear on the site.
 	Title string `json:"title"`
 
-	// +kubebuilder:validation:MinLength:=1
-	// The URL of the RSS feed to be watched.
-	RssSourceURL string `json:"rss_source_url"`
+	// The URL of the RSS feed to be watched. When rssSourceURLFrom is given, it may contain a ${passkey} placeholder
+	// replaced by the secret value. Required unless rssSourceURLFrom is given.
+	// +optional
+	RssSourceURL string `json:"rss_source_url,omitempty"`
+
+	// Secret holding the URL of the RSS feed, or its passkey when rss_source_url contains ${passkey},
+	// so that the credential does not appear in the Feed.
+	// +optional
+	RssSourceURLFrom *RssSourceURLSource `json:"rssSourceURLFrom,omitempty"`
 
 	// The file ID of the folder to place the RSS feed files in. Default to the root directory (0).
+	// Mutually exclusive with parentFolderRef, parent_dir_path and createParentDir.
 	// +optional
 	ParentDirID *uint `json:"parent_dir_id,omitempty"`
 
+	// Reference to a Folder of the same namespace to place the RSS feed files in.
+	// Mutually exclusive with parent_dir_id, parent_dir_path and createParentDir.
+	// +optional
+	ParentFolderRef *FolderReference `json:"parentFolderRef,omitempty"`
+
+	// Slash-separated path of an existing folder to place the RSS feed files in, e.g. "TV Shows/House of the Dragon".
+	// Mutually exclusive with parent_dir_id, parentFolderRef and createParentDir.
+	// +optional
+	ParentDirPath string `json:"parent_dir_path,omitempty"`
+
+	// Slash-separated path of the folder to place the RSS feed files in, created along with its missing parents
+	// when it does not exist yet. Mutually exclusive with parent_dir_id, parentFolderRef and parent_dir_path.
+	// +optional
+	CreateParentDir string `json:"createParentDir,omitempty"`
+
 	// Should old files in the folder be deleted when space is low. Default to false.
 	// +optional
 	DeleteOldFiles *bool `json:"delete_old_files,omitempty"`
@@ -51,39 +152,156 @@ type FeedSpec struct {
 	DontProcessWholeFeed *bool `json:"dont_process_whole_feed,omitempty"`
 
 	// +kubebuilder:validation:MinLength:=1
-	// Only items with titles that contain any of these words will be transferred (comma-separated list of words).
-	Keyword string `json:"keyword"`
+	// Only items with titles that contain any of these words will be transferred (comma-separated list of words,
+	// each alternative being an ampersand-separated list of words which must all be in the title).
+	// Mutually exclusive with keywords.
+	// +optional
+	Keyword string `json:"keyword,omitempty"`
 
 	// No items with titles that contain any of these words will be transferred (comma-separated list of words).
+	// Mutually exclusive with keywords.noneOf.
 	// +optional
 	UnwantedKeywords string `json:"unwanted_keywords,omitempty"`
 
+	// Structured form of keyword and unwanted_keywords. Mutually exclusive with keyword.
+	// +optional
+	Keywords *Keywords `json:"keywords,omitempty"`
+
 	// Should the RSS feed be created in the paused state. Default to false.
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
 
-	// Authentication reference to Put.io token in a secret.
-	AuthSecretRef AuthSecretReference `json:"authSecretRef"`
+	// Recurring windows during which the RSS feed is active, it is paused outside of them.
+	// A paused feed stays paused whatever its schedule.
+	// +optional
+	Schedule *FeedSchedule `json:"schedule,omitempty"`
+
+	// When the Feed expires, and what happens then.
+	// +optional
+	Expiry *FeedExpiry `json:"expiry,omitempty"`
+
+	// List the items of the RSS feed matching the keywords in status.preview instead of creating the Put.io feed.
+	// An existing Put.io feed is left untouched while previewing.
+	// +optional
+	Preview bool `json:"preview,omitempty"`
+
+	// What happens to the Put.io feed when this Feed is deleted: Delete it, Orphan it, or Pause it. Default to Delete.
+	// Orphaned and paused feeds are no longer marked as managed by the operator in their title.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Authentication reference to Put.io token in a secret. Mutually exclusive with accountRef.
+	// +optional
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+
+	// Reference to a cluster-wide PutioAccount allowing this namespace. Mutually exclusive with authSecretRef.
+	// +optional
+	AccountRef *AccountReference `json:"accountRef,omitempty"`
 }
 
 // FeedStatus defines the observed state of Feed.
 type FeedStatus struct {
 	ID *uint `json:"id,omitempty"`
 
+	// Hash of the Put.io feed payload last applied from the spec.
+	// +optional
+	SpecHash string `json:"spec_hash,omitempty"`
+
+	// Resource version of the secret holding the Put.io token last accepted by Put.io.
+	// +optional
+	AuthSecretVersion string `json:"auth_secret_version,omitempty"`
+
+	// File ID of the folder resolved from parentFolderRef, parent_dir_path or createParentDir.
+	// +optional
+	ParentDirID *uint `json:"parent_dir_id,omitempty"`
+
+	// Last time Put.io fetched the RSS feed.
+	// +optional
+	LastFetch *metav1.Time `json:"last_fetch,omitempty"`
+
+	// Last error reported by Put.io while processing the RSS feed.
+	// +optional
+	LastError string `json:"last_error,omitempty"`
+
+	// Number of feed items Put.io failed to transfer.
+	// +optional
+	FailedItemCount uint `json:"failed_item_count,omitempty"`
+
+	// When the RSS feed was paused at Put.io.
+	// +optional
+	PausedAt *metav1.Time `json:"paused_at,omitempty"`
+
+	// When the schedule next resumes or pauses the RSS feed.
+	// +optional
+	NextTransition *metav1.Time `json:"next_transition,omitempty"`
+
+	// When Put.io started to process the RSS feed.
+	// +optional
+	StartAt *metav1.Time `json:"start_at,omitempty"`
+
+	// Last time the RSS feed was updated at Put.io.
+	// +optional
+	UpdatedAt *metav1.Time `json:"updated_at,omitempty"`
+
+	// Whether Put.io extracts archives downloaded by the RSS feed.
+	// +optional
+	Extract bool `json:"extract,omitempty"`
+
+	// Number of items Put.io successfully processed for the RSS feed since the operator manages it.
+	// +optional
+	CompletedItemCount int32 `json:"completed_item_count,omitempty"`
+
+	// ID of the last item Put.io processed for the RSS feed, counted in completed_item_count.
+	// +optional
+	LastItemID *uint `json:"last_item_id,omitempty"`
+
+	// When Put.io processed the last item of the RSS feed.
+	// +optional
+	LastItemAt *metav1.Time `json:"last_item_at,omitempty"`
+
+	// Title of the last item Put.io successfully processed for the RSS feed.
+	// +optional
+	LastCompletedItem string `json:"last_completed_item,omitempty"`
+
+	// Items of the RSS feed matching the keywords, when spec.preview is set.
+	// +optional
+	Preview *FeedPreview `json:"preview,omitempty"`
+
 	// Conditions represent the latest available observations of a Feed state
 	Conditions []metav1.Condition `json:"conditions"`
 }
 
+// FeedPreview lists the items of the RSS feed Put.io would transfer.
+type FeedPreview struct {
+	// Number of items in the RSS feed.
+	TotalItems int `json:"total_items"`
+
+	// Titles of the items matching the keywords, the most recent first, at most 100.
+	// +optional
+	MatchedItems []string `json:"matched_items,omitempty"`
+
+	// Number of items matching the keywords, including the ones not listed.
+	MatchedItemCount int `json:"matched_item_count"`
+
+	// When the RSS feed was fetched.
+	FetchedAt metav1.Time `json:"fetched_at"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Keyword",type=string,JSONPath=".spec.keyword"
 // +kubebuilder:printcolumn:name="Paused",type=boolean,JSONPath=".spec.paused"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Available",type="string",JSONPath=`.status.conditions[?(@.type == "Available")].status`
+// +kubebuilder:printcolumn:name="Auth",type="string",priority=1,JSONPath=`.status.conditions[?(@.type == "AuthReady")].status`
 // +kubebuilder:printcolumn:name="ID",type=string,priority=1,JSONPath=".status.id"
 // +kubebuilder:printcolumn:name="URL",type=string,priority=1,JSONPath=".spec.rss_source_url"
 // +kubebuilder:printcolumn:name="Title",type=string,priority=1,JSONPath=".spec.title"
 // +kubebuilder:printcolumn:name="Last fetch",type=date,priority=1,JSONPath=".status.last_fetch"
+// +kubebuilder:printcolumn:name="Failed items",type=integer,priority=1,JSONPath=".status.failed_item_count"
+// +kubebuilder:printcolumn:name="Last error",type=string,priority=1,JSONPath=".status.last_error"
+// +kubebuilder:printcolumn:name="Next transition",type=date,priority=1,JSONPath=".status.next_transition"
 
 // Feed is the Schema to manage your rss feeds.
 type Feed struct {
@@ -94,8 +312,28 @@ type Feed struct {
 	Status FeedStatus `json:"status,omitempty"`
 }
 
+// PutioKeywords returns the Put.io keyword and unwanted keywords expressions of the feed,
+// compiled from keywords when given.
+func (r *Feed) PutioKeywords() (keyword, unwanted string) {
+	if r.Spec.Keywords == nil {
+		return r.Spec.Keyword, r.Spec.UnwantedKeywords
+	}
+
+	keyword, unwanted = r.Spec.Keywords.Compile()
+	if unwanted == "" {
+		unwanted = r.Spec.UnwantedKeywords
+	}
+
+	return keyword, unwanted
+}
+
+// AuthSecretRef returns the secret reference of the feed, empty when it uses a PutioAccount.
 func (r *Feed) AuthSecretRef() AuthSecretReference {
-	return r.Spec.AuthSecretRef
+	if r.Spec.AuthSecretRef == nil {
+		return AuthSecretReference{}
+	}
+
+	return *r.Spec.AuthSecretRef
 }
 
 //+kubebuilder:object:root=true
@@ -33,7 +33,7 @@ func TestFeed_AuthSecretRef(t *testing.T) {
 					Keyword:              "",
 					UnwantedKeywords:     "",
 					Paused:               new(bool),
-					AuthSecretRef: AuthSecretReference{
+					AuthSecretRef: &AuthSecretReference{
 						Name: "foo",
 						Key:  "bar",
 					},
@@ -18,7 +18,10 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
 	"net/url"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -59,7 +62,7 @@ func (r *Feed) Default() {
 	span.SetAttributes(attribute.String("name", r.Name))
 	feedlog.Info("default", "name", r.Name)
 
-	if r.Spec.ParentDirID == nil {
+	if r.Spec.ParentDirID == nil && r.Spec.ParentFolderRef == nil && r.Spec.ParentDirPath == "" && r.Spec.CreateParentDir == "" {
 		r.Spec.ParentDirID = new(uint)
 		*r.Spec.ParentDirID = defaultParentDirID
 	}
@@ -75,6 +78,14 @@ func (r *Feed) Default() {
 	if r.Spec.Paused == nil {
 		r.Spec.Paused = new(bool)
 	}
+
+	if r.Spec.DeletionPolicy == "" {
+		r.Spec.DeletionPolicy = DeletionPolicyDelete
+	}
+
+	if r.Spec.Expiry != nil && r.Spec.Expiry.OnExpiry == "" {
+		r.Spec.Expiry.OnExpiry = ExpiryActionPause
+	}
 }
 
 //+kubebuilder:webhook:path=/validate-putio-skynewz-dev-v1alpha1-feed,mutating=false,failurePolicy=fail,sideEffects=None,groups=putio.skynewz.dev,resources=feeds,verbs=create;update,versions=v1alpha1,name=vfeed.kb.io,admissionReviewVersions=v1
@@ -116,13 +127,214 @@ func (r *Feed) validateFeedSpec() error {
 	defer span.End()
 
 	// validate URL
-	return r.validateRSSSourceURL(r.Spec.RssSourceURL, field.NewPath("spec").Child("rss_source_url"))
+	if err := r.validateRSSSource(field.NewPath("spec")); err != nil {
+		return err
+	}
+
+	// validate keywords
+	if err := r.validateKeywords(field.NewPath("spec")); err != nil {
+		return err
+	}
+
+	// validate parent folder
+	if err := r.validateParentDir(field.NewPath("spec")); err != nil {
+		return err
+	}
+
+	// validate schedule
+	if err := r.validateSchedule(field.NewPath("spec")); err != nil {
+		return err
+	}
+
+	// validate expiry
+	if err := r.validateExpiry(field.NewPath("spec")); err != nil {
+		return err
+	}
+
+	// validate authentication
+	return r.validateAuthentication(field.NewPath("spec"))
+}
+
+// validateKeywords ensures exactly one of keyword and keywords is given, and that expressions can be understood by Put.io.
+func (r *Feed) validateKeywords(fldPath *field.Path) error {
+	switch {
+	case r.Spec.Keyword == "" && r.Spec.Keywords == nil:
+		return field.Required(fldPath.Child("keyword"), "one of keyword or keywords is required")
+	case r.Spec.Keyword != "" && r.Spec.Keywords != nil:
+		return field.Forbidden(fldPath.Child("keywords"), "keywords cannot be used along with keyword")
+	}
+
+	var errs field.ErrorList
+	if r.Spec.Keyword != "" {
+		if _, err := ParseKeywordExpression(r.Spec.Keyword); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("keyword"), r.Spec.Keyword, err.Error()))
+		}
+	}
+
+	if r.Spec.UnwantedKeywords != "" {
+		if _, err := ParseKeywordExpression(r.Spec.UnwantedKeywords); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("unwanted_keywords"), r.Spec.UnwantedKeywords, err.Error()))
+		}
+	}
+
+	if keywords := r.Spec.Keywords; keywords != nil {
+		fldPath := fldPath.Child("keywords")
+		if len(keywords.AllOf) == 0 && len(keywords.AnyOf) == 0 {
+			errs = append(errs, field.Required(fldPath.Child("allOf"), "at least one of allOf or anyOf is required"))
+		}
+
+		if len(keywords.NoneOf) > 0 && r.Spec.UnwantedKeywords != "" {
+			errs = append(errs, field.Forbidden(fldPath.Child("noneOf"), "noneOf cannot be used along with unwanted_keywords"))
+		}
+
+		errs = append(errs, validateKeywordList(fldPath.Child("allOf"), keywords.AllOf)...)
+		errs = append(errs, validateKeywordList(fldPath.Child("anyOf"), keywords.AnyOf)...)
+		errs = append(errs, validateKeywordList(fldPath.Child("noneOf"), keywords.NoneOf)...)
+	}
+
+	return errs.ToAggregate()
+}
+
+func validateKeywordList(fldPath *field.Path, keywords []string) field.ErrorList {
+	var errs field.ErrorList
+	for i, keyword := range keywords {
+		if err := validateKeyword(keyword); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Index(i), keyword, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// validateParentDir ensures at most one of parent_dir_id, parentFolderRef, parent_dir_path and createParentDir is given,
+// and that createParentDir names a folder.
+func (r *Feed) validateParentDir(fldPath *field.Path) error {
+	given := make([]string, 0, 4)
+	if r.Spec.ParentDirID != nil {
+		given = append(given, "parent_dir_id")
+	}
+
+	if r.Spec.ParentFolderRef != nil {
+		given = append(given, "parentFolderRef")
+	}
+
+	if r.Spec.ParentDirPath != "" {
+		given = append(given, "parent_dir_path")
+	}
+
+	if r.Spec.CreateParentDir != "" {
+		given = append(given, "createParentDir")
+	}
+
+	if len(given) > 1 {
+		return field.Forbidden(fldPath.Child(given[1]), fmt.Sprintf("%s cannot be used along with %s", given[1], given[0]))
+	}
+
+	if r.Spec.CreateParentDir != "" && strings.Trim(r.Spec.CreateParentDir, "/") == "" {
+		return field.Invalid(fldPath.Child("createParentDir"), r.Spec.CreateParentDir, "must name a folder, the root directory always exists")
+	}
+
+	return nil
+}
+
+// validateSchedule ensures the time zone and every window of the schedule can be understood.
+func (r *Feed) validateSchedule(fldPath *field.Path) error {
+	schedule := r.Spec.Schedule
+	if schedule == nil {
+		return nil
+	}
+
+	fldPath = fldPath.Child("schedule")
+
+	var errs field.ErrorList
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Child("timezone"), schedule.Timezone, err.Error()))
+	}
+
+	if len(schedule.Windows) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("windows"), "at least one window is required"))
+	}
+
+	for i, window := range schedule.Windows {
+		if _, err := window.compile(); err != nil {
+			value := window.Cron
+			if value == "" {
+				value = window.Start + "-" + window.End
+			}
+
+			errs = append(errs, field.Invalid(fldPath.Child("windows").Index(i), value, err.Error()))
+		}
+	}
+
+	return errs.ToAggregate()
+}
+
+// validateExpiry ensures the expiry gives at least one of after, afterMatches and inactiveFor.
+func (r *Feed) validateExpiry(fldPath *field.Path) error {
+	expiry := r.Spec.Expiry
+	if expiry == nil {
+		return nil
+	}
+
+	fldPath = fldPath.Child("expiry")
+	switch {
+	case expiry.After == nil && expiry.AfterMatches == nil && expiry.InactiveFor == nil:
+		return field.Required(fldPath.Child("after"), "one of after, afterMatches or inactiveFor is required")
+	case expiry.AfterMatches != nil && *expiry.AfterMatches < 1:
+		return field.Invalid(fldPath.Child("afterMatches"), *expiry.AfterMatches, "must be positive")
+	case expiry.InactiveFor != nil && expiry.InactiveFor.Duration <= 0:
+		return field.Invalid(fldPath.Child("inactiveFor"), expiry.InactiveFor.Duration.String(), "must be positive")
+	default:
+		return nil
+	}
+}
+
+// validateAuthentication ensures exactly one of authSecretRef and accountRef is given.
+func (r *Feed) validateAuthentication(fldPath *field.Path) error {
+	return validateAuthenticationRefs(fldPath, r.Spec.AuthSecretRef, r.Spec.AccountRef)
+}
+
+// validateAuthenticationRefs ensures exactly one of given authSecretRef and accountRef is set.
+func validateAuthenticationRefs(fldPath *field.Path, authSecretRef *AuthSecretReference, accountRef *AccountReference) error {
+	switch {
+	case authSecretRef == nil && accountRef == nil:
+		return field.Required(fldPath.Child("authSecretRef"), "one of authSecretRef or accountRef is required")
+	case authSecretRef != nil && accountRef != nil:
+		return field.Forbidden(fldPath.Child("accountRef"), "accountRef cannot be used along with authSecretRef")
+	default:
+		return nil
+	}
+}
+
+// validateRSSSource ensures the URL is given either in rss_source_url, in rssSourceURLFrom,
+// or as a rss_source_url template whose passkey is read from rssSourceURLFrom.
+func (r *Feed) validateRSSSource(fldPath *field.Path) error {
+	u := r.Spec.RssSourceURL
+	hasPlaceholder := strings.Contains(u, PasskeyPlaceholder)
+
+	switch {
+	case r.Spec.RssSourceURLFrom == nil && u == "":
+		return field.Required(fldPath.Child("rss_source_url"), "one of rss_source_url or rssSourceURLFrom is required")
+	case r.Spec.RssSourceURLFrom == nil && hasPlaceholder:
+		return field.Required(fldPath.Child("rssSourceURLFrom"), "rssSourceURLFrom is required to replace "+PasskeyPlaceholder)
+	case r.Spec.RssSourceURLFrom != nil && u != "" && !hasPlaceholder:
+		return field.Forbidden(fldPath.Child("rss_source_url"), "rss_source_url cannot be used along with rssSourceURLFrom unless it contains "+PasskeyPlaceholder)
+	case u == "":
+		return nil // the whole URL is read from the secret
+	}
+
+	return r.validateRSSSourceURL(strings.ReplaceAll(u, PasskeyPlaceholder, "passkey"), fldPath.Child("rss_source_url"))
 }
 
 func (r *Feed) validateRSSSourceURL(u string, fldPath *field.Path) error {
-	if _, err := url.ParseRequestURI(u); err != nil {
+	parsed, err := url.ParseRequestURI(u)
+	if err != nil {
 		return field.Invalid(fldPath, u, "invalid URL provided")
 	}
 
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return field.Invalid(fldPath, u, "only http and https URLs are supported")
+	}
+
 	return nil
 }
@@ -47,6 +47,15 @@ func TestFeed_validateRSSSourceURL(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:   "unsupported scheme",
+			fields: fields{},
+			args: args{
+				u:       "file:///etc/passwd",
+				fldPath: field.NewPath("spec").Child("rss_source_url"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -63,6 +72,354 @@ func TestFeed_validateRSSSourceURL(t *testing.T) {
 	}
 }
 
+func TestFeed_validateAuthentication(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    FeedSpec
+		wantErr bool
+	}{
+		{
+			name:    "secret reference",
+			spec:    FeedSpec{AuthSecretRef: &AuthSecretReference{Name: "putio-token", Key: "token"}},
+			wantErr: false,
+		},
+		{
+			name:    "account reference",
+			spec:    FeedSpec{AccountRef: &AccountReference{Name: "shared"}},
+			wantErr: false,
+		},
+		{
+			name:    "missing authentication",
+			spec:    FeedSpec{},
+			wantErr: true,
+		},
+		{
+			name: "both references",
+			spec: FeedSpec{
+				AuthSecretRef: &AuthSecretReference{Name: "putio-token", Key: "token"},
+				AccountRef:    &AccountReference{Name: "shared"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Feed{Spec: tt.spec}
+			if err := r.validateAuthentication(field.NewPath("spec")); (err != nil) != tt.wantErr {
+				t.Errorf("validateAuthentication() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeed_validateKeywords(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    FeedSpec
+		wantErr string
+	}{
+		{
+			name: "keyword expression",
+			spec: FeedSpec{Keyword: "House.of.the.Dragon&1080p,House.of.the.Dragon&2160p", UnwantedKeywords: "HDR"},
+		},
+		{
+			name: "structured keywords",
+			spec: FeedSpec{Keywords: &Keywords{AllOf: []string{"House.of.the.Dragon"}, AnyOf: []string{"1080p"}, NoneOf: []string{"HDR"}}},
+		},
+		{
+			name:    "missing keyword",
+			spec:    FeedSpec{},
+			wantErr: "spec.keyword: Required value: one of keyword or keywords is required",
+		},
+		{
+			name:    "keyword and keywords",
+			spec:    FeedSpec{Keyword: "foo", Keywords: &Keywords{AllOf: []string{"foo"}}},
+			wantErr: "spec.keywords: Forbidden: keywords cannot be used along with keyword",
+		},
+		{
+			name:    "malformed keyword",
+			spec:    FeedSpec{Keyword: "foo,,bar"},
+			wantErr: `spec.keyword: Invalid value: "foo,,bar": expected a keyword between "," and "," at offset 4`,
+		},
+		{
+			name:    "malformed unwanted keywords",
+			spec:    FeedSpec{Keyword: "foo", UnwantedKeywords: "bar,"},
+			wantErr: `spec.unwanted_keywords: Invalid value: "bar,": expected a keyword after "," at offset 4`,
+		},
+		{
+			name:    "empty structured keywords",
+			spec:    FeedSpec{Keywords: &Keywords{NoneOf: []string{"HDR"}}},
+			wantErr: "spec.keywords.allOf: Required value: at least one of allOf or anyOf is required",
+		},
+		{
+			name:    "none of and unwanted keywords",
+			spec:    FeedSpec{Keywords: &Keywords{AllOf: []string{"foo"}, NoneOf: []string{"HDR"}}, UnwantedKeywords: "x265"},
+			wantErr: "spec.keywords.noneOf: Forbidden: noneOf cannot be used along with unwanted_keywords",
+		},
+		{
+			name:    "separator in structured keyword",
+			spec:    FeedSpec{Keywords: &Keywords{AllOf: []string{"foo", "bar&baz"}, AnyOf: []string{" "}}},
+			wantErr: `[spec.keywords.allOf[1]: Invalid value: "bar&baz": unexpected "&" in keyword at offset 3, spec.keywords.anyOf[0]: Invalid value: " ": empty keyword at offset 0]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Feed{Spec: tt.spec}
+			err := r.validateKeywords(field.NewPath("spec"))
+			if (err != nil) != (tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("validateKeywords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeed_validateParentDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    FeedSpec
+		wantErr bool
+	}{
+		{
+			name:    "root directory",
+			spec:    FeedSpec{},
+			wantErr: false,
+		},
+		{
+			name:    "folder ID",
+			spec:    FeedSpec{ParentDirID: new(uint)},
+			wantErr: false,
+		},
+		{
+			name:    "folder reference",
+			spec:    FeedSpec{ParentFolderRef: &FolderReference{Name: "house-of-the-dragon"}},
+			wantErr: false,
+		},
+		{
+			name:    "folder path",
+			spec:    FeedSpec{ParentDirPath: "TV Shows/House of the Dragon"},
+			wantErr: false,
+		},
+		{
+			name:    "folder ID and reference",
+			spec:    FeedSpec{ParentDirID: new(uint), ParentFolderRef: &FolderReference{Name: "house-of-the-dragon"}},
+			wantErr: true,
+		},
+		{
+			name:    "folder ID and path",
+			spec:    FeedSpec{ParentDirID: new(uint), ParentDirPath: "TV Shows/House of the Dragon"},
+			wantErr: true,
+		},
+		{
+			name:    "folder reference and path",
+			spec:    FeedSpec{ParentFolderRef: &FolderReference{Name: "house-of-the-dragon"}, ParentDirPath: "TV Shows/House of the Dragon"},
+			wantErr: true,
+		},
+		{
+			name:    "folder to create",
+			spec:    FeedSpec{CreateParentDir: "TV Shows/House of the Dragon"},
+			wantErr: false,
+		},
+		{
+			name:    "folder to create and path",
+			spec:    FeedSpec{ParentDirPath: "TV Shows", CreateParentDir: "TV Shows/House of the Dragon"},
+			wantErr: true,
+		},
+		{
+			name:    "root directory to create",
+			spec:    FeedSpec{CreateParentDir: "/"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Feed{Spec: tt.spec}
+			if err := r.validateParentDir(field.NewPath("spec")); (err != nil) != tt.wantErr {
+				t.Errorf("validateParentDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeed_validateSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    FeedSpec
+		wantErr bool
+	}{
+		{
+			name:    "no schedule",
+			spec:    FeedSpec{},
+			wantErr: false,
+		},
+		{
+			name: "time range",
+			spec: FeedSpec{Schedule: &FeedSchedule{
+				Timezone: "Europe/Paris",
+				Windows:  []ScheduleWindow{{Days: []Weekday{"Monday", "Tuesday"}, Start: "20:00", End: "02:00"}},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "cron",
+			spec: FeedSpec{Schedule: &FeedSchedule{
+				Windows: []ScheduleWindow{{Cron: "0 20 * * wed", Duration: &v1.Duration{Duration: 6 * time.Hour}}},
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "no window",
+			spec:    FeedSpec{Schedule: &FeedSchedule{}},
+			wantErr: true,
+		},
+		{
+			name: "unknown timezone",
+			spec: FeedSpec{Schedule: &FeedSchedule{
+				Timezone: "Europe/Gotham",
+				Windows:  []ScheduleWindow{{Start: "20:00", End: "22:00"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "cron without duration",
+			spec: FeedSpec{Schedule: &FeedSchedule{
+				Windows: []ScheduleWindow{{Cron: "0 20 * * wed"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid cron",
+			spec: FeedSpec{Schedule: &FeedSchedule{
+				Windows: []ScheduleWindow{{Cron: "0 25 * * wed", Duration: &v1.Duration{Duration: time.Hour}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "cron and time range",
+			spec: FeedSpec{Schedule: &FeedSchedule{
+				Windows: []ScheduleWindow{{Cron: "0 20 * * wed", Duration: &v1.Duration{Duration: time.Hour}, Start: "20:00", End: "22:00"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "start without end",
+			spec: FeedSpec{Schedule: &FeedSchedule{
+				Windows: []ScheduleWindow{{Start: "20:00"}},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Feed{Spec: tt.spec}
+			if err := r.validateSchedule(field.NewPath("spec")); (err != nil) != tt.wantErr {
+				t.Errorf("validateSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeed_validateExpiry(t *testing.T) {
+	matches := func(v int32) *int32 { return &v }
+
+	tests := []struct {
+		name    string
+		spec    FeedSpec
+		wantErr bool
+	}{
+		{
+			name:    "no expiry",
+			spec:    FeedSpec{},
+			wantErr: false,
+		},
+		{
+			name:    "expiry date",
+			spec:    FeedSpec{Expiry: &FeedExpiry{After: &v1.Time{Time: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)}}},
+			wantErr: false,
+		},
+		{
+			name:    "matches and inactivity",
+			spec:    FeedSpec{Expiry: &FeedExpiry{AfterMatches: matches(10), InactiveFor: &v1.Duration{Duration: 720 * time.Hour}}},
+			wantErr: false,
+		},
+		{
+			name:    "no condition",
+			spec:    FeedSpec{Expiry: &FeedExpiry{OnExpiry: ExpiryActionDelete}},
+			wantErr: true,
+		},
+		{
+			name:    "no match",
+			spec:    FeedSpec{Expiry: &FeedExpiry{AfterMatches: matches(0)}},
+			wantErr: true,
+		},
+		{
+			name:    "negative inactivity",
+			spec:    FeedSpec{Expiry: &FeedExpiry{InactiveFor: &v1.Duration{Duration: -time.Hour}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Feed{Spec: tt.spec}
+			if err := r.validateExpiry(field.NewPath("spec")); (err != nil) != tt.wantErr {
+				t.Errorf("validateExpiry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeed_validateRSSSource(t *testing.T) {
+	secretRef := &RssSourceURLSource{SecretKeyRef: SecretKeyReference{Name: "tracker", Key: "passkey"}}
+	tests := []struct {
+		name    string
+		spec    FeedSpec
+		wantErr bool
+	}{
+		{
+			name:    "literal URL",
+			spec:    FeedSpec{RssSourceURL: "https://example.com/rss?passkey=secret"},
+			wantErr: false,
+		},
+		{
+			name:    "URL from secret",
+			spec:    FeedSpec{RssSourceURLFrom: secretRef},
+			wantErr: false,
+		},
+		{
+			name:    "passkey from secret",
+			spec:    FeedSpec{RssSourceURL: "https://example.com/rss?passkey=${passkey}", RssSourceURLFrom: secretRef},
+			wantErr: false,
+		},
+		{
+			name:    "no URL",
+			spec:    FeedSpec{},
+			wantErr: true,
+		},
+		{
+			name:    "passkey without secret",
+			spec:    FeedSpec{RssSourceURL: "https://example.com/rss?passkey=${passkey}"},
+			wantErr: true,
+		},
+		{
+			name:    "literal URL and secret",
+			spec:    FeedSpec{RssSourceURL: "https://example.com/rss", RssSourceURLFrom: secretRef},
+			wantErr: true,
+		},
+		{
+			name:    "invalid URL template",
+			spec:    FeedSpec{RssSourceURL: "${passkey}", RssSourceURLFrom: secretRef},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Feed{Spec: tt.spec}
+			if err := r.validateRSSSource(field.NewPath("spec")); (err != nil) != tt.wantErr {
+				t.Errorf("validateRSSSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 var _ = Describe("Feed webhook", func() {
 	// Define utility constants for object names and testing timeouts/durations and intervals.
 	const (
@@ -94,7 +451,7 @@ var _ = Describe("Feed webhook", func() {
 					Keyword:              "foo",
 					UnwantedKeywords:     "",
 					Paused:               new(bool),
-					AuthSecretRef: AuthSecretReference{
+					AuthSecretRef: &AuthSecretReference{
 						Name: "putio-token",
 						Key:  "token",
 					},
@@ -124,7 +481,7 @@ var _ = Describe("Feed webhook", func() {
 					Keyword:              "foo",
 					UnwantedKeywords:     "",
 					Paused:               new(bool),
-					AuthSecretRef: AuthSecretReference{
+					AuthSecretRef: &AuthSecretReference{
 						Name: "putio-token",
 						Key:  "token",
 					},
@@ -157,7 +514,7 @@ var _ = Describe("Feed webhook", func() {
 					Keyword:              "foo",
 					UnwantedKeywords:     "",
 					Paused:               nil,
-					AuthSecretRef: AuthSecretReference{
+					AuthSecretRef: &AuthSecretReference{
 						Name: "foo",
 						Key:  "bar",
 					},
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var errEmptyTemplateKeyword = errors.New("rendered keyword is empty")
+
+// feedTemplateData is given to the keyword template of a FeedTemplate for each instance.
+type feedTemplateData struct {
+	Name   string
+	Title  string
+	Show   string
+	Season string
+	Values map[string]string
+}
+
+// RenderFeeds returns the Feed of each instance of the template, sorted by name.
+func (r *FeedTemplate) RenderFeeds() ([]*Feed, error) {
+	keywordTemplate, err := r.parseKeywordTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	feeds := make([]*Feed, 0, len(r.Spec.Instances))
+	for _, instance := range r.Spec.Instances {
+		feed, err := r.renderFeed(keywordTemplate, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		feeds = append(feeds, feed)
+	}
+
+	sort.Slice(feeds, func(i, j int) bool { return feeds[i].Name < feeds[j].Name })
+	return feeds, nil
+}
+
+func (r *FeedTemplate) parseKeywordTemplate() (*template.Template, error) {
+	keywordTemplate, err := template.New("keyword").Option("missingkey=error").Parse(r.Spec.KeywordTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse keyword template: %w", err)
+	}
+
+	return keywordTemplate, nil
+}
+
+// renderFeed returns the Feed of given instance, with the webhook defaults applied.
+func (r *FeedTemplate) renderFeed(keywordTemplate *template.Template, instance FeedTemplateInstance) (*Feed, error) {
+	spec := r.Spec
+	title := instance.Title
+	if title == "" {
+		title = instance.Show
+	}
+
+	keyword := instance.Keyword
+	if keyword == "" {
+		var b strings.Builder
+		if err := keywordTemplate.Execute(&b, feedTemplateData{
+			Name:   instance.Name,
+			Title:  title,
+			Show:   instance.Show,
+			Season: instance.Season,
+			Values: instance.Values,
+		}); err != nil {
+			return nil, fmt.Errorf("cannot render keyword of instance %q: %w", instance.Name, err)
+		}
+
+		keyword = b.String()
+	}
+
+	if keyword == "" {
+		return nil, fmt.Errorf("instance %q: %w", instance.Name, errEmptyTemplateKeyword)
+	}
+
+	paused := spec.Paused
+	if instance.Paused != nil {
+		paused = instance.Paused
+	}
+
+	schedule := spec.Schedule
+	if instance.Schedule != nil {
+		schedule = instance.Schedule
+	}
+
+	feed := &Feed{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.FeedName(instance),
+			Namespace: r.Namespace,
+		},
+		Spec: FeedSpec{
+			Title:                title,
+			RssSourceURL:         spec.RssSourceURL,
+			RssSourceURLFrom:     spec.RssSourceURLFrom,
+			ParentDirID:          instance.ParentDirID,
+			ParentFolderRef:      instance.ParentFolderRef,
+			ParentDirPath:        instance.ParentDirPath,
+			CreateParentDir:      instance.CreateParentDir,
+			DeleteOldFiles:       spec.DeleteOldFiles,
+			DontProcessWholeFeed: spec.DontProcessWholeFeed,
+			Keyword:              keyword,
+			UnwantedKeywords:     spec.UnwantedKeywords,
+			Paused:               paused,
+			Schedule:             schedule,
+			DeletionPolicy:       spec.DeletionPolicy,
+			AuthSecretRef:        spec.AuthSecretRef,
+			AccountRef:           spec.AccountRef,
+		},
+	}
+
+	// apply the webhook defaults, so that unchanged feeds are not updated on every reconciliation
+	feed.Default()
+	return feed, nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FeedTemplateInstance is a Feed generated from a FeedTemplate.
+type FeedTemplateInstance struct {
+	// Name of the instance, unique in the template. The generated Feed is named after the template and the instance.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Title of the RSS feed as will appear on the site. Default to show.
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Show rendered in keyword_template as {{.Show}}.
+	// +optional
+	Show string `json:"show,omitempty"`
+
+	// Season rendered in keyword_template as {{.Season}}.
+	// +optional
+	Season string `json:"season,omitempty"`
+
+	// Additional values rendered in keyword_template as {{.Values.key}}.
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
+
+	// Keyword of the feed, instead of rendering keyword_template.
+	// +optional
+	Keyword string `json:"keyword,omitempty"`
+
+	// The file ID of the folder to place the RSS feed files in. Default to the root directory (0).
+	// Mutually exclusive with parentFolderRef, parent_dir_path and createParentDir.
+	// +optional
+	ParentDirID *uint `json:"parent_dir_id,omitempty"`
+
+	// Reference to a Folder of the same namespace to place the RSS feed files in.
+	// Mutually exclusive with parent_dir_id, parent_dir_path and createParentDir.
+	// +optional
+	ParentFolderRef *FolderReference `json:"parentFolderRef,omitempty"`
+
+	// Slash-separated path of an existing folder to place the RSS feed files in.
+	// Mutually exclusive with parent_dir_id, parentFolderRef and createParentDir.
+	// +optional
+	ParentDirPath string `json:"parent_dir_path,omitempty"`
+
+	// Slash-separated path of the folder to place the RSS feed files in, created when it does not exist yet.
+	// Mutually exclusive with parent_dir_id, parentFolderRef and parent_dir_path.
+	// +optional
+	CreateParentDir string `json:"createParentDir,omitempty"`
+
+	// Should the RSS feed be created in the paused state. Default to the template paused.
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
+
+	// Recurring windows during which the RSS feed is active. Default to the template schedule.
+	// +optional
+	Schedule *FeedSchedule `json:"schedule,omitempty"`
+}
+
+// FeedTemplateSpec defines the desired state of FeedTemplate.
+type FeedTemplateSpec struct {
+	// The URL of the RSS feed to be watched by every instance, see the Feed rss_source_url.
+	// +optional
+	RssSourceURL string `json:"rss_source_url,omitempty"`
+
+	// Secret holding the URL of the RSS feed or its passkey, see the Feed rssSourceURLFrom.
+	// +optional
+	RssSourceURLFrom *RssSourceURLSource `json:"rssSourceURLFrom,omitempty"`
+
+	// Go template rendering the keyword of each instance, e.g. "{{.Show}}.S{{.Season}}&1080p".
+	// Required unless every instance sets its keyword.
+	// +optional
+	KeywordTemplate string `json:"keyword_template,omitempty"`
+
+	// No items with titles that contain any of these words will be transferred (comma-separated list of words).
+	// +optional
+	UnwantedKeywords string `json:"unwanted_keywords,omitempty"`
+
+	// Should old files in the folder be deleted when space is low. Default to false.
+	// +optional
+	DeleteOldFiles *bool `json:"delete_old_files,omitempty"`
+
+	// Should the current items in the feed, at creation time, be ignored.
+	// +optional
+	DontProcessWholeFeed *bool `json:"dont_process_whole_feed,omitempty"`
+
+	// Should the RSS feeds be created in the paused state. Default to false.
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
+
+	// Recurring windows during which the RSS feeds are active, see the Feed schedule.
+	// +optional
+	Schedule *FeedSchedule `json:"schedule,omitempty"`
+
+	// What happens to the Put.io feed when a generated Feed is deleted. Default to Delete.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Authentication reference to Put.io token in a secret. Mutually exclusive with accountRef.
+	// +optional
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+
+	// Reference to a cluster-wide PutioAccount allowing this namespace. Mutually exclusive with authSecretRef.
+	// +optional
+	AccountRef *AccountReference `json:"accountRef,omitempty"`
+
+	// Feeds to generate. Feeds of removed instances are deleted.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Instances []FeedTemplateInstance `json:"instances,omitempty"`
+}
+
+// FeedTemplateStatus defines the observed state of FeedTemplate.
+type FeedTemplateStatus struct {
+	// Names of the generated Feeds.
+	// +optional
+	Feeds []string `json:"feeds,omitempty"`
+
+	// Conditions represent the latest available observations of a FeedTemplate state
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Keyword template",type=string,JSONPath=".spec.keyword_template"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type == "Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="URL",type=string,priority=1,JSONPath=".spec.rss_source_url"
+
+// FeedTemplate is the Schema to generate Feeds sharing the same RSS feed and settings.
+type FeedTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FeedTemplateSpec   `json:"spec,omitempty"`
+	Status FeedTemplateStatus `json:"status,omitempty"`
+}
+
+// FeedName returns the name of the Feed generated for given instance.
+func (r *FeedTemplate) FeedName(instance FeedTemplateInstance) string {
+	return r.Name + "-" + instance.Name
+}
+
+//+kubebuilder:object:root=true
+
+// FeedTemplateList contains a list of FeedTemplate.
+type FeedTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FeedTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FeedTemplate{}, &FeedTemplateList{})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var feedtemplatelog = logf.Log.WithName("feedtemplate-resource")
+
+func (r *FeedTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FeedTemplate.SetupWebhookWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-putio-skynewz-dev-v1alpha1-feedtemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=putio.skynewz.dev,resources=feedtemplates,verbs=create;update,versions=v1alpha1,name=vfeedtemplate.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &FeedTemplate{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *FeedTemplate) ValidateCreate() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FeedTemplate.ValidateCreate")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	feedtemplatelog.Info("validate create", "name", r.Name)
+	return r.validateFeedTemplateSpec()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *FeedTemplate) ValidateUpdate(_ runtime.Object) error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FeedTemplate.ValidateUpdate")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	feedtemplatelog.Info("validate update", "name", r.Name)
+	return r.validateFeedTemplateSpec()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *FeedTemplate) ValidateDelete() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FeedTemplate.ValidateDelete")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	feedtemplatelog.Info("validate delete", "name", r.Name)
+	return nil // nothing to validate on deletion
+}
+
+// validateFeedTemplateSpec renders every instance and validates the resulting Feed as its own webhook would,
+// so that a template is not accepted when the controller cannot generate its Feeds.
+func (r *FeedTemplate) validateFeedTemplateSpec() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FeedTemplate.validateFeedTemplateSpec")
+	defer span.End()
+
+	fldPath := field.NewPath("spec")
+
+	// validate authentication once, rather than for every instance
+	if err := validateAuthenticationRefs(fldPath, r.Spec.AuthSecretRef, r.Spec.AccountRef); err != nil {
+		return err
+	}
+
+	keywordTemplate, err := r.parseKeywordTemplate()
+	if err != nil {
+		return field.Invalid(fldPath.Child("keyword_template"), r.Spec.KeywordTemplate, err.Error())
+	}
+
+	var errs field.ErrorList
+	seen := make(map[string]bool, len(r.Spec.Instances))
+	for i, instance := range r.Spec.Instances {
+		fldPath := fldPath.Child("instances").Index(i)
+		if seen[instance.Name] {
+			errs = append(errs, field.Duplicate(fldPath.Child("name"), instance.Name))
+			continue
+		}
+
+		seen[instance.Name] = true
+
+		feed, err := r.renderFeed(keywordTemplate, instance)
+		if err != nil {
+			errs = append(errs, field.Invalid(fldPath, instance.Name, err.Error()))
+			continue
+		}
+
+		if err := feed.validateFeedSpec(); err != nil {
+			errs = append(errs, field.Invalid(fldPath, instance.Name, err.Error()))
+		}
+	}
+
+	return errs.ToAggregate()
+}
@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestFeedTemplate_validateFeedTemplateSpec(t *testing.T) {
+	makeSpec := func(keywordTemplate string, instances ...FeedTemplateInstance) FeedTemplateSpec {
+		return FeedTemplateSpec{
+			RssSourceURL:    "https://example.com/rss",
+			KeywordTemplate: keywordTemplate,
+			AuthSecretRef:   &AuthSecretReference{Name: "putio-token", Key: "token"},
+			Instances:       instances,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		spec    FeedTemplateSpec
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			spec: makeSpec("{{.Show}}.S{{.Season}}&1080p",
+				FeedTemplateInstance{Name: "hotd", Show: "House.of.the.Dragon", Season: "01"},
+				FeedTemplateInstance{Name: "rop", Keyword: "Rings.of.Power,LOTR"},
+			),
+			wantErr: false,
+		},
+		{
+			name:    "no instances",
+			spec:    makeSpec("{{.Show}}"),
+			wantErr: false,
+		},
+		{
+			name: "missing authentication",
+			spec: FeedTemplateSpec{
+				RssSourceURL:    "https://example.com/rss",
+				KeywordTemplate: "{{.Show}}",
+				Instances:       []FeedTemplateInstance{{Name: "a", Show: "A"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			spec:    makeSpec("{{.Show", FeedTemplateInstance{Name: "a", Show: "A"}),
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			spec:    makeSpec("{{.Values.quality}}", FeedTemplateInstance{Name: "a", Show: "A"}),
+			wantErr: true,
+		},
+		{
+			name:    "empty keyword",
+			spec:    makeSpec("", FeedTemplateInstance{Name: "a", Show: "A"}),
+			wantErr: true,
+		},
+		{
+			name:    "rendered keyword is not a valid expression",
+			spec:    makeSpec("{{.Show}}&", FeedTemplateInstance{Name: "a", Show: "A"}),
+			wantErr: true,
+		},
+		{
+			name:    "instance keyword is not a valid expression",
+			spec:    makeSpec("{{.Show}}", FeedTemplateInstance{Name: "a", Keyword: "A,,B"}),
+			wantErr: true,
+		},
+		{
+			name: "duplicate instance names",
+			spec: makeSpec("{{.Show}}",
+				FeedTemplateInstance{Name: "a", Show: "A"},
+				FeedTemplateInstance{Name: "a", Show: "B"},
+			),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &FeedTemplate{Spec: tt.spec}
+			r.Name = "shows"
+			if err := r.validateFeedTemplateSpec(); (err != nil) != tt.wantErr {
+				t.Errorf("validateFeedTemplateSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
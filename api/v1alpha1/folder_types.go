@@ -0,0 +1,83 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FolderReference references a Folder in the same namespace.
+type FolderReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// FolderSpec defines the desired state of Folder.
+type FolderSpec struct {
+	// +kubebuilder:validation:MinLength:=1
+	// Slash-separated path of the folder from the root directory, e.g. "TV Shows/House of the Dragon".
+	// Missing intermediate folders are created.
+	Path string `json:"path"`
+
+	// Authentication reference to Put.io token in a secret. Mutually exclusive with accountRef.
+	// +optional
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+
+	// Reference to a cluster-wide PutioAccount allowing this namespace. Mutually exclusive with authSecretRef.
+	// +optional
+	AccountRef *AccountReference `json:"accountRef,omitempty"`
+}
+
+// FolderStatus defines the observed state of Folder.
+type FolderStatus struct {
+	// Put.io file ID of the folder.
+	// +optional
+	FileID *uint `json:"file_id,omitempty"`
+
+	// Conditions represent the latest available observations of a Folder state
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Path",type=string,JSONPath=".spec.path"
+// +kubebuilder:printcolumn:name="File ID",type=integer,JSONPath=".status.file_id"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type == "Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Folder is the Schema to ensure a Put.io folder exists at a given path.
+// Deleting a Folder leaves the Put.io folder and its content untouched.
+type Folder struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FolderSpec   `json:"spec,omitempty"`
+	Status FolderStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FolderList contains a list of Folder.
+type FolderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Folder `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Folder{}, &FolderList{})
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var folderlog = logf.Log.WithName("folder-resource")
+
+func (r *Folder) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.Folder.SetupWebhookWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-putio-skynewz-dev-v1alpha1-folder,mutating=false,failurePolicy=fail,sideEffects=None,groups=putio.skynewz.dev,resources=folders,verbs=create;update,versions=v1alpha1,name=vfolder.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Folder{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *Folder) ValidateCreate() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.Folder.ValidateCreate")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	folderlog.Info("validate create", "name", r.Name)
+	return r.validateFolderSpec()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *Folder) ValidateUpdate(_ runtime.Object) error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.Folder.ValidateUpdate")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	folderlog.Info("validate update", "name", r.Name)
+	return r.validateFolderSpec()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *Folder) ValidateDelete() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.Folder.ValidateDelete")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	folderlog.Info("validate delete", "name", r.Name)
+	return nil // nothing to validate on deletion
+}
+
+func (r *Folder) validateFolderSpec() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.Folder.validateFolderSpec")
+	defer span.End()
+
+	// validate authentication
+	return validateAuthenticationRefs(field.NewPath("spec"), r.Spec.AuthSecretRef, r.Spec.AccountRef)
+}
@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestFolder_validateFolderSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    FolderSpec
+		wantErr bool
+	}{
+		{
+			name:    "secret reference",
+			spec:    FolderSpec{Path: "TV Shows", AuthSecretRef: &AuthSecretReference{Name: "putio-token", Key: "token"}},
+			wantErr: false,
+		},
+		{
+			name:    "account reference",
+			spec:    FolderSpec{Path: "TV Shows", AccountRef: &AccountReference{Name: "shared"}},
+			wantErr: false,
+		},
+		{
+			name:    "missing authentication",
+			spec:    FolderSpec{Path: "TV Shows"},
+			wantErr: true,
+		},
+		{
+			name: "both references",
+			spec: FolderSpec{
+				Path:          "TV Shows",
+				AuthSecretRef: &AuthSecretReference{Name: "putio-token", Key: "token"},
+				AccountRef:    &AccountReference{Name: "shared"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Folder{Spec: tt.spec}
+			if err := r.validateFolderSpec(); (err != nil) != tt.wantErr {
+				t.Errorf("validateFolderSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RetentionAction tells what happens to the files a FolderRetentionPolicy prunes.
+// +kubebuilder:validation:Enum=Trash;Delete
+type RetentionAction string
+
+const (
+	// RetentionActionTrash moves pruned files to the Put.io trash, from where they can be restored.
+	RetentionActionTrash RetentionAction = "Trash"
+
+	// RetentionActionDelete deletes pruned files permanently.
+	RetentionActionDelete RetentionAction = "Delete"
+)
+
+// FolderRetentionPolicySpec defines the desired state of FolderRetentionPolicy.
+type FolderRetentionPolicySpec struct {
+	// File ID of the folder to prune. Mutually exclusive with path.
+	// +optional
+	FolderID *uint `json:"folder_id,omitempty"`
+
+	// Slash-separated path of the folder to prune, e.g. "TV Shows/House of the Dragon". Mutually exclusive with folder_id.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Files and folders directly in the folder older than this are pruned, e.g. "720h" for 30 days.
+	// +optional
+	MaxAge *metav1.Duration `json:"max_age,omitempty"`
+
+	// The oldest files and folders are pruned until the folder content does not exceed this size, e.g. "500Gi".
+	// +optional
+	MaxTotalSize *resource.Quantity `json:"max_total_size,omitempty"`
+
+	// Only the given number of most recent files and folders are kept.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepLast *int32 `json:"keep_last,omitempty"`
+
+	// Only files and folders whose name matches one of these globs are pruned, e.g. "*.mkv". Default to all of them.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Files and folders whose name matches one of these globs are never pruned.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// What happens to pruned files: moved to the Trash, or permanently Deleted. Default to Trash.
+	// +kubebuilder:default=Trash
+	// +optional
+	Action RetentionAction `json:"action,omitempty"`
+
+	// How often the policy is enforced. Default to one hour.
+	// +kubebuilder:default="1h"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// Only list in status the files the policy would prune, without pruning them.
+	// +optional
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Authentication reference to Put.io token in a secret. Mutually exclusive with accountRef.
+	// +optional
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+
+	// Reference to a cluster-wide PutioAccount allowing this namespace. Mutually exclusive with authSecretRef.
+	// +optional
+	AccountRef *AccountReference `json:"accountRef,omitempty"`
+}
+
+// PrunedFile is a file or folder pruned by a FolderRetentionPolicy.
+type PrunedFile struct {
+	// Put.io file ID.
+	ID uint `json:"id"`
+
+	// Name of the file or folder.
+	Name string `json:"name"`
+
+	// Size in bytes.
+	Size int64 `json:"size"`
+
+	// When the file was created at Put.io.
+	// +optional
+	CreatedAt *metav1.Time `json:"created_at,omitempty"`
+
+	// Rule which pruned the file: MaxAge, MaxTotalSize or KeepLast.
+	Reason string `json:"reason"`
+}
+
+// FolderRetentionPolicyStatus defines the observed state of FolderRetentionPolicy.
+type FolderRetentionPolicyStatus struct {
+	// Put.io file ID of the pruned folder.
+	// +optional
+	FolderID *uint `json:"folder_id,omitempty"`
+
+	// Last time the policy was enforced, successfully or not.
+	// +optional
+	LastRun *metav1.Time `json:"last_run,omitempty"`
+
+	// Files and folders pruned on the last run, or which would be on dry run. At most 100 are listed.
+	// +optional
+	Pruned []PrunedFile `json:"pruned,omitempty"`
+
+	// Number of files and folders pruned on the last run, or which would be on dry run.
+	// +optional
+	PrunedCount int `json:"pruned_count,omitempty"`
+
+	// Bytes freed on the last run, or which would be on dry run.
+	// +optional
+	PrunedSize int64 `json:"pruned_size,omitempty"`
+
+	// Generation of the spec enforced on the last run.
+	// +optional
+	ObservedGeneration int64 `json:"observed_generation,omitempty"`
+
+	// Conditions represent the latest available observations of a FolderRetentionPolicy state
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Folder ID",type=integer,JSONPath=".status.folder_id"
+// +kubebuilder:printcolumn:name="Dry run",type=boolean,JSONPath=".spec.dry_run"
+// +kubebuilder:printcolumn:name="Pruned",type=integer,JSONPath=".status.pruned_count"
+// +kubebuilder:printcolumn:name="Last run",type="date",JSONPath=".status.last_run"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type == "Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// FolderRetentionPolicy is the Schema to periodically prune old downloads of a Put.io folder.
+// Deleting a FolderRetentionPolicy leaves the folder content untouched.
+type FolderRetentionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FolderRetentionPolicySpec   `json:"spec,omitempty"`
+	Status FolderRetentionPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FolderRetentionPolicyList contains a list of FolderRetentionPolicy.
+type FolderRetentionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FolderRetentionPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FolderRetentionPolicy{}, &FolderRetentionPolicyList{})
+}
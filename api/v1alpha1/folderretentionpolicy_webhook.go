@@ -0,0 +1,144 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var folderretentionpolicylog = logf.Log.WithName("folderretentionpolicy-resource")
+
+func (r *FolderRetentionPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FolderRetentionPolicy.SetupWebhookWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-putio-skynewz-dev-v1alpha1-folderretentionpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=putio.skynewz.dev,resources=folderretentionpolicies,verbs=create;update,versions=v1alpha1,name=vfolderretentionpolicy.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &FolderRetentionPolicy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *FolderRetentionPolicy) ValidateCreate() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FolderRetentionPolicy.ValidateCreate")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	folderretentionpolicylog.Info("validate create", "name", r.Name)
+	return r.validateFolderRetentionPolicySpec()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *FolderRetentionPolicy) ValidateUpdate(_ runtime.Object) error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FolderRetentionPolicy.ValidateUpdate")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	folderretentionpolicylog.Info("validate update", "name", r.Name)
+	return r.validateFolderRetentionPolicySpec()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *FolderRetentionPolicy) ValidateDelete() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FolderRetentionPolicy.ValidateDelete")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", r.Name))
+	folderretentionpolicylog.Info("validate delete", "name", r.Name)
+	return nil // nothing to validate on deletion
+}
+
+func (r *FolderRetentionPolicy) validateFolderRetentionPolicySpec() error {
+	_, span := tracer.Start(context.Background(), "v1alpha1.FolderRetentionPolicy.validateFolderRetentionPolicySpec")
+	defer span.End()
+
+	// validate folder
+	if err := r.validateFolder(field.NewPath("spec")); err != nil {
+		return err
+	}
+
+	// validate rules
+	if err := r.validateRules(field.NewPath("spec")); err != nil {
+		return err
+	}
+
+	// validate authentication
+	return validateAuthenticationRefs(field.NewPath("spec"), r.Spec.AuthSecretRef, r.Spec.AccountRef)
+}
+
+// validateFolder ensures exactly one of folder_id and path is given, and that it is not the root folder,
+// whose content is rarely meant to be pruned.
+func (r *FolderRetentionPolicy) validateFolder(fldPath *field.Path) error {
+	switch {
+	case r.Spec.FolderID == nil && r.Spec.Path == "":
+		return field.Required(fldPath.Child("folder_id"), "one of folder_id or path is required")
+	case r.Spec.FolderID != nil && r.Spec.Path != "":
+		return field.Forbidden(fldPath.Child("path"), "path cannot be used along with folder_id")
+	case r.Spec.FolderID != nil && *r.Spec.FolderID == 0:
+		return field.Invalid(fldPath.Child("folder_id"), *r.Spec.FolderID, "the root folder cannot be pruned")
+	case r.Spec.FolderID == nil && strings.Trim(r.Spec.Path, "/") == "":
+		return field.Invalid(fldPath.Child("path"), r.Spec.Path, "the root folder cannot be pruned")
+	}
+
+	return nil
+}
+
+// validateRules ensures at least one of max_age, keep_last and max_total_size is given, and that the interval and
+// globs are valid.
+func (r *FolderRetentionPolicy) validateRules(fldPath *field.Path) error {
+	var errs field.ErrorList
+	if r.Spec.MaxAge == nil && r.Spec.KeepLast == nil && r.Spec.MaxTotalSize == nil {
+		errs = append(errs, field.Required(fldPath.Child("max_age"), "one of max_age, keep_last or max_total_size is required"))
+	}
+
+	if r.Spec.MaxAge != nil && r.Spec.MaxAge.Duration <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("max_age"), r.Spec.MaxAge.Duration.String(), "must be positive"))
+	}
+
+	if r.Spec.Interval.Duration <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("interval"), r.Spec.Interval.Duration.String(), "must be positive"))
+	}
+
+	errs = append(errs, validateGlobs(fldPath.Child("include"), r.Spec.Include)...)
+	errs = append(errs, validateGlobs(fldPath.Child("exclude"), r.Spec.Exclude)...)
+	return errs.ToAggregate()
+}
+
+func validateGlobs(fldPath *field.Path, globs []string) field.ErrorList {
+	var errs field.ErrorList
+	for i, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Index(i), glob, err.Error()))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,82 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFolderRetentionPolicy_validateFolderRetentionPolicySpec(t *testing.T) {
+	folderID := uint(42)
+	rootID := uint(0)
+	keepLast := int32(10)
+	maxAge := &metav1.Duration{Duration: 30 * 24 * time.Hour}
+	interval := metav1.Duration{Duration: time.Hour}
+	authSecretRef := &AuthSecretReference{Name: "putio-token", Key: "token"}
+
+	tests := []struct {
+		name    string
+		spec    FolderRetentionPolicySpec
+		wantErr bool
+	}{
+		{
+			name:    "path",
+			spec:    FolderRetentionPolicySpec{Path: "TV Shows", MaxAge: maxAge, Interval: interval, AuthSecretRef: authSecretRef},
+			wantErr: false,
+		},
+		{
+			name:    "folder ID",
+			spec:    FolderRetentionPolicySpec{FolderID: &folderID, KeepLast: &keepLast, Interval: interval, AuthSecretRef: authSecretRef},
+			wantErr: false,
+		},
+		{
+			name:    "no folder",
+			spec:    FolderRetentionPolicySpec{MaxAge: maxAge, Interval: interval, AuthSecretRef: authSecretRef},
+			wantErr: true,
+		},
+		{
+			name:    "folder ID and path",
+			spec:    FolderRetentionPolicySpec{FolderID: &folderID, Path: "TV Shows", MaxAge: maxAge, Interval: interval, AuthSecretRef: authSecretRef},
+			wantErr: true,
+		},
+		{
+			name:    "root folder ID",
+			spec:    FolderRetentionPolicySpec{FolderID: &rootID, MaxAge: maxAge, Interval: interval, AuthSecretRef: authSecretRef},
+			wantErr: true,
+		},
+		{
+			name:    "root path",
+			spec:    FolderRetentionPolicySpec{Path: "/", MaxAge: maxAge, Interval: interval, AuthSecretRef: authSecretRef},
+			wantErr: true,
+		},
+		{
+			name:    "no rule",
+			spec:    FolderRetentionPolicySpec{Path: "TV Shows", Interval: interval, AuthSecretRef: authSecretRef},
+			wantErr: true,
+		},
+		{
+			name:    "no interval",
+			spec:    FolderRetentionPolicySpec{Path: "TV Shows", MaxAge: maxAge, AuthSecretRef: authSecretRef},
+			wantErr: true,
+		},
+		{
+			name:    "invalid glob",
+			spec:    FolderRetentionPolicySpec{Path: "TV Shows", MaxAge: maxAge, Interval: interval, Exclude: []string{"[a-"}, AuthSecretRef: authSecretRef},
+			wantErr: true,
+		},
+		{
+			name:    "missing authentication",
+			spec:    FolderRetentionPolicySpec{Path: "TV Shows", MaxAge: maxAge, Interval: interval},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &FolderRetentionPolicy{Spec: tt.spec}
+			if err := r.validateFolderRetentionPolicySpec(); (err != nil) != tt.wantErr {
+				t.Errorf("validateFolderRetentionPolicySpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
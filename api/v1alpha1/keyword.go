@@ -0,0 +1,162 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Put.io keyword expressions are alternatives separated by commas, each matching when all of its
+// keywords separated by ampersands are in the item title, e.g. "S01E&1080p, S01E&2160p".
+const (
+	keywordOr  = ","
+	keywordAnd = "&"
+)
+
+// KeywordSyntaxError tells where a keyword expression is malformed.
+type KeywordSyntaxError struct {
+	// Offset of the error in the expression, in bytes.
+	Offset int
+	Msg    string
+}
+
+func (e *KeywordSyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Msg, e.Offset)
+}
+
+// KeywordExpression is a parsed Put.io keyword expression: a list of alternatives,
+// each being a list of keywords which must all match.
+type KeywordExpression [][]string
+
+// ParseKeywordExpression parses given Put.io keyword expression.
+// Keywords are trimmed, an empty keyword, e.g. in "foo,,bar" or "foo&", is an error.
+func ParseKeywordExpression(s string) (KeywordExpression, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, &KeywordSyntaxError{Offset: 0, Msg: "empty expression"}
+	}
+
+	var (
+		expr   KeywordExpression
+		offset int
+	)
+
+	for _, alternative := range strings.Split(s, keywordOr) {
+		var keywords []string
+		for _, keyword := range strings.Split(alternative, keywordAnd) {
+			if strings.TrimSpace(keyword) == "" {
+				return nil, &KeywordSyntaxError{Offset: offset, Msg: emptyKeywordMessage(s, offset, keyword)}
+			}
+
+			keywords = append(keywords, strings.TrimSpace(keyword))
+			offset += len(keyword) + len(keywordAnd)
+		}
+
+		expr = append(expr, keywords)
+		offset += len(keywordOr) - len(keywordAnd) // the last keyword is followed by a comma, not an ampersand
+	}
+
+	return expr, nil
+}
+
+// emptyKeywordMessage describes the empty keyword at given offset.
+func emptyKeywordMessage(s string, offset int, keyword string) string {
+	end := offset + len(keyword)
+	switch {
+	case offset == 0:
+		return fmt.Sprintf("expected a keyword before %q", s[end:end+1])
+	case end == len(s):
+		return fmt.Sprintf("expected a keyword after %q", s[offset-1:offset])
+	default:
+		return fmt.Sprintf("expected a keyword between %q and %q", s[offset-1:offset], s[end:end+1])
+	}
+}
+
+// Match tells whether given title contains every keyword of one of the alternatives, ignoring case.
+// An empty expression matches nothing.
+func (e KeywordExpression) Match(title string) bool {
+	title = strings.ToLower(title)
+
+	for _, keywords := range e {
+		matched := true
+		for _, keyword := range keywords {
+			if !strings.Contains(title, strings.ToLower(keyword)) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String formats the expression in the Put.io format.
+func (e KeywordExpression) String() string {
+	alternatives := make([]string, 0, len(e))
+	for _, keywords := range e {
+		alternatives = append(alternatives, strings.Join(keywords, keywordAnd))
+	}
+
+	return strings.Join(alternatives, keywordOr)
+}
+
+// validateKeyword ensures a keyword of the structured form can be written in the Put.io format.
+func validateKeyword(keyword string) error {
+	switch {
+	case strings.TrimSpace(keyword) == "":
+		return &KeywordSyntaxError{Offset: 0, Msg: "empty keyword"}
+	case strings.Contains(keyword, keywordOr):
+		return &KeywordSyntaxError{Offset: strings.Index(keyword, keywordOr), Msg: fmt.Sprintf("unexpected %q in keyword", keywordOr)}
+	case strings.Contains(keyword, keywordAnd):
+		return &KeywordSyntaxError{Offset: strings.Index(keyword, keywordAnd), Msg: fmt.Sprintf("unexpected %q in keyword", keywordAnd)}
+	default:
+		return nil
+	}
+}
+
+// Compile returns the Put.io keyword and unwanted keywords expressions of the structured keywords:
+// every allOf keyword must match along with one of anyOf, and none of noneOf.
+func (k *Keywords) Compile() (keyword, unwanted string) {
+	var (
+		allOf = trimKeywords(k.AllOf)
+		expr  KeywordExpression
+	)
+
+	if len(k.AnyOf) == 0 {
+		expr = KeywordExpression{allOf}
+	}
+
+	for _, alternative := range trimKeywords(k.AnyOf) {
+		keywords := make([]string, 0, len(allOf)+1)
+		expr = append(expr, append(append(keywords, allOf...), alternative))
+	}
+
+	return expr.String(), strings.Join(trimKeywords(k.NoneOf), keywordOr)
+}
+
+func trimKeywords(keywords []string) []string {
+	trimmed := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		trimmed = append(trimmed, strings.TrimSpace(keyword))
+	}
+
+	return trimmed
+}
@@ -0,0 +1,112 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeywordExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    KeywordExpression
+		wantErr string
+	}{
+		{
+			name: "single keyword",
+			s:    "House.of.the.Dragon",
+			want: KeywordExpression{{"House.of.the.Dragon"}},
+		},
+		{
+			name: "alternatives and conjunctions",
+			s:    "House.of.the.Dragon.S01E&.MULTi.1080p, House.of.the.Dragon.S01E & 2160p",
+			want: KeywordExpression{{"House.of.the.Dragon.S01E", ".MULTi.1080p"}, {"House.of.the.Dragon.S01E", "2160p"}},
+		},
+		{
+			name: "keyword with spaces",
+			s:    "house of the dragon",
+			want: KeywordExpression{{"house of the dragon"}},
+		},
+		{
+			name:    "empty expression",
+			s:       "  ",
+			wantErr: "empty expression at offset 0",
+		},
+		{
+			name:    "leading comma",
+			s:       ",foo",
+			wantErr: `expected a keyword before "," at offset 0`,
+		},
+		{
+			name:    "double comma",
+			s:       "foo,,bar",
+			wantErr: `expected a keyword between "," and "," at offset 4`,
+		},
+		{
+			name:    "trailing ampersand",
+			s:       "foo&",
+			wantErr: `expected a keyword after "&" at offset 4`,
+		},
+		{
+			name:    "blank keyword between separators",
+			s:       "foo,bar& ,baz",
+			wantErr: `expected a keyword between "&" and "," at offset 8`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeywordExpression(tt.s)
+			if (err != nil) != (tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("ParseKeywordExpression() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseKeywordExpression() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywordExpression_String(t *testing.T) {
+	expr := KeywordExpression{{"House.of.the.Dragon.S01E", "1080p"}, {"House.of.the.Dragon.S01E", "2160p"}}
+	if got, want := expr.String(), "House.of.the.Dragon.S01E&1080p,House.of.the.Dragon.S01E&2160p"; got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+func TestKeywords_Compile(t *testing.T) {
+	tests := []struct {
+		name         string
+		keywords     Keywords
+		wantKeyword  string
+		wantUnwanted string
+	}{
+		{
+			name:        "all of",
+			keywords:    Keywords{AllOf: []string{"House.of.the.Dragon", "1080p"}},
+			wantKeyword: "House.of.the.Dragon&1080p",
+		},
+		{
+			name:        "any of",
+			keywords:    Keywords{AnyOf: []string{"1080p", "2160p"}},
+			wantKeyword: "1080p,2160p",
+		},
+		{
+			name:         "all, any and none of",
+			keywords:     Keywords{AllOf: []string{"House.of.the.Dragon", " MULTi "}, AnyOf: []string{"1080p", "2160p"}, NoneOf: []string{"HDR", "x265"}},
+			wantKeyword:  "House.of.the.Dragon&MULTi&1080p,House.of.the.Dragon&MULTi&2160p",
+			wantUnwanted: "HDR,x265",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotKeyword, gotUnwanted := tt.keywords.Compile()
+			if gotKeyword != tt.wantKeyword {
+				t.Errorf("Compile() keyword = %v, want %v", gotKeyword, tt.wantKeyword)
+			}
+			if gotUnwanted != tt.wantUnwanted {
+				t.Errorf("Compile() unwanted = %v, want %v", gotUnwanted, tt.wantUnwanted)
+			}
+		})
+	}
+}
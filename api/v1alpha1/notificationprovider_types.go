@@ -0,0 +1,112 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NotificationProviderType tells how notifications are delivered.
+// +kubebuilder:validation:Enum=Generic;Slack;Discord;SMTP
+type NotificationProviderType string
+
+const (
+	// NotificationProviderGeneric posts notifications as JSON to a webhook.
+	NotificationProviderGeneric NotificationProviderType = "Generic"
+
+	// NotificationProviderSlack posts notifications to a Slack-compatible incoming webhook.
+	NotificationProviderSlack NotificationProviderType = "Slack"
+
+	// NotificationProviderDiscord posts notifications to a Discord-compatible webhook.
+	NotificationProviderDiscord NotificationProviderType = "Discord"
+
+	// NotificationProviderSMTP mails notifications.
+	NotificationProviderSMTP NotificationProviderType = "SMTP"
+)
+
+// SMTPSettings configures the mail server notifications are sent through.
+type SMTPSettings struct {
+	// Host name of the mail server.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Port of the mail server, STARTTLS is used when the server supports it. Default to 587.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=587
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Sender address.
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+
+	// Recipient addresses.
+	// +kubebuilder:validation:MinItems=1
+	To []string `json:"to"`
+
+	// Username to authenticate with, no authentication when empty.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Reference to the password of username in a secret.
+	// +optional
+	PasswordSecretRef *SecretKeyReference `json:"passwordSecretRef,omitempty"`
+}
+
+// NotificationProviderSpec defines the desired state of NotificationProvider.
+type NotificationProviderSpec struct {
+	// How notifications are delivered.
+	Type NotificationProviderType `json:"type"`
+
+	// Webhook URL of the Generic, Slack and Discord types. Mutually exclusive with addressSecretRef.
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// Reference to the webhook URL in a secret, as webhook URLs usually embed a token. Mutually exclusive with address.
+	// +optional
+	AddressSecretRef *SecretKeyReference `json:"addressSecretRef,omitempty"`
+
+	// Mail server of the SMTP type.
+	// +optional
+	SMTP *SMTPSettings `json:"smtp,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NotificationProvider is the Schema to deliver the notifications of Alerts to a webhook or by mail.
+type NotificationProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NotificationProviderSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NotificationProviderList contains a list of NotificationProvider.
+type NotificationProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationProvider{}, &NotificationProviderList{})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AllNamespaces can be used in PutioAccountSpec.AllowedNamespaces to allow every namespace.
+const AllNamespaces string = "*"
+
+// NamespacedSecretReference references a Secret key in any namespace.
+type NamespacedSecretReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// AccountReference references a PutioAccount.
+type AccountReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// PutioAccountSpec defines the desired state of PutioAccount.
+type PutioAccountSpec struct {
+	// Reference to the secret holding the Put.io token.
+	TokenSecretRef NamespacedSecretReference `json:"tokenSecretRef"`
+
+	// Namespaces allowed to use this account. Use "*" to allow every namespace.
+	// No namespace is allowed when empty.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// PutioAccountStatus defines the observed state of PutioAccount.
+type PutioAccountStatus struct {
+	// Put.io username owning the token.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Account email address.
+	// +optional
+	Mail string `json:"mail,omitempty"`
+
+	// Disk size in bytes.
+	// +optional
+	DiskSize int64 `json:"disk_size,omitempty"`
+
+	// Used disk space in bytes.
+	// +optional
+	DiskUsed int64 `json:"disk_used,omitempty"`
+
+	// Available disk space in bytes.
+	// +optional
+	DiskAvailable int64 `json:"disk_available,omitempty"`
+
+	// Last time the account has been checked against Put.io.
+	// +optional
+	LastCheck *metav1.Time `json:"last_check,omitempty"`
+
+	// Conditions represent the latest available observations of a PutioAccount state
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Username",type=string,JSONPath=".status.username"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type == "Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Disk used",type=integer,priority=1,JSONPath=".status.disk_used"
+// +kubebuilder:printcolumn:name="Disk size",type=integer,priority=1,JSONPath=".status.disk_size"
+
+// PutioAccount is the Schema to share a Put.io token across namespaces.
+type PutioAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PutioAccountSpec   `json:"spec,omitempty"`
+	Status PutioAccountStatus `json:"status,omitempty"`
+}
+
+// IsNamespaceAllowed reports whether resources of given namespace may use this account.
+func (r *PutioAccount) IsNamespaceAllowed(namespace string) bool {
+	for _, allowed := range r.Spec.AllowedNamespaces {
+		if allowed == AllNamespaces || allowed == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+//+kubebuilder:object:root=true
+
+// PutioAccountList contains a list of PutioAccount.
+type PutioAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PutioAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PutioAccount{}, &PutioAccountList{})
+}
@@ -0,0 +1,48 @@
+package v1alpha1
+
+import "testing"
+
+func TestPutioAccount_IsNamespaceAllowed(t *testing.T) {
+	type fields struct {
+		Spec PutioAccountSpec
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		namespace string
+		want      bool
+	}{
+		{
+			name:      "no namespace allowed",
+			fields:    fields{Spec: PutioAccountSpec{}},
+			namespace: "default",
+			want:      false,
+		},
+		{
+			name:      "namespace allowed",
+			fields:    fields{Spec: PutioAccountSpec{AllowedNamespaces: []string{"media", "default"}}},
+			namespace: "default",
+			want:      true,
+		},
+		{
+			name:      "namespace not allowed",
+			fields:    fields{Spec: PutioAccountSpec{AllowedNamespaces: []string{"media"}}},
+			namespace: "default",
+			want:      false,
+		},
+		{
+			name:      "every namespace allowed",
+			fields:    fields{Spec: PutioAccountSpec{AllowedNamespaces: []string{AllNamespaces}}},
+			namespace: "default",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &PutioAccount{Spec: tt.fields.Spec}
+			if got := r.IsNamespaceAllowed(tt.namespace); got != tt.want {
+				t.Errorf("IsNamespaceAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxScheduleSteps bounds the search of the end of overlapping windows, e.g. for a window which never ends.
+const maxScheduleSteps = 1000
+
+var (
+	errMissingScheduleDuration = errors.New("duration is required along with cron")
+	errInvalidScheduleDuration = errors.New("duration must be positive")
+	errScheduleWindowConflict  = errors.New("cron cannot be used along with days, start and end")
+	errMissingScheduleTimes    = errors.New("one of cron, or start and end, is required")
+	errInvalidScheduleDay      = errors.New("invalid day")
+)
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// weekdays maps days of the week to their cron value.
+var weekdays = map[Weekday]time.Weekday{
+	"Sunday":    time.Sunday,
+	"Monday":    time.Monday,
+	"Tuesday":   time.Tuesday,
+	"Wednesday": time.Wednesday,
+	"Thursday":  time.Thursday,
+	"Friday":    time.Friday,
+	"Saturday":  time.Saturday,
+}
+
+// ScheduleWindow is a recurring window during which a feed is active, given either by a cron expression
+// and a duration, or by days of the week and a time range.
+type ScheduleWindow struct {
+	// Cron expression of the starts of the window (minute, hour, day of month, month, day of week), e.g. "0 20 * * wed".
+	// Requires duration. Mutually exclusive with days, start and end.
+	// +optional
+	Cron string `json:"cron,omitempty"`
+
+	// How long the window lasts from each start given by cron, e.g. "6h".
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Days of the week the window starts on. Default to every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Time of the day the window starts at, as "HH:MM". Required along with end unless cron is given.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +optional
+	Start string `json:"start,omitempty"`
+
+	// Time of the day the window ends at, as "HH:MM", excluded.
+	// A window ending before it starts ends the next day, one ending when it starts lasts the whole day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +optional
+	End string `json:"end,omitempty"`
+}
+
+// FeedSchedule restricts a feed to recurring active windows, the feed being paused outside of them.
+type FeedSchedule struct {
+	// IANA time zone of the windows, e.g. "Europe/Paris". Default to UTC.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// The feed is active while any of these windows is.
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+}
+
+// Schedule is a compiled FeedSchedule, telling when a feed is active.
+// +kubebuilder:object:generate=false
+type Schedule struct {
+	location *time.Location
+	windows  []scheduleWindow
+}
+
+// scheduleWindow is a window lasting duration from each time matching its cron expression.
+type scheduleWindow struct {
+	starts   *CronExpression
+	duration time.Duration
+}
+
+// Compile parses the time zone and the windows of the schedule.
+func (s *FeedSchedule) Compile() (*Schedule, error) {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+
+	schedule := &Schedule{location: location, windows: make([]scheduleWindow, 0, len(s.Windows))}
+	for i := range s.Windows {
+		window, err := s.Windows[i].compile()
+		if err != nil {
+			return nil, fmt.Errorf("window %d: %w", i, err)
+		}
+
+		schedule.windows = append(schedule.windows, window)
+	}
+
+	return schedule, nil
+}
+
+// compile turns the window into a cron expression of its starts along with its duration.
+func (w *ScheduleWindow) compile() (scheduleWindow, error) {
+	if w.Cron != "" {
+		switch {
+		case len(w.Days) > 0 || w.Start != "" || w.End != "":
+			return scheduleWindow{}, errScheduleWindowConflict
+		case w.Duration == nil:
+			return scheduleWindow{}, errMissingScheduleDuration
+		case w.Duration.Duration <= 0:
+			return scheduleWindow{}, errInvalidScheduleDuration
+		}
+
+		starts, err := ParseCronExpression(w.Cron)
+		if err != nil {
+			return scheduleWindow{}, fmt.Errorf("invalid cron %q: %w", w.Cron, err)
+		}
+
+		return scheduleWindow{starts: starts, duration: w.Duration.Duration}, nil
+	}
+
+	if w.Start == "" || w.End == "" {
+		return scheduleWindow{}, errMissingScheduleTimes
+	}
+
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return scheduleWindow{}, fmt.Errorf("invalid start %q: %w", w.Start, err)
+	}
+
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return scheduleWindow{}, fmt.Errorf("invalid end %q: %w", w.End, err)
+	}
+
+	duration := end.Sub(start)
+	if duration <= 0 {
+		duration += 24 * time.Hour
+	}
+
+	days := "*"
+	if len(w.Days) > 0 {
+		values := make([]string, 0, len(w.Days))
+		for _, day := range w.Days {
+			value, ok := weekdays[day]
+			if !ok {
+				return scheduleWindow{}, fmt.Errorf("%w %q", errInvalidScheduleDay, day)
+			}
+
+			values = append(values, fmt.Sprint(int(value)))
+		}
+
+		days = strings.Join(values, ",")
+	}
+
+	starts, err := ParseCronExpression(fmt.Sprintf("%d %d * * %s", start.Minute(), start.Hour(), days))
+	if err != nil {
+		return scheduleWindow{}, err
+	}
+
+	return scheduleWindow{starts: starts, duration: duration}, nil
+}
+
+// Active tells whether any window of the schedule is active at given time.
+func (s *Schedule) Active(t time.Time) bool {
+	t = t.In(s.location)
+	for _, w := range s.windows {
+		// the first start after the window would have ended if it started earlier
+		if start := w.starts.Next(t.Add(-w.duration)); !start.IsZero() && !start.After(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextTransition returns when the schedule next becomes active or inactive after given time.
+// It returns the zero time when it cannot tell, e.g. for windows which always overlap.
+func (s *Schedule) NextTransition(t time.Time) time.Time {
+	t = t.In(s.location)
+
+	if !s.Active(t) {
+		var next time.Time
+		for _, w := range s.windows {
+			if start := w.starts.Next(t); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+
+		return next
+	}
+
+	// walk through the ends of the windows until none other is active
+	for i := 0; i < maxScheduleSteps; i++ {
+		var next time.Time
+		for _, w := range s.windows {
+			start := w.starts.Next(t.Add(-w.duration))
+			if start.IsZero() {
+				continue
+			}
+
+			if end := start.Add(w.duration); next.IsZero() || end.Before(next) {
+				next = end
+			}
+		}
+
+		if next.IsZero() || !s.Active(next) {
+			return next
+		}
+
+		t = next
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,160 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFeedSchedule_Compile(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule FeedSchedule
+		wantErr  bool
+	}{
+		{
+			name:     "time range",
+			schedule: FeedSchedule{Windows: []ScheduleWindow{{Start: "20:00", End: "22:00"}}},
+		},
+		{
+			name:     "cron",
+			schedule: FeedSchedule{Windows: []ScheduleWindow{{Cron: "0 20 * * *", Duration: &metav1.Duration{Duration: time.Hour}}}},
+		},
+		{
+			name:     "unknown timezone",
+			schedule: FeedSchedule{Timezone: "Mars/Olympus", Windows: []ScheduleWindow{{Start: "20:00", End: "22:00"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown day",
+			schedule: FeedSchedule{Windows: []ScheduleWindow{{Days: []Weekday{"Caturday"}, Start: "20:00", End: "22:00"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid start",
+			schedule: FeedSchedule{Windows: []ScheduleWindow{{Start: "8pm", End: "22:00"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "negative duration",
+			schedule: FeedSchedule{Windows: []ScheduleWindow{{Cron: "0 20 * * *", Duration: &metav1.Duration{Duration: -time.Hour}}}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.schedule.Compile(); (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2022-10-12 is a Wednesday
+	tests := []struct {
+		name       string
+		schedule   FeedSchedule
+		t          time.Time
+		wantActive bool
+		wantNext   time.Time
+	}{
+		{
+			name:       "before time range",
+			schedule:   FeedSchedule{Windows: []ScheduleWindow{{Start: "20:00", End: "22:00"}}},
+			t:          time.Date(2022, 10, 12, 19, 0, 0, 0, time.UTC),
+			wantActive: false,
+			wantNext:   time.Date(2022, 10, 12, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "at start of time range",
+			schedule:   FeedSchedule{Windows: []ScheduleWindow{{Start: "20:00", End: "22:00"}}},
+			t:          time.Date(2022, 10, 12, 20, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Date(2022, 10, 12, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "at end of time range",
+			schedule:   FeedSchedule{Windows: []ScheduleWindow{{Start: "20:00", End: "22:00"}}},
+			t:          time.Date(2022, 10, 12, 22, 0, 0, 0, time.UTC),
+			wantActive: false,
+			wantNext:   time.Date(2022, 10, 13, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "overnight time range",
+			schedule:   FeedSchedule{Windows: []ScheduleWindow{{Days: []Weekday{"Wednesday"}, Start: "22:00", End: "02:00"}}},
+			t:          time.Date(2022, 10, 13, 1, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Date(2022, 10, 13, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "next release day",
+			schedule:   FeedSchedule{Windows: []ScheduleWindow{{Days: []Weekday{"Monday", "Thursday"}, Start: "20:00", End: "23:00"}}},
+			t:          time.Date(2022, 10, 12, 21, 0, 0, 0, time.UTC),
+			wantActive: false,
+			wantNext:   time.Date(2022, 10, 13, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "in timezone",
+			schedule:   FeedSchedule{Timezone: "Europe/Paris", Windows: []ScheduleWindow{{Start: "20:00", End: "22:00"}}},
+			t:          time.Date(2022, 10, 12, 18, 30, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Date(2022, 10, 12, 22, 0, 0, 0, paris),
+		},
+		{
+			name: "cron",
+			schedule: FeedSchedule{Windows: []ScheduleWindow{
+				{Cron: "0 20 * * wed", Duration: &metav1.Duration{Duration: 36 * time.Hour}},
+			}},
+			t:          time.Date(2022, 10, 13, 12, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Date(2022, 10, 14, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "overlapping windows",
+			schedule: FeedSchedule{Windows: []ScheduleWindow{
+				{Start: "18:00", End: "21:00"},
+				{Start: "20:00", End: "23:00"},
+			}},
+			t:          time.Date(2022, 10, 12, 19, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Date(2022, 10, 12, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "whole day",
+			schedule:   FeedSchedule{Windows: []ScheduleWindow{{Days: []Weekday{"Wednesday"}, Start: "00:00", End: "00:00"}}},
+			t:          time.Date(2022, 10, 12, 12, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Date(2022, 10, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "always active",
+			schedule:   FeedSchedule{Windows: []ScheduleWindow{{Start: "00:00", End: "00:00"}}},
+			t:          time.Date(2022, 10, 12, 12, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := tt.schedule.Compile()
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			if got := schedule.Active(tt.t); got != tt.wantActive {
+				t.Errorf("Active() = %v, want %v", got, tt.wantActive)
+			}
+
+			if got := schedule.NextTransition(tt.t); !got.Equal(tt.wantNext) {
+				t.Errorf("NextTransition() = %v, want %v", got, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TransferSpec defines the desired state of Transfer.
+type TransferSpec struct {
+	// +kubebuilder:validation:MinLength:=1
+	// The URL or magnet link to download. Changing it once the transfer is started has no effect.
+	URL string `json:"url"`
+
+	// The file ID of the folder to download the transfer into. Default to the root directory (0).
+	// +optional
+	ParentDirID *uint `json:"parent_dir_id,omitempty"`
+
+	// How many times a failed transfer should be retried. Default to 0.
+	// +optional
+	Retries uint `json:"retries,omitempty"`
+
+	// Authentication reference to Put.io token in a secret.
+	AuthSecretRef AuthSecretReference `json:"authSecretRef"`
+}
+
+// TransferStatus defines the observed state of Transfer.
+type TransferStatus struct {
+	ID *uint `json:"id,omitempty"`
+
+	// Name of the transfer as shown on the site.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Put.io transfer status (IN_QUEUE, DOWNLOADING, COMPLETED, ERROR, ...).
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// Download progress percentage.
+	// +optional
+	PercentDone int `json:"percent_done,omitempty"`
+
+	// Download speed in bytes per second.
+	// +optional
+	DownloadSpeed int64 `json:"down_speed,omitempty"`
+
+	// Upload speed in bytes per second.
+	// +optional
+	UploadSpeed int64 `json:"up_speed,omitempty"`
+
+	// The file ID of the downloaded file or folder, once completed.
+	// +optional
+	FileID *uint `json:"file_id,omitempty"`
+
+	// Error reported by Put.io when the transfer failed.
+	// +optional
+	ErrorMessage string `json:"error_message,omitempty"`
+
+	// How many times the transfer has been retried.
+	// +optional
+	Retried uint `json:"retried,omitempty"`
+
+	// Conditions represent the latest available observations of a Transfer state
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.status"
+// +kubebuilder:printcolumn:name="Progress",type=integer,JSONPath=".status.percent_done"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Completed",type="string",JSONPath=`.status.conditions[?(@.type == "Completed")].status`
+// +kubebuilder:printcolumn:name="ID",type=string,priority=1,JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Name",type=string,priority=1,JSONPath=".status.name"
+// +kubebuilder:printcolumn:name="File ID",type=string,priority=1,JSONPath=".status.file_id"
+
+// Transfer is the Schema to manage your one-off downloads.
+type Transfer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TransferSpec   `json:"spec,omitempty"`
+	Status TransferStatus `json:"status,omitempty"`
+}
+
+func (r *Transfer) AuthSecretRef() AuthSecretReference {
+	return r.Spec.AuthSecretRef
+}
+
+//+kubebuilder:object:root=true
+
+// TransferList contains a list of Transfer.
+type TransferList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Transfer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Transfer{}, &TransferList{})
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var (
+	tracer = otel.GetTracerProvider().Tracer("webhook")
+
+	errUnexpectedHub = errors.New("unexpected hub type")
+)
+
+var _ conversion.Convertible = &Feed{}
+
+// ConvertTo converts this Feed to the hub version, v1alpha1.
+func (src *Feed) ConvertTo(dstRaw conversion.Hub) error {
+	_, span := tracer.Start(context.Background(), "v1beta1.Feed.ConvertTo")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", src.Name))
+
+	dst, ok := dstRaw.(*v1alpha1.Feed)
+	if !ok {
+		err := fmt.Errorf("%w %T", errUnexpectedHub, dstRaw)
+		span.RecordError(err)
+		return err
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.FeedSpec{
+		Title:                src.Spec.Title,
+		RssSourceURL:         src.Spec.Source.URL,
+		ParentDirID:          copyUint(src.Spec.ParentDirID),
+		ParentDirPath:        src.Spec.ParentDirPath,
+		CreateParentDir:      src.Spec.CreateParentDir,
+		DeleteOldFiles:       boolToPtr(src.Spec.DeleteOldFiles),
+		DontProcessWholeFeed: boolToPtr(src.Spec.Source.IgnoreExistingItems),
+		Keyword:              src.Spec.Keyword,
+		UnwantedKeywords:     src.Spec.UnwantedKeywords,
+		Paused:               boolToPtr(src.Spec.Paused),
+		Preview:              src.Spec.Preview,
+		DeletionPolicy:       v1alpha1.DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+
+	if from := src.Spec.Source.URLFrom; from != nil {
+		dst.Spec.RssSourceURLFrom = &v1alpha1.RssSourceURLSource{
+			SecretKeyRef: v1alpha1.SecretKeyReference{Name: from.SecretKeyRef.Name, Key: from.SecretKeyRef.Key},
+		}
+	}
+
+	if ref := src.Spec.ParentFolderRef; ref != nil {
+		dst.Spec.ParentFolderRef = &v1alpha1.FolderReference{Name: ref.Name}
+	}
+
+	if keywords := src.Spec.Keywords; keywords != nil {
+		dst.Spec.Keywords = &v1alpha1.Keywords{
+			AllOf:  copyStrings(keywords.AllOf),
+			AnyOf:  copyStrings(keywords.AnyOf),
+			NoneOf: copyStrings(keywords.NoneOf),
+		}
+	}
+
+	if ref := src.Spec.AuthSecretRef; ref != nil {
+		dst.Spec.AuthSecretRef = &v1alpha1.AuthSecretReference{Name: ref.Name, Key: ref.Key}
+	}
+
+	if ref := src.Spec.AccountRef; ref != nil {
+		dst.Spec.AccountRef = &v1alpha1.AccountReference{Name: ref.Name}
+	}
+
+	if expiry := src.Spec.Expiry; expiry != nil {
+		dst.Spec.Expiry = &v1alpha1.FeedExpiry{
+			After:        expiry.After.DeepCopy(),
+			AfterMatches: copyInt32(expiry.AfterMatches),
+			InactiveFor:  expiry.InactiveFor.DeepCopy(),
+			OnExpiry:     v1alpha1.ExpiryAction(expiry.OnExpiry),
+		}
+	}
+
+	if schedule := src.Spec.Schedule; schedule != nil {
+		dst.Spec.Schedule = &v1alpha1.FeedSchedule{Timezone: schedule.Timezone}
+		for _, window := range schedule.Windows {
+			var days []v1alpha1.Weekday
+			for _, day := range window.Days {
+				days = append(days, v1alpha1.Weekday(day))
+			}
+
+			dst.Spec.Schedule.Windows = append(dst.Spec.Schedule.Windows, v1alpha1.ScheduleWindow{
+				Cron:     window.Cron,
+				Duration: window.Duration.DeepCopy(),
+				Days:     days,
+				Start:    window.Start,
+				End:      window.End,
+			})
+		}
+	}
+
+	dst.Status = v1alpha1.FeedStatus{
+		ID:                 copyUint(src.Status.ID),
+		SpecHash:           src.Status.SpecHash,
+		AuthSecretVersion:  src.Status.AuthSecretVersion,
+		ParentDirID:        copyUint(src.Status.ParentDirID),
+		LastFetch:          src.Status.LastFetch.DeepCopy(),
+		LastError:          src.Status.LastError,
+		FailedItemCount:    src.Status.FailedItemCount,
+		PausedAt:           src.Status.PausedAt.DeepCopy(),
+		NextTransition:     src.Status.NextTransition.DeepCopy(),
+		StartAt:            src.Status.StartAt.DeepCopy(),
+		UpdatedAt:          src.Status.UpdatedAt.DeepCopy(),
+		Extract:            src.Status.Extract,
+		CompletedItemCount: src.Status.CompletedItemCount,
+		LastItemID:         copyUint(src.Status.LastItemID),
+		LastItemAt:         src.Status.LastItemAt.DeepCopy(),
+		LastCompletedItem:  src.Status.LastCompletedItem,
+		Conditions:         copyConditions(src.Status.Conditions),
+	}
+
+	if preview := src.Status.Preview; preview != nil {
+		dst.Status.Preview = &v1alpha1.FeedPreview{
+			TotalItems:       preview.TotalItems,
+			MatchedItems:     copyStrings(preview.MatchedItems),
+			MatchedItemCount: preview.MatchedItemCount,
+			FetchedAt:        preview.FetchedAt,
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the hub version, v1alpha1, to this version.
+// Unset booleans of the hub version are converted to false.
+func (dst *Feed) ConvertFrom(srcRaw conversion.Hub) error {
+	_, span := tracer.Start(context.Background(), "v1beta1.Feed.ConvertFrom")
+	defer span.End()
+
+	src, ok := srcRaw.(*v1alpha1.Feed)
+	if !ok {
+		err := fmt.Errorf("%w %T", errUnexpectedHub, srcRaw)
+		span.RecordError(err)
+		return err
+	}
+
+	span.SetAttributes(attribute.String("name", src.Name))
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = FeedSpec{
+		Title: src.Spec.Title,
+		Source: FeedSource{
+			URL:                 src.Spec.RssSourceURL,
+			IgnoreExistingItems: ptrToBool(src.Spec.DontProcessWholeFeed),
+		},
+		ParentDirID:      copyUint(src.Spec.ParentDirID),
+		ParentDirPath:    src.Spec.ParentDirPath,
+		CreateParentDir:  src.Spec.CreateParentDir,
+		DeleteOldFiles:   ptrToBool(src.Spec.DeleteOldFiles),
+		Keyword:          src.Spec.Keyword,
+		UnwantedKeywords: src.Spec.UnwantedKeywords,
+		Paused:           ptrToBool(src.Spec.Paused),
+		Preview:          src.Spec.Preview,
+		DeletionPolicy:   DeletionPolicy(src.Spec.DeletionPolicy),
+	}
+
+	if from := src.Spec.RssSourceURLFrom; from != nil {
+		dst.Spec.Source.URLFrom = &URLSource{
+			SecretKeyRef: SecretKeyReference{Name: from.SecretKeyRef.Name, Key: from.SecretKeyRef.Key},
+		}
+	}
+
+	if ref := src.Spec.ParentFolderRef; ref != nil {
+		dst.Spec.ParentFolderRef = &FolderReference{Name: ref.Name}
+	}
+
+	if keywords := src.Spec.Keywords; keywords != nil {
+		dst.Spec.Keywords = &Keywords{
+			AllOf:  copyStrings(keywords.AllOf),
+			AnyOf:  copyStrings(keywords.AnyOf),
+			NoneOf: copyStrings(keywords.NoneOf),
+		}
+	}
+
+	if ref := src.Spec.AuthSecretRef; ref != nil {
+		dst.Spec.AuthSecretRef = &AuthSecretReference{Name: ref.Name, Key: ref.Key}
+	}
+
+	if ref := src.Spec.AccountRef; ref != nil {
+		dst.Spec.AccountRef = &AccountReference{Name: ref.Name}
+	}
+
+	if expiry := src.Spec.Expiry; expiry != nil {
+		dst.Spec.Expiry = &FeedExpiry{
+			After:        expiry.After.DeepCopy(),
+			AfterMatches: copyInt32(expiry.AfterMatches),
+			InactiveFor:  expiry.InactiveFor.DeepCopy(),
+			OnExpiry:     ExpiryAction(expiry.OnExpiry),
+		}
+	}
+
+	if schedule := src.Spec.Schedule; schedule != nil {
+		dst.Spec.Schedule = &FeedSchedule{Timezone: schedule.Timezone}
+		for _, window := range schedule.Windows {
+			var days []Weekday
+			for _, day := range window.Days {
+				days = append(days, Weekday(day))
+			}
+
+			dst.Spec.Schedule.Windows = append(dst.Spec.Schedule.Windows, ScheduleWindow{
+				Cron:     window.Cron,
+				Duration: window.Duration.DeepCopy(),
+				Days:     days,
+				Start:    window.Start,
+				End:      window.End,
+			})
+		}
+	}
+
+	dst.Status = FeedStatus{
+		ID:                 copyUint(src.Status.ID),
+		SpecHash:           src.Status.SpecHash,
+		AuthSecretVersion:  src.Status.AuthSecretVersion,
+		ParentDirID:        copyUint(src.Status.ParentDirID),
+		LastFetch:          src.Status.LastFetch.DeepCopy(),
+		LastError:          src.Status.LastError,
+		FailedItemCount:    src.Status.FailedItemCount,
+		PausedAt:           src.Status.PausedAt.DeepCopy(),
+		NextTransition:     src.Status.NextTransition.DeepCopy(),
+		StartAt:            src.Status.StartAt.DeepCopy(),
+		UpdatedAt:          src.Status.UpdatedAt.DeepCopy(),
+		Extract:            src.Status.Extract,
+		CompletedItemCount: src.Status.CompletedItemCount,
+		LastItemID:         copyUint(src.Status.LastItemID),
+		LastItemAt:         src.Status.LastItemAt.DeepCopy(),
+		LastCompletedItem:  src.Status.LastCompletedItem,
+		Conditions:         copyConditions(src.Status.Conditions),
+	}
+
+	if preview := src.Status.Preview; preview != nil {
+		dst.Status.Preview = &FeedPreview{
+			TotalItems:       preview.TotalItems,
+			MatchedItems:     copyStrings(preview.MatchedItems),
+			MatchedItemCount: preview.MatchedItemCount,
+			FetchedAt:        preview.FetchedAt,
+		}
+	}
+
+	return nil
+}
+
+func boolToPtr(b bool) *bool {
+	return &b
+}
+
+func ptrToBool(b *bool) bool {
+	return b != nil && *b
+}
+
+func copyUint(u *uint) *uint {
+	if u == nil {
+		return nil
+	}
+
+	v := *u
+	return &v
+}
+
+func copyInt32(i *int32) *int32 {
+	if i == nil {
+		return nil
+	}
+
+	v := *i
+	return &v
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+
+	return append(make([]string, 0, len(s)), s...)
+}
+
+func copyConditions(conditions []metav1.Condition) []metav1.Condition {
+	if conditions == nil {
+		return nil
+	}
+
+	return append(make([]metav1.Condition, 0, len(conditions)), conditions...)
+}
@@ -0,0 +1,176 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func uintToPtr(u uint) *uint {
+	return &u
+}
+
+func TestFeed_roundTrip(t *testing.T) {
+	now := metav1.NewTime(time.Date(2022, 8, 21, 10, 0, 0, 0, time.UTC))
+	afterMatches := int32(10)
+	meta := metav1.ObjectMeta{Name: "house-of-the-dragon", Namespace: "default", Generation: 2, Labels: map[string]string{"app": "putio"}}
+
+	tests := []struct {
+		name string
+		feed *Feed
+	}{
+		{
+			name: "minimal",
+			feed: &Feed{
+				ObjectMeta: meta,
+				Spec: FeedSpec{
+					Title:         "House of the Dragon",
+					Source:        FeedSource{URL: "https://rss.site.fr/rss?id=2184"},
+					Keyword:       "House.of.the.Dragon",
+					AuthSecretRef: &AuthSecretReference{Name: "putio-token", Key: "token"},
+				},
+			},
+		},
+		{
+			name: "every field",
+			feed: &Feed{
+				ObjectMeta: meta,
+				Spec: FeedSpec{
+					Title: "House of the Dragon",
+					Source: FeedSource{
+						URL:                 "https://rss.site.fr/rss?id=2184&passkey=${passkey}",
+						URLFrom:             &URLSource{SecretKeyRef: SecretKeyReference{Name: "tracker", Key: "passkey"}},
+						IgnoreExistingItems: true,
+					},
+					ParentFolderRef:  &FolderReference{Name: "house-of-the-dragon"},
+					DeleteOldFiles:   true,
+					UnwantedKeywords: "HDR",
+					Keywords:         &Keywords{AllOf: []string{"House.of.the.Dragon"}, AnyOf: []string{"1080p", "2160p"}},
+					Paused:           true,
+					Preview:          true,
+					DeletionPolicy:   DeletionPolicyPause,
+					AccountRef:       &AccountReference{Name: "shared"},
+					Schedule: &FeedSchedule{
+						Timezone: "Europe/Paris",
+						Windows: []ScheduleWindow{
+							{Days: []Weekday{"Monday", "Thursday"}, Start: "20:00", End: "02:00"},
+							{Cron: "0 20 * * wed", Duration: &metav1.Duration{Duration: 6 * time.Hour}},
+						},
+					},
+					Expiry: &FeedExpiry{
+						After:        &now,
+						AfterMatches: &afterMatches,
+						InactiveFor:  &metav1.Duration{Duration: 720 * time.Hour},
+						OnExpiry:     ExpiryActionDelete,
+					},
+				},
+				Status: FeedStatus{
+					ID:                 uintToPtr(42),
+					SpecHash:           "abc",
+					AuthSecretVersion:  "1138",
+					ParentDirID:        uintToPtr(1234),
+					LastFetch:          &now,
+					LastError:          "error",
+					FailedItemCount:    3,
+					CompletedItemCount: 8,
+					LastItemID:         uintToPtr(1138),
+					LastItemAt:         &now,
+					LastCompletedItem:  "House.of.the.Dragon.S01E08",
+					PausedAt:           &now,
+					NextTransition:     &now,
+					StartAt:            &now,
+					UpdatedAt:          &now,
+					Extract:            true,
+					Preview: &FeedPreview{
+						TotalItems:       10,
+						MatchedItems:     []string{"House.of.the.Dragon.S01E01"},
+						MatchedItemCount: 1,
+						FetchedAt:        now,
+					},
+					Conditions: []metav1.Condition{{Type: "Available", Status: metav1.ConditionTrue, Reason: "FeedSuccessfullyDeployed", LastTransitionTime: now}},
+				},
+			},
+		},
+		{
+			name: "folder ID and path",
+			feed: &Feed{
+				ObjectMeta: meta,
+				Spec: FeedSpec{
+					Title:         "House of the Dragon",
+					Source:        FeedSource{URLFrom: &URLSource{SecretKeyRef: SecretKeyReference{Name: "tracker", Key: "url"}}},
+					ParentDirID:   uintToPtr(0),
+					ParentDirPath: "TV Shows/House of the Dragon",
+					Keyword:       "House.of.the.Dragon",
+					AuthSecretRef: &AuthSecretReference{Name: "putio-token", Key: "token"},
+				},
+			},
+		},
+		{
+			name: "folder to create",
+			feed: &Feed{
+				ObjectMeta: meta,
+				Spec: FeedSpec{
+					Title:           "House of the Dragon",
+					Source:          FeedSource{URL: "https://example.com/rss"},
+					CreateParentDir: "TV Shows/House of the Dragon",
+					Keyword:         "House.of.the.Dragon",
+					AuthSecretRef:   &AuthSecretReference{Name: "putio-token", Key: "token"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := new(v1alpha1.Feed)
+			if err := tt.feed.ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+
+			got := new(Feed)
+			if err := got.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.feed, got); diff != "" {
+				t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFeed_hubRoundTrip(t *testing.T) {
+	hub := &v1alpha1.Feed{
+		ObjectMeta: metav1.ObjectMeta{Name: "house-of-the-dragon", Namespace: "default"},
+		Spec: v1alpha1.FeedSpec{
+			Title:            "House of the Dragon",
+			RssSourceURL:     "https://rss.site.fr/rss?id=2184",
+			ParentDirPath:    "TV Shows/House of the Dragon",
+			Keyword:          "House.of.the.Dragon",
+			UnwantedKeywords: "HDR",
+			AuthSecretRef:    &v1alpha1.AuthSecretReference{Name: "putio-token", Key: "token"},
+		},
+		Status: v1alpha1.FeedStatus{ID: uintToPtr(42), LastError: "error"},
+	}
+	hub.Default() // booleans are always set once stored
+
+	spoke := new(Feed)
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+
+	if spoke.Spec.Source.URL != hub.Spec.RssSourceURL || spoke.Spec.ParentDirPath != hub.Spec.ParentDirPath {
+		t.Errorf("ConvertFrom() spec = %+v, fields not converted", spoke.Spec)
+	}
+
+	got := new(v1alpha1.Feed)
+	if err := spoke.ConvertTo(got); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+
+	if diff := cmp.Diff(hub, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
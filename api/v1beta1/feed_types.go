@@ -0,0 +1,380 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretKeyReference references a key of a Secret in the same namespace.
+type SecretKeyReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// AuthSecretReference references a Secret containing a Put.io authentication token.
+type AuthSecretReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// AccountReference references a cluster-wide PutioAccount.
+type AccountReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// FolderReference references a Folder in the same namespace.
+type FolderReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// DeletionPolicy tells what happens to the Put.io feed when its Feed is deleted.
+// +kubebuilder:validation:Enum=Delete;Orphan;Pause
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the Put.io feed.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the Put.io feed as is, no longer managed by the operator.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	// DeletionPolicyPause pauses the Put.io feed and leaves it no longer managed by the operator.
+	DeletionPolicyPause DeletionPolicy = "Pause"
+)
+
+// ExpiryAction tells what happens to a Feed once it expired.
+// +kubebuilder:validation:Enum=Pause;Delete;Condition
+type ExpiryAction string
+
+const (
+	// ExpiryActionPause pauses the Put.io feed, without changing paused.
+	ExpiryActionPause ExpiryAction = "Pause"
+	// ExpiryActionDelete deletes the Feed, its deletion policy applying to the Put.io feed.
+	ExpiryActionDelete ExpiryAction = "Delete"
+	// ExpiryActionCondition only reports the Feed expired through its Expired condition.
+	ExpiryActionCondition ExpiryAction = "Condition"
+)
+
+// FeedExpiry tells when a Feed is done with, e.g. once its season ended. The Feed expires as soon as one is reached.
+type FeedExpiry struct {
+	// Time after which the Feed expires.
+	// +optional
+	After *metav1.Time `json:"after,omitempty"`
+
+	// Number of items Put.io successfully processed for the RSS feed after which the Feed expires.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AfterMatches *int32 `json:"afterMatches,omitempty"`
+
+	// The Feed expires once Put.io processed no new item for this long, e.g. "720h".
+	// +optional
+	InactiveFor *metav1.Duration `json:"inactiveFor,omitempty"`
+
+	// What happens once the Feed expired: Pause the Put.io feed, Delete the Feed, or only set its Expired Condition.
+	// Default to Pause.
+	// +optional
+	OnExpiry ExpiryAction `json:"onExpiry,omitempty"`
+}
+
+// URLSource reads the URL of the RSS feed, or its passkey, from a Secret.
+type URLSource struct {
+	// Secret key holding the URL of the RSS feed, or the passkey replacing ${passkey} in url.
+	SecretKeyRef SecretKeyReference `json:"secretKeyRef"`
+}
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// ScheduleWindow is a recurring window during which a feed is active, given either by a cron expression
+// and a duration, or by days of the week and a time range.
+type ScheduleWindow struct {
+	// Cron expression of the starts of the window (minute, hour, day of month, month, day of week), e.g. "0 20 * * wed".
+	// Requires duration. Mutually exclusive with days, start and end.
+	// +optional
+	Cron string `json:"cron,omitempty"`
+
+	// How long the window lasts from each start given by cron, e.g. "6h".
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Days of the week the window starts on. Default to every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Time of the day the window starts at, as "HH:MM". Required along with end unless cron is given.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +optional
+	Start string `json:"start,omitempty"`
+
+	// Time of the day the window ends at, as "HH:MM", excluded.
+	// A window ending before it starts ends the next day, one ending when it starts lasts the whole day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +optional
+	End string `json:"end,omitempty"`
+}
+
+// FeedSchedule restricts a feed to recurring active windows, the feed being paused outside of them.
+type FeedSchedule struct {
+	// IANA time zone of the windows, e.g. "Europe/Paris". Default to UTC.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// The feed is active while any of these windows is.
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+}
+
+// FeedSource is the RSS feed watched by Put.io.
+type FeedSource struct {
+	// The URL of the RSS feed. When urlFrom is given, it may contain a ${passkey} placeholder
+	// replaced by the secret value. Required unless urlFrom is given.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Secret holding the URL of the RSS feed, or its passkey when url contains ${passkey}.
+	// +optional
+	URLFrom *URLSource `json:"urlFrom,omitempty"`
+
+	// Ignore the items in the feed at creation time.
+	// +optional
+	IgnoreExistingItems bool `json:"ignoreExistingItems,omitempty"`
+}
+
+// Keywords selects the items to transfer by the words in their title.
+type Keywords struct {
+	// Words which must all be in the title.
+	// +optional
+	AllOf []string `json:"allOf,omitempty"`
+
+	// Words of which at least one must be in the title, along with every allOf word.
+	// +optional
+	AnyOf []string `json:"anyOf,omitempty"`
+
+	// Words of which none must be in the title.
+	// +optional
+	NoneOf []string `json:"noneOf,omitempty"`
+}
+
+// FeedSpec defines the desired state of Feed.
+type FeedSpec struct {
+	// +kubebuilder:validation:MinLength:=1
+	// Title of the RSS feed as will appear on the site.
+	Title string `json:"title"`
+
+	// The RSS feed to be watched.
+	Source FeedSource `json:"source"`
+
+	// The file ID of the folder to place the RSS feed files in. Default to the root directory (0).
+	// Mutually exclusive with parentFolderRef, parentDirPath and createParentDir.
+	// +optional
+	ParentDirID *uint `json:"parentDirID,omitempty"`
+
+	// Reference to a Folder of the same namespace to place the RSS feed files in.
+	// Mutually exclusive with parentDirID, parentDirPath and createParentDir.
+	// +optional
+	ParentFolderRef *FolderReference `json:"parentFolderRef,omitempty"`
+
+	// Slash-separated path of an existing folder to place the RSS feed files in, e.g. "TV Shows/House of the Dragon".
+	// Mutually exclusive with parentDirID, parentFolderRef and createParentDir.
+	// +optional
+	ParentDirPath string `json:"parentDirPath,omitempty"`
+
+	// Slash-separated path of the folder to place the RSS feed files in, created along with its missing parents
+	// when it does not exist yet. Mutually exclusive with parentDirID, parentFolderRef and parentDirPath.
+	// +optional
+	CreateParentDir string `json:"createParentDir,omitempty"`
+
+	// Delete old files in the folder when space is low.
+	// +optional
+	DeleteOldFiles bool `json:"deleteOldFiles,omitempty"`
+
+	// Only items with titles that contain any of these words will be transferred, in the Put.io syntax.
+	// Mutually exclusive with keywords.
+	// +optional
+	Keyword string `json:"keyword,omitempty"`
+
+	// No items with titles that contain any of these words will be transferred, in the Put.io syntax.
+	// Mutually exclusive with keywords.noneOf.
+	// +optional
+	UnwantedKeywords string `json:"unwantedKeywords,omitempty"`
+
+	// Structured form of keyword and unwantedKeywords. Mutually exclusive with keyword.
+	// +optional
+	Keywords *Keywords `json:"keywords,omitempty"`
+
+	// Pause the RSS feed.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Recurring windows during which the RSS feed is active, it is paused outside of them.
+	// A paused feed stays paused whatever its schedule.
+	// +optional
+	Schedule *FeedSchedule `json:"schedule,omitempty"`
+
+	// When the Feed expires, and what happens then.
+	// +optional
+	Expiry *FeedExpiry `json:"expiry,omitempty"`
+
+	// List the items of the RSS feed matching the keywords in status.preview instead of creating the Put.io feed.
+	// +optional
+	Preview bool `json:"preview,omitempty"`
+
+	// What happens to the Put.io feed when this Feed is deleted: Delete it, Orphan it, or Pause it. Default to Delete.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Authentication reference to Put.io token in a secret. Mutually exclusive with accountRef.
+	// +optional
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+
+	// Reference to a cluster-wide PutioAccount allowing this namespace. Mutually exclusive with authSecretRef.
+	// +optional
+	AccountRef *AccountReference `json:"accountRef,omitempty"`
+}
+
+// FeedStatus defines the observed state of Feed.
+type FeedStatus struct {
+	// Put.io ID of the feed.
+	// +optional
+	ID *uint `json:"id,omitempty"`
+
+	// Hash of the Put.io feed payload last applied from the spec.
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+
+	// Resource version of the secret holding the Put.io token last accepted by Put.io.
+	// +optional
+	AuthSecretVersion string `json:"authSecretVersion,omitempty"`
+
+	// File ID of the folder resolved from parentFolderRef, parentDirPath or createParentDir.
+	// +optional
+	ParentDirID *uint `json:"parentDirID,omitempty"`
+
+	// Last time Put.io fetched the RSS feed.
+	// +optional
+	LastFetch *metav1.Time `json:"lastFetch,omitempty"`
+
+	// Last error reported by Put.io while processing the RSS feed.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// Number of feed items Put.io failed to transfer.
+	// +optional
+	FailedItemCount uint `json:"failedItemCount,omitempty"`
+
+	// When the RSS feed was paused at Put.io.
+	// +optional
+	PausedAt *metav1.Time `json:"pausedAt,omitempty"`
+
+	// When the schedule next resumes or pauses the RSS feed.
+	// +optional
+	NextTransition *metav1.Time `json:"nextTransition,omitempty"`
+
+	// When Put.io started to process the RSS feed.
+	// +optional
+	StartAt *metav1.Time `json:"startAt,omitempty"`
+
+	// Last time the RSS feed was updated at Put.io.
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// Whether Put.io extracts archives downloaded by the RSS feed.
+	// +optional
+	Extract bool `json:"extract,omitempty"`
+
+	// Number of items Put.io successfully processed for the RSS feed since the operator manages it.
+	// +optional
+	CompletedItemCount int32 `json:"completedItemCount,omitempty"`
+
+	// ID of the last item Put.io processed for the RSS feed, counted in completedItemCount.
+	// +optional
+	LastItemID *uint `json:"lastItemID,omitempty"`
+
+	// When Put.io processed the last item of the RSS feed.
+	// +optional
+	LastItemAt *metav1.Time `json:"lastItemAt,omitempty"`
+
+	// Title of the last item Put.io successfully processed for the RSS feed.
+	// +optional
+	LastCompletedItem string `json:"lastCompletedItem,omitempty"`
+
+	// Items of the RSS feed matching the keywords, when spec.preview is set.
+	// +optional
+	Preview *FeedPreview `json:"preview,omitempty"`
+
+	// Conditions represent the latest available observations of a Feed state
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// FeedPreview lists the items of the RSS feed Put.io would transfer.
+type FeedPreview struct {
+	// Number of items in the RSS feed.
+	TotalItems int `json:"totalItems"`
+
+	// Titles of the items matching the keywords, the most recent first, at most 100.
+	// +optional
+	MatchedItems []string `json:"matchedItems,omitempty"`
+
+	// Number of items matching the keywords, including the ones not listed.
+	MatchedItemCount int `json:"matchedItemCount"`
+
+	// When the RSS feed was fetched.
+	FetchedAt metav1.Time `json:"fetchedAt"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Keyword",type=string,JSONPath=".spec.keyword"
+// +kubebuilder:printcolumn:name="Paused",type=boolean,JSONPath=".spec.paused"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=`.status.conditions[?(@.type == "Available")].status`
+// +kubebuilder:printcolumn:name="Auth",type="string",priority=1,JSONPath=`.status.conditions[?(@.type == "AuthReady")].status`
+// +kubebuilder:printcolumn:name="ID",type=string,priority=1,JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="URL",type=string,priority=1,JSONPath=".spec.source.url"
+// +kubebuilder:printcolumn:name="Title",type=string,priority=1,JSONPath=".spec.title"
+// +kubebuilder:printcolumn:name="Last fetch",type=date,priority=1,JSONPath=".status.lastFetch"
+// +kubebuilder:printcolumn:name="Failed items",type=integer,priority=1,JSONPath=".status.failedItemCount"
+// +kubebuilder:printcolumn:name="Last error",type=string,priority=1,JSONPath=".status.lastError"
+// +kubebuilder:printcolumn:name="Next transition",type=date,priority=1,JSONPath=".status.nextTransition"
+
+// Feed is the Schema to manage your rss feeds.
+type Feed struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FeedSpec   `json:"spec,omitempty"`
+	Status FeedStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FeedList contains a list of Feed.
+type FeedList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Feed `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Feed{}, &FeedList{})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command fake-putio serves an in-memory Put.io API, to run the operator locally without a Put.io account.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/SkYNewZ/putio-operator/internal/putio/putiotest"
+)
+
+func main() {
+	var (
+		addr     string
+		token    string
+		username string
+		diskSize int64
+	)
+
+	flag.StringVar(&addr, "addr", "localhost:8090", "Address to listen on.")
+	flag.StringVar(&token, "token", "", "Token requests must be authenticated with. Any token is accepted when empty.")
+	flag.StringVar(&username, "username", "putiotest", "Username of the fake account.")
+	flag.Int64Var(&diskSize, "disk-size", putiotest.DefaultDiskSize, "Disk size of the fake account, in bytes.")
+	flag.Parse()
+
+	fake := putiotest.New(
+		putiotest.WithToken(token),
+		putiotest.WithUsername(username),
+		putiotest.WithDiskSize(diskSize),
+	)
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           fake,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("unable to shut down: %s", err)
+		}
+	}()
+
+	log.Printf("serving a fake Put.io API on http://%s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("unable to serve: %s", err) //nolint:gocritic
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/notifier"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// defaultSMTPPort is used by SMTP providers not given a port.
+const defaultSMTPPort = 587
+
+// AlertReconciler reconciles an Alert object.
+// Notifications are sent by the NotificationDispatcher, this reconciler only reports whether they can be.
+type AlertReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=alerts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=alerts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=notificationproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile checks the alert spec and its provider, and reports in its Ready condition whether notifications can be sent.
+func (r *AlertReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, "controllers.AlertReconciler.Reconcile")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("alert.name", req.Name),
+		attribute.String("alert.namespace", req.Namespace),
+	)
+
+	alert := new(skynewzdevv1alpha1.Alert)
+	if err := r.Get(ctx, req.NamespacedName, alert); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	if !alert.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	condition, err := r.checkAlert(ctx, alert)
+	if err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	if condition.Status == metav1.ConditionFalse && !isSameCondition(alert.Status.Conditions, condition) {
+		r.Recorder.Event(alert, corev1.EventTypeWarning, eventInvalidAlert, condition.Message)
+	}
+
+	meta.SetStatusCondition(&alert.Status.Conditions, condition)
+	alert.Status.ObservedGeneration = alert.Generation
+	if err := r.Status().Update(ctx, alert); err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err //nolint:wrapcheck
+	}
+
+	log.FromContext(ctx).Info("Alert successfully reconciled", "ready", condition.Status)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AlertReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "controllers.AlertReconciler.SetupWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&skynewzdevv1alpha1.Alert{}).
+		Watches(&source.Kind{Type: &skynewzdevv1alpha1.NotificationProvider{}}, handler.EnqueueRequestsFromMapFunc(r.findAlertsForProvider)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.findAlertsForSecret)).
+		Complete(r)
+}
+
+// findAlertsForProvider returns a request for each alert of the provider namespace referencing it.
+func (r *AlertReconciler) findAlertsForProvider(provider client.Object) []reconcile.Request {
+	ctx, span := tracer.Start(context.Background(), "controllers.AlertReconciler.findAlertsForProvider")
+	defer span.End()
+
+	alerts := new(skynewzdevv1alpha1.AlertList)
+	if err := r.List(ctx, alerts, client.InNamespace(provider.GetNamespace())); err != nil {
+		span.RecordError(err)
+		log.FromContext(ctx).Error(err, "unable to list alerts referencing provider", "provider", provider.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, alert := range alerts.Items {
+		if alert.Spec.ProviderRef.Name == provider.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: alert.Name, Namespace: alert.Namespace}})
+		}
+	}
+
+	return requests
+}
+
+// findAlertsForSecret returns a request for each alert whose provider reads given secret, so that alerts created
+// before the secret of their provider, or fixed by updating it, become ready.
+func (r *AlertReconciler) findAlertsForSecret(secret client.Object) []reconcile.Request {
+	ctx, span := tracer.Start(context.Background(), "controllers.AlertReconciler.findAlertsForSecret")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("secret.name", secret.GetName()),
+		attribute.String("secret.namespace", secret.GetNamespace()),
+	)
+
+	providers := new(skynewzdevv1alpha1.NotificationProviderList)
+	if err := r.List(ctx, providers, client.InNamespace(secret.GetNamespace())); err != nil {
+		span.RecordError(err)
+		log.FromContext(ctx).Error(err, "unable to list providers referencing secret", "secret", secret.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for i := range providers.Items {
+		provider := &providers.Items[i]
+		if providerReadsSecret(provider, secret.GetName()) {
+			requests = append(requests, r.findAlertsForProvider(provider)...)
+		}
+	}
+
+	return requests
+}
+
+// providerReadsSecret tells whether given provider reads its address or its SMTP password from the named secret.
+func providerReadsSecret(provider *skynewzdevv1alpha1.NotificationProvider, name string) bool {
+	if ref := provider.Spec.AddressSecretRef; ref != nil && ref.Name == name {
+		return true
+	}
+
+	if smtp := provider.Spec.SMTP; smtp != nil && smtp.PasswordSecretRef != nil && smtp.PasswordSecretRef.Name == name {
+		return true
+	}
+
+	return false
+}
+
+// checkAlert returns the Ready condition of given alert. Errors are only returned when the check can be retried.
+func (r *AlertReconciler) checkAlert(ctx context.Context, alert *skynewzdevv1alpha1.Alert) (metav1.Condition, error) {
+	ctx, span := tracer.Start(ctx, "controllers.AlertReconciler.checkAlert")
+	defer span.End()
+
+	if err := validateAlertSpec(alert.Spec); err != nil {
+		return makeAlertReadyCondition(metav1.ConditionFalse, AlertInvalidSpec, err.Error()), nil
+	}
+
+	provider := new(skynewzdevv1alpha1.NotificationProvider)
+	if err := r.Get(ctx, types.NamespacedName{Name: alert.Spec.ProviderRef.Name, Namespace: alert.Namespace}, provider); err != nil {
+		if apierrors.IsNotFound(err) {
+			message := fmt.Sprintf("provider %q not found", alert.Spec.ProviderRef.Name)
+			return makeAlertReadyCondition(metav1.ConditionFalse, AlertProviderNotFound, message), nil
+		}
+
+		span.RecordError(err)
+		return metav1.Condition{}, fmt.Errorf("cannot get provider %q: %w", alert.Spec.ProviderRef.Name, err)
+	}
+
+	// the notifier is only built to check the provider, it is never used
+	if _, err := makeNotifier(ctx, r, http.DefaultClient, provider); err != nil {
+		return makeAlertReadyCondition(metav1.ConditionFalse, AlertInvalidProvider, err.Error()), nil
+	}
+
+	return makeAlertReadyCondition(metav1.ConditionTrue, AlertProviderReady, ""), nil
+}
+
+// isSameCondition tells whether given conditions already hold given condition, with the same status and reason.
+func isSameCondition(conditions []metav1.Condition, condition metav1.Condition) bool {
+	current := meta.FindStatusCondition(conditions, condition.Type)
+	return current != nil && current.Status == condition.Status && current.Reason == condition.Reason
+}
+
+// validateAlertSpec ensures the template, feed selector and rate limit of an alert are valid.
+func validateAlertSpec(spec skynewzdevv1alpha1.AlertSpec) error {
+	if _, err := parseAlertTemplate(spec.Template); err != nil {
+		return err
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(spec.FeedSelector); err != nil {
+		return fmt.Errorf("invalid feed_selector: %w", err)
+	}
+
+	if spec.RateLimit != nil && spec.RateLimit.Every.Duration <= 0 {
+		return errInvalidAlertRateLimit
+	}
+
+	return nil
+}
+
+// validateProviderSpec ensures a provider is given the settings its type requires.
+func validateProviderSpec(spec skynewzdevv1alpha1.NotificationProviderSpec) error {
+	if spec.Type == skynewzdevv1alpha1.NotificationProviderSMTP {
+		switch {
+		case spec.SMTP == nil:
+			return errMissingSMTPSettings
+		case spec.SMTP.PasswordSecretRef != nil && spec.SMTP.Username == "":
+			return errMissingSMTPUsername
+		default:
+			return nil
+		}
+	}
+
+	switch {
+	case spec.Address == "" && spec.AddressSecretRef == nil:
+		return errMissingProviderAddress
+	case spec.Address != "" && spec.AddressSecretRef != nil:
+		return errProviderAddressConflict
+	case spec.Address != "":
+		return validateWebhookURL(spec.Address)
+	default:
+		return nil
+	}
+}
+
+// validateWebhookURL ensures given webhook URL can be posted to. The URL is left out of the error, as it may embed a token.
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errInvalidWebhookURL
+	}
+
+	return nil
+}
+
+// makeNotifier returns the notifier delivering notifications with given provider, reading its secrets.
+// Webhooks are posted with given HTTP client.
+func makeNotifier(ctx context.Context, c client.Reader, httpClient *http.Client, provider *skynewzdevv1alpha1.NotificationProvider) (notifier.Notifier, error) {
+	ctx, span := tracer.Start(ctx, "controllers.makeNotifier")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("provider.type", string(provider.Spec.Type)))
+
+	spec := provider.Spec
+	if err := validateProviderSpec(spec); err != nil {
+		return nil, err
+	}
+
+	if spec.Type == skynewzdevv1alpha1.NotificationProviderSMTP {
+		config := notifier.SMTPConfig{
+			Host:     spec.SMTP.Host,
+			Port:     int(spec.SMTP.Port),
+			From:     spec.SMTP.From,
+			To:       spec.SMTP.To,
+			Username: spec.SMTP.Username,
+		}
+		if config.Port == 0 {
+			config.Port = defaultSMTPPort
+		}
+
+		if ref := spec.SMTP.PasswordSecretRef; ref != nil {
+			password, err := readSecretKey(ctx, c, provider.Namespace, ref.Name, ref.Key)
+			if err != nil {
+				span.RecordError(err)
+				return nil, err
+			}
+
+			config.Password = string(password)
+		}
+
+		return notifier.NewSMTP(config), nil
+	}
+
+	address := spec.Address
+	if ref := spec.AddressSecretRef; ref != nil {
+		value, err := readSecretKey(ctx, c, provider.Namespace, ref.Name, ref.Key)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		address = strings.TrimSpace(string(value))
+		if err := validateWebhookURL(address); err != nil {
+			return nil, err
+		}
+	}
+
+	switch spec.Type {
+	case skynewzdevv1alpha1.NotificationProviderSlack:
+		return notifier.NewSlack(httpClient, address), nil
+	case skynewzdevv1alpha1.NotificationProviderDiscord:
+		return notifier.NewDiscord(httpClient, address), nil
+	default:
+		return notifier.NewGeneric(httpClient, address), nil
+	}
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_validateAlertSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    skynewzdevv1alpha1.AlertSpec
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			spec:    skynewzdevv1alpha1.AlertSpec{ProviderRef: skynewzdevv1alpha1.ProviderReference{Name: "slack"}},
+			wantErr: false,
+		},
+		{
+			name: "every field",
+			spec: skynewzdevv1alpha1.AlertSpec{
+				ProviderRef:  skynewzdevv1alpha1.ProviderReference{Name: "slack"},
+				FeedSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "putio"}},
+				Events:       []skynewzdevv1alpha1.AlertEventType{skynewzdevv1alpha1.AlertEventError},
+				Template:     "{{ .Feed.Spec.Title }}: {{ .Message }}",
+				RateLimit:    &skynewzdevv1alpha1.AlertRateLimit{Burst: 5, Every: metav1.Duration{Duration: time.Minute}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid template",
+			spec:    skynewzdevv1alpha1.AlertSpec{Template: "{{ .Message"},
+			wantErr: true,
+		},
+		{
+			name: "invalid selector",
+			spec: skynewzdevv1alpha1.AlertSpec{FeedSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "no rate",
+			spec:    skynewzdevv1alpha1.AlertSpec{RateLimit: &skynewzdevv1alpha1.AlertRateLimit{Burst: 5}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAlertSpec(tt.spec); (err != nil) != tt.wantErr {
+				t.Errorf("validateAlertSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateProviderSpec(t *testing.T) {
+	secretRef := &skynewzdevv1alpha1.SecretKeyReference{Name: "webhook", Key: "url"}
+
+	tests := []struct {
+		name    string
+		spec    skynewzdevv1alpha1.NotificationProviderSpec
+		wantErr error
+	}{
+		{
+			name:    "webhook address",
+			spec:    skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderSlack, Address: "https://hooks.slack.com/services/T000/B000/XXXX"},
+			wantErr: nil,
+		},
+		{
+			name:    "webhook address from secret",
+			spec:    skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderDiscord, AddressSecretRef: secretRef},
+			wantErr: nil,
+		},
+		{
+			name:    "no address",
+			spec:    skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderGeneric},
+			wantErr: errMissingProviderAddress,
+		},
+		{
+			name:    "both addresses",
+			spec:    skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderGeneric, Address: "https://example.com", AddressSecretRef: secretRef},
+			wantErr: errProviderAddressConflict,
+		},
+		{
+			name:    "not an http address",
+			spec:    skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderGeneric, Address: "ftp://example.com"},
+			wantErr: errInvalidWebhookURL,
+		},
+		{
+			name: "smtp",
+			spec: skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderSMTP, SMTP: &skynewzdevv1alpha1.SMTPSettings{
+				Host:              "smtp.example.com",
+				From:              "putio-operator@example.com",
+				To:                []string{"team@example.com"},
+				Username:          "putio-operator",
+				PasswordSecretRef: &skynewzdevv1alpha1.SecretKeyReference{Name: "smtp", Key: "password"},
+			}},
+			wantErr: nil,
+		},
+		{
+			name:    "smtp without settings",
+			spec:    skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderSMTP, Address: "https://example.com"},
+			wantErr: errMissingSMTPSettings,
+		},
+		{
+			name: "smtp password without username",
+			spec: skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderSMTP, SMTP: &skynewzdevv1alpha1.SMTPSettings{
+				Host:              "smtp.example.com",
+				From:              "putio-operator@example.com",
+				To:                []string{"team@example.com"},
+				PasswordSecretRef: &skynewzdevv1alpha1.SecretKeyReference{Name: "smtp", Key: "password"},
+			}},
+			wantErr: errMissingSMTPUsername,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateProviderSpec(tt.spec); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateProviderSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlertReconciler_findAlertsForSecret(t *testing.T) {
+	provider := func(name string, spec skynewzdevv1alpha1.NotificationProviderSpec) skynewzdevv1alpha1.NotificationProvider {
+		return skynewzdevv1alpha1.NotificationProvider{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Spec: spec}
+	}
+	alert := func(name, provider string) skynewzdevv1alpha1.Alert {
+		return skynewzdevv1alpha1.Alert{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       skynewzdevv1alpha1.AlertSpec{ProviderRef: skynewzdevv1alpha1.ProviderReference{Name: provider}},
+		}
+	}
+
+	reader := &listReader{
+		providers: []skynewzdevv1alpha1.NotificationProvider{
+			provider("slack", skynewzdevv1alpha1.NotificationProviderSpec{AddressSecretRef: &skynewzdevv1alpha1.SecretKeyReference{Name: "slack-webhook", Key: "url"}}),
+			provider("mail", skynewzdevv1alpha1.NotificationProviderSpec{SMTP: &skynewzdevv1alpha1.SMTPSettings{PasswordSecretRef: &skynewzdevv1alpha1.SecretKeyReference{Name: "smtp", Key: "password"}}}),
+			provider("discord", skynewzdevv1alpha1.NotificationProviderSpec{Address: "https://discord.example.com/webhook"}),
+		},
+		alerts: []skynewzdevv1alpha1.Alert{alert("downloads", "slack"), alert("errors", "mail"), alert("stale", "discord")},
+	}
+	r := &AlertReconciler{Client: listClient{reader: reader}}
+
+	tests := []struct {
+		secret string
+		want   []reconcile.Request
+	}{
+		{secret: "slack-webhook", want: []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "downloads", Namespace: "default"}}}},
+		{secret: "smtp", want: []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "errors", Namespace: "default"}}}},
+		{secret: "putio-token", want: []reconcile.Request{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.secret, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: tt.secret, Namespace: "default"}}
+			if diff := cmp.Diff(tt.want, r.findAlertsForSecret(secret)); diff != "" {
+				t.Errorf("findAlertsForSecret() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,14 +1,60 @@
 package controllers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/http"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	errAccountNotAllowed      = errors.New("namespace is not allowed to use this account")
+	errMissingAuthentication  = errors.New("one of authSecretRef or accountRef is required")
+	errFolderNotFound         = errors.New("folder not found")
+	errFolderNotReady         = errors.New("folder is not ready yet")
+	errNotADirectory          = errors.New("file is not a folder")
+	errFolderNotOwned         = errors.New("folder is shared with the account, not owned by it")
+	errAdoptedFeedNotFound    = errors.New("feed to adopt not found")
+	errAdoptedFeedClaimed     = errors.New("feed to adopt is owned by another Feed")
+	errSecretKeyMissing       = errors.New("key not found in secret")
+	errFeedNotOwnedByTemplate = errors.New("feed already exists and is not generated by this template")
+
+	errMissingRetentionFolder   = errors.New("one of folder_id or path is required")
+	errRetentionFolderConflict  = errors.New("folder_id cannot be used along with path")
+	errRetentionRootFolder      = errors.New("the root folder cannot be pruned")
+	errMissingRetentionRule     = errors.New("one of max_age, keep_last or max_total_size is required")
+	errInvalidRetentionInterval = errors.New("interval must be positive")
+
+	errMissingProviderAddress  = errors.New("one of address or addressSecretRef is required")
+	errProviderAddressConflict = errors.New("address cannot be used along with addressSecretRef")
+	errInvalidWebhookURL       = errors.New("address must be an http or https URL")
+	errMissingSMTPSettings     = errors.New("smtp is required by the SMTP type")
+	errMissingSMTPUsername     = errors.New("smtp.username is required along with smtp.passwordSecretRef")
+	errInvalidAlertRateLimit   = errors.New("rate_limit.every must be positive")
 )
 
-const finalizerAnnotation string = "feed.skynewz.dev/finalizer"
+const (
+	finalizerAnnotation         string = "feed.skynewz.dev/finalizer"
+	transferFinalizerAnnotation string = "transfer.skynewz.dev/finalizer"
+
+	// adoptIDAnnotation holds the ID of an existing Put.io feed to take ownership of.
+	adoptIDAnnotation string = "feed.skynewz.dev/adopt-id"
+
+	// feedTemplateLabel holds the name of the FeedTemplate a Feed is generated from.
+	feedTemplateLabel string = "feedtemplate.skynewz.dev/name"
+)
 
 const (
 	eventReconciliationStarted string = "ReconciliationStarted"
 	eventUnableToGetAuthSecret string = "UnableToGetAuthSecret" //nolint:gosec
+	eventUnableToGetAccount    string = "UnableToGetAccount"
 
 	// finalizer events.
 	eventAddedFinalizer          string = "InstanceFinalizerAdded"
@@ -20,6 +66,25 @@ const (
 	eventUnableToCreateOrUpdatedAtPutio     string = "UnableToCreateOrUpdatedAtPutio"
 	eventSuccessfullyCreateOrUpdatedAtPutio string = "SuccessfullyCreateOrUpdatedAtPutio"
 
+	// title migration event.
+	eventFeedTitleMigrated string = "FeedTitleMigrated"
+
+	// adoption events.
+	eventFeedAdopted       string = "FeedAdopted"
+	eventUnableToAdoptFeed string = "UnableToAdoptFeed"
+
+	// orphaned feed events.
+	eventOrphanedFeedFound          string = "OrphanedFeedFound"
+	eventOrphanedFeedDeleted        string = "OrphanedFeedDeleted"
+	eventUnableToDeleteOrphanedFeed string = "UnableToDeleteOrphanedFeed"
+
+	// source URL event.
+	eventUnableToGetRSSSourceURL string = "UnableToGetRSSSourceURL"
+
+	// preview events.
+	eventFeedPreviewed       string = "FeedPreviewed"
+	eventUnableToPreviewFeed string = "UnableToPreviewFeed"
+
 	// deletion event.
 	eventDeleteFeedAtPutio          string = "DeleteFeedAtPutio"
 	eventUnableToDeleteAtPutio      string = "UnableToDeleteAtPutio"
@@ -34,12 +99,81 @@ const (
 	eventFeedStatus                    string = "FeedStatus"
 	eventUnableToUpdateFeedStatus      string = "UnableToUpdateFeedStatus"
 	eventFeedStatusSuccessfullyUpdated string = "FeedStatusSuccessfullyUpdated"
+
+	// drift detection.
+	eventDriftDetected              string = "DriftDetected"
+	eventUnableToCorrectDrift       string = "UnableToCorrectDrift"
+	eventDriftSuccessfullyCorrected string = "DriftSuccessfullyCorrected"
+
+	// transfer events.
+	eventAddTransferAtPutio            string = "AddTransferAtPutio"
+	eventUnableToAddTransferAtPutio    string = "UnableToAddTransferAtPutio"
+	eventSuccessfullyAddedAtPutio      string = "SuccessfullyAddedAtPutio"
+	eventUnableToGetTransferAtPutio    string = "UnableToGetTransferAtPutio"
+	eventRetryTransferAtPutio          string = "RetryTransferAtPutio"
+	eventUnableToRetryTransferAtPutio  string = "UnableToRetryTransferAtPutio"
+	eventCancelTransferAtPutio         string = "CancelTransferAtPutio"
+	eventUnableToCancelTransferAtPutio string = "UnableToCancelTransferAtPutio"
+	eventSuccessfullyCanceledAtPutio   string = "SuccessfullyCanceledAtPutio"
+	eventUnableToUpdateTransferStatus  string = "UnableToUpdateTransferStatus"
+	eventTransferSuccessfullyCompleted string = "TransferSuccessfullyCompleted"
+	eventTransferFailed                string = "TransferFailed"
+
+	// account events.
+	eventAccountVerified       string = "AccountVerified"
+	eventUnableToVerifyAccount string = "UnableToVerifyAccount"
+
+	// folder events.
+	eventFolderResolved           string = "FolderResolved"
+	eventUnableToResolveFolder    string = "UnableToResolveFolder"
+	eventUnableToResolveParentDir string = "UnableToResolveParentDir"
+	eventParentDirCreated         string = "ParentDirCreated"
+
+	// feed template events.
+	eventUnableToRenderFeedTemplate string = "UnableToRenderFeedTemplate"
+	eventUnableToApplyTemplateFeed  string = "UnableToApplyTemplateFeed"
+	eventUnableToPruneTemplateFeed  string = "UnableToPruneTemplateFeed"
+	eventTemplateFeedCreated        string = "TemplateFeedCreated"
+	eventTemplateFeedUpdated        string = "TemplateFeedUpdated"
+	eventTemplateFeedPruned         string = "TemplateFeedPruned"
+
+	// disk quota events.
+	eventFeedQuotaPaused  string = "FeedQuotaPaused"
+	eventFeedQuotaResumed string = "FeedQuotaResumed"
+
+	// schedule events.
+	eventInvalidSchedule string = "InvalidSchedule"
+	eventFeedScheduled   string = "FeedScheduled"
+
+	// feed items event.
+	eventUnableToCountItems string = "UnableToCountItems"
+
+	// expiry event.
+	eventFeedExpired string = "FeedExpired"
+
+	// notification events.
+	eventInvalidAlert             string = "InvalidAlert"
+	eventUnableToSendNotification string = "UnableToSendNotification"
+	eventNotificationRateLimited  string = "NotificationRateLimited"
+	eventNotificationDropped      string = "NotificationDropped"
+
+	// folder retention policy events.
+	eventInvalidRetentionPolicy string = "InvalidRetentionPolicy"
+	eventFilesPruned            string = "FilesPruned"
+	eventUnableToPruneFiles     string = "UnableToPruneFiles"
 )
 
 type FeedConditionType string
 
 const (
 	FeedAvailable FeedConditionType = "Available"
+	FeedDrifted   FeedConditionType = "Drifted"
+	FeedAuthReady FeedConditionType = "AuthReady"
+
+	FeedParentDirReady FeedConditionType = "ParentDirReady"
+	FeedQuotaPaused    FeedConditionType = "QuotaPaused"
+	FeedScheduleActive FeedConditionType = "ScheduleActive"
+	FeedExpired        FeedConditionType = "Expired"
 )
 
 type FeedConditionReason string
@@ -47,6 +181,124 @@ type FeedConditionReason string
 const (
 	FeedSuccessfullyDeployed FeedConditionReason = "FeedSuccessfullyDeployed"
 	FeedFailedToDeploy       FeedConditionReason = "FeedFailedToDeploy"
+	FeedDriftDetected        FeedConditionReason = "FeedDriftDetected"
+	FeedInSync               FeedConditionReason = "FeedInSync"
+	FeedTokenAccepted        FeedConditionReason = "TokenAccepted"
+	FeedSecretNotFound       FeedConditionReason = "SecretNotFound"
+	FeedSecretKeyMissing     FeedConditionReason = "SecretKeyMissing"
+	FeedAccountUnavailable   FeedConditionReason = "AccountUnavailable"
+	FeedTokenRejected        FeedConditionReason = "TokenRejected"
+	FeedTokenCheckFailed     FeedConditionReason = "TokenCheckFailed"
+	FeedPreviewing           FeedConditionReason = "Previewing"
+	FeedPreviewFailed        FeedConditionReason = "PreviewFailed"
+	FeedSourceURLUnavailable FeedConditionReason = "SourceURLUnavailable"
+
+	FeedParentDirFound         FeedConditionReason = "ParentDirFound"
+	FeedParentDirCreated       FeedConditionReason = "ParentDirCreated"
+	FeedParentDirNotFound      FeedConditionReason = "ParentDirNotFound"
+	FeedParentDirNotADirectory FeedConditionReason = "NotADirectory"
+	FeedParentDirNotOwned      FeedConditionReason = "NotOwnedByAccount"
+	FeedParentFolderNotReady   FeedConditionReason = "FolderNotReady"
+	FeedParentDirCheckFailed   FeedConditionReason = "ParentDirCheckFailed"
+
+	FeedQuotaExceeded  FeedConditionReason = "QuotaExceeded"
+	FeedQuotaAvailable FeedConditionReason = "QuotaAvailable"
+
+	FeedInsideWindow    FeedConditionReason = "InsideWindow"
+	FeedOutsideWindow   FeedConditionReason = "OutsideWindow"
+	FeedInvalidSchedule FeedConditionReason = "InvalidSchedule"
+
+	FeedNotExpired        FeedConditionReason = "NotExpired"
+	FeedExpiryDateReached FeedConditionReason = "ExpiryDateReached"
+	FeedMatchesReached    FeedConditionReason = "MatchesReached"
+	FeedInactive          FeedConditionReason = "Inactive"
+)
+
+type AccountConditionType string
+
+const (
+	AccountReady AccountConditionType = "Ready"
+)
+
+type AccountConditionReason string
+
+const (
+	AccountTokenAccepted AccountConditionReason = "TokenAccepted"
+	AccountTokenMissing  AccountConditionReason = "TokenMissing"
+	AccountTokenRejected AccountConditionReason = "TokenRejected"
+)
+
+type FolderConditionType string
+
+const (
+	FolderReady FolderConditionType = "Ready"
+)
+
+type FolderConditionReason string
+
+const (
+	FolderResolved        FolderConditionReason = "FolderResolved"
+	FolderFailedToResolve FolderConditionReason = "FolderFailedToResolve"
+)
+
+type FeedTemplateConditionType string
+
+const (
+	FeedTemplateReady FeedTemplateConditionType = "Ready"
+)
+
+type FeedTemplateConditionReason string
+
+const (
+	FeedTemplateRendered       FeedTemplateConditionReason = "FeedsRendered"
+	FeedTemplateFailedToRender FeedTemplateConditionReason = "FailedToRender"
+	FeedTemplateFailedToApply  FeedTemplateConditionReason = "FailedToApply"
+)
+
+type RetentionPolicyConditionType string
+
+const (
+	RetentionPolicyReady RetentionPolicyConditionType = "Ready"
+)
+
+type RetentionPolicyConditionReason string
+
+const (
+	RetentionPolicyEnforced        RetentionPolicyConditionReason = "PolicyEnforced"
+	RetentionPolicyDryRun          RetentionPolicyConditionReason = "DryRun"
+	RetentionPolicyInvalid         RetentionPolicyConditionReason = "InvalidPolicy"
+	RetentionPolicyFailedToResolve RetentionPolicyConditionReason = "FailedToResolveFolder"
+	RetentionPolicyFailedToPrune   RetentionPolicyConditionReason = "FailedToPrune"
+)
+
+type AlertConditionType string
+
+const (
+	AlertReady AlertConditionType = "Ready"
+)
+
+type AlertConditionReason string
+
+const (
+	AlertProviderReady    AlertConditionReason = "ProviderReady"
+	AlertProviderNotFound AlertConditionReason = "ProviderNotFound"
+	AlertInvalidProvider  AlertConditionReason = "InvalidProvider"
+	AlertInvalidSpec      AlertConditionReason = "InvalidAlert"
+)
+
+type TransferConditionType string
+
+const (
+	TransferCompleted TransferConditionType = "Completed"
+)
+
+type TransferConditionReason string
+
+const (
+	TransferInProgress        TransferConditionReason = "TransferInProgress"
+	TransferSucceeded         TransferConditionReason = "TransferSucceeded"
+	TransferFailed            TransferConditionReason = "TransferFailed"
+	TransferFailedToBeCreated TransferConditionReason = "TransferFailedToBeCreated"
 )
 
 func makeFeedAvailableCondition(status metav1.ConditionStatus, reason FeedConditionReason, message string) metav1.Condition {
@@ -57,3 +309,168 @@ func makeFeedAvailableCondition(status metav1.ConditionStatus, reason FeedCondit
 		Message: message,
 	}
 }
+
+func makeFeedDriftedCondition(status metav1.ConditionStatus, reason FeedConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(FeedDrifted),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeFeedAuthReadyCondition(status metav1.ConditionStatus, reason FeedConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(FeedAuthReady),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeFeedParentDirReadyCondition(status metav1.ConditionStatus, reason FeedConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(FeedParentDirReady),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeFeedQuotaPausedCondition(status metav1.ConditionStatus, reason FeedConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(FeedQuotaPaused),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeFeedScheduleActiveCondition(status metav1.ConditionStatus, reason FeedConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(FeedScheduleActive),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeFeedExpiredCondition(status metav1.ConditionStatus, reason FeedConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(FeedExpired),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeRetentionPolicyReadyCondition(status metav1.ConditionStatus, reason RetentionPolicyConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(RetentionPolicyReady),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeAlertReadyCondition(status metav1.ConditionStatus, reason AlertConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(AlertReady),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeTransferCompletedCondition(status metav1.ConditionStatus, reason TransferConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(TransferCompleted),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeAccountReadyCondition(status metav1.ConditionStatus, reason AccountConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(AccountReady),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeFolderReadyCondition(status metav1.ConditionStatus, reason FolderConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(FolderReady),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+func makeFeedTemplateReadyCondition(status metav1.ConditionStatus, reason FeedTemplateConditionReason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(FeedTemplateReady),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+// makePutioClientFromSecret reads the Put.io token referenced by given ref in given namespace
+// and returns a client authenticated with it.
+func makePutioClientFromSecret(ctx context.Context, c client.Reader, namespace string, ref skynewzdevv1alpha1.AuthSecretReference) (*putio.Client, error) {
+	ctx, span := tracer.Start(ctx, "controllers.makePutioClientFromSecret")
+	defer span.End()
+
+	token, err := readSecretKey(ctx, c, namespace, ref.Name, ref.Key)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	httpClient := http.NewHTTPClient(string(token))
+	return putio.New(ctx, httpClient), nil
+}
+
+// readSecretKey returns the value of given key of the secret named name in given namespace.
+func readSecretKey(ctx context.Context, c client.Reader, namespace, name, key string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "controllers.readSecretKey")
+	defer span.End()
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("cannot get secret %q: %w", name, err)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		span.RecordError(errSecretKeyMissing)
+		return nil, fmt.Errorf("cannot read %q from secret %q: %w", key, name, errSecretKeyMissing)
+	}
+
+	return value, nil
+}
+
+// makePutioClientFromAccount returns a client authenticated with the token of given PutioAccount,
+// as long as the account allows given namespace.
+func makePutioClientFromAccount(ctx context.Context, c client.Reader, namespace string, ref skynewzdevv1alpha1.AccountReference) (*putio.Client, error) {
+	ctx, span := tracer.Start(ctx, "controllers.makePutioClientFromAccount")
+	defer span.End()
+
+	account := &skynewzdevv1alpha1.PutioAccount{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, account); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("cannot get account %q: %w", ref.Name, err)
+	}
+
+	if !account.IsNamespaceAllowed(namespace) {
+		span.RecordError(errAccountNotAllowed)
+		return nil, fmt.Errorf("cannot use account %q from namespace %q: %w", ref.Name, namespace, errAccountNotAllowed)
+	}
+
+	tokenRef := account.Spec.TokenSecretRef
+	return makePutioClientFromSecret(ctx, c, tokenRef.Namespace, skynewzdevv1alpha1.AuthSecretReference{Name: tokenRef.Name, Key: tokenRef.Key})
+}
@@ -18,18 +18,22 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
-	"github.com/SkYNewZ/putio-operator/internal/http"
 	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"github.com/SkYNewZ/putio-operator/internal/rss"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,29 +42,46 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-)
-
-const (
-	// <wanted title>|generation|managed by Kubernetes/putio-operator.
-	titleFormat    = "%s|%d|managed by Kubernetes/putio-operator"
-	titleSeparator = "|"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var tracer = otel.GetTracerProvider().Tracer("controller")
 
-var errCannotDeleteFeedWithoutID = errors.New("cannot delete Feed without its ID")
+// secretNameField indexes feeds by the names of the secrets they reference, for authentication or their source URL.
+const secretNameField = ".spec.secretNames"
+
+// accountNameField indexes feeds by the name of the PutioAccount they reference.
+const accountNameField = ".spec.accountRef.name"
+
+// minRequeueAfter delays the reconciliation of a feed whose schedule transition or expiry is already due.
+const minRequeueAfter = time.Second
 
 // FeedReconciler reconciles a Feed object.
 type FeedReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+
+	// ResyncInterval is the period after which a reconciled feed is compared again
+	// with its Put.io counterpart to detect drift. Zero disables periodic resync.
+	ResyncInterval time.Duration
+
+	// TitleTemplate renders the title of Put.io feeds. Default to DefaultFeedTitleTemplate.
+	TitleTemplate *FeedTitleTemplate
+
+	// HTTPClient fetches RSS feeds to preview. Default to a client with a 30 seconds timeout.
+	HTTPClient *http.Client
 }
 
 //+kubebuilder:rbac:groups=putio.skynewz.dev,resources=feeds,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=putio.skynewz.dev,resources=feeds/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=putio.skynewz.dev,resources=feeds/finalizers,verbs=update
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=putioaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=folders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=alerts,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
@@ -87,15 +108,6 @@ func (r *FeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 	r.Recorder.Event(k8sFeed, corev1.EventTypeNormal, eventReconciliationStarted, "starting reconciliation")
 
-	logger.Info("Setting up put.io client with feed secret")
-	clientAuthSecret := &corev1.Secret{}
-	if err := r.Get(ctx, types.NamespacedName{Name: k8sFeed.AuthSecretRef().Name, Namespace: req.Namespace}, clientAuthSecret); err != nil {
-		span.RecordError(err)
-		r.Recorder.Eventf(k8sFeed, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
-		return ctrl.Result{}, fmt.Errorf("cannot get secret %q: %w", k8sFeed.AuthSecretRef().Name, err)
-	}
-	putioClient := r.makePutioClient(ctx, string(clientAuthSecret.Data[k8sFeed.AuthSecretRef().Key]))
-
 	// examine DeletionTimestamp to determine if object is under deletion
 	if k8sFeed.ObjectMeta.DeletionTimestamp.IsZero() {
 		// The object is not being deleted, so if it does not have our finalizer,
@@ -114,8 +126,8 @@ func (r *FeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		// The object is being deleted
 		if controllerutil.ContainsFinalizer(k8sFeed, finalizerAnnotation) {
 			// our finalizer is present, so lets handle any external dependency
-			r.Recorder.Event(k8sFeed, corev1.EventTypeNormal, eventDeleteFeedAtPutio, "deleting feed at putio")
-			result, err := r.deleteFeed(ctx, k8sFeed, putioClient)
+			r.Recorder.Eventf(k8sFeed, corev1.EventTypeNormal, eventDeleteFeedAtPutio, "deleting feed at putio with %q policy", k8sFeed.Spec.DeletionPolicy)
+			result, err := r.deleteFeed(ctx, k8sFeed)
 			if err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
@@ -139,8 +151,56 @@ func (r *FeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, nil
 	}
 
+	// only list what the feed would match, without touching Put.io
+	if k8sFeed.Spec.Preview {
+		return r.previewFeed(ctx, k8sFeed)
+	}
+
+	now := time.Now()
+	if err := r.applySchedule(ctx, k8sFeed, now); err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	putioClient, err := r.authenticate(ctx, k8sFeed)
+	if err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.countItems(ctx, putioClient, k8sFeed); err != nil {
+		r.Recorder.Event(k8sFeed, corev1.EventTypeWarning, eventUnableToCountItems, err.Error())
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	r.checkExpiry(ctx, k8sFeed, now)
+
+	// the finalizer applies the deletion policy to the Put.io feed
+	if isExpired(k8sFeed) && expiryAction(k8sFeed) == skynewzdevv1alpha1.ExpiryActionDelete {
+		logger.Info("Deleting expired feed")
+		if err := r.Delete(ctx, k8sFeed); err != nil {
+			span.RecordError(err)
+			return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.resolveParentDir(ctx, k8sFeed, putioClient); err != nil {
+		r.Recorder.Event(k8sFeed, corev1.EventTypeWarning, eventUnableToResolveParentDir, err.Error())
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	rssSourceURL, err := r.resolveRSSSourceURL(ctx, k8sFeed)
+	if err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
 	r.Recorder.Event(k8sFeed, corev1.EventTypeNormal, eventCreateOrUpdatedAtPutio, "handling feed creation/update")
-	putioFeed, err := r.createOrUpdateFeed(ctx, k8sFeed, putioClient)
+	putioFeed, err := r.createOrUpdateFeed(ctx, k8sFeed, putioClient, rssSourceURL)
 	if err != nil {
 		r.Recorder.Event(k8sFeed, corev1.EventTypeWarning, eventUnableToCreateOrUpdatedAtPutio, err.Error())
 		return ctrl.Result{}, err
@@ -155,43 +215,187 @@ func (r *FeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	r.Recorder.Event(k8sFeed, corev1.EventTypeNormal, eventFeedStatusSuccessfullyUpdated, "feed status successfully set")
 
 	logger.Info("Feed successfully reconciled")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.requeueAfter(k8sFeed, now)}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *FeedReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	_, span := tracer.Start(context.Background(), "controllers.FeedReconciler.SetupWithManager")
+	ctx, span := tracer.Start(context.Background(), "controllers.FeedReconciler.SetupWithManager")
 	defer span.End()
 
+	// index feeds by referenced secret, to find the feeds to reconcile when a secret changes
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &skynewzdevv1alpha1.Feed{}, secretNameField, func(o client.Object) []string {
+		feed, ok := o.(*skynewzdevv1alpha1.Feed)
+		if !ok {
+			return nil
+		}
+
+		return feedSecretNames(feed)
+	}); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot index feeds by secret: %w", err)
+	}
+
+	// index feeds by referenced account, to find the feeds to reconcile when the token secret of an account changes
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &skynewzdevv1alpha1.Feed{}, accountNameField, func(o client.Object) []string {
+		feed, ok := o.(*skynewzdevv1alpha1.Feed)
+		if !ok || feed.Spec.AccountRef == nil {
+			return nil
+		}
+
+		return []string{feed.Spec.AccountRef.Name}
+	}); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot index feeds by account: %w", err)
+	}
+
 	//nolint:wrapcheck
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&skynewzdevv1alpha1.Feed{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.findFeedsForSecret)).
 		Complete(r)
 }
 
-func (r *FeedReconciler) deleteFeed(ctx context.Context, feed *skynewzdevv1alpha1.Feed, putioClient *putio.Client) (ctrl.Result, error) {
+// findFeedsForSecret returns a request for each feed of the secret namespace referencing it,
+// and for each feed using a PutioAccount whose token is held by the secret.
+func (r *FeedReconciler) findFeedsForSecret(secret client.Object) []reconcile.Request {
+	ctx, span := tracer.Start(context.Background(), "controllers.FeedReconciler.findFeedsForSecret")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("secret.name", secret.GetName()),
+		attribute.String("secret.namespace", secret.GetNamespace()),
+	)
+
+	feeds := new(skynewzdevv1alpha1.FeedList)
+	if err := r.List(ctx, feeds, client.InNamespace(secret.GetNamespace()), client.MatchingFields{secretNameField: secret.GetName()}); err != nil {
+		span.RecordError(err)
+		log.FromContext(ctx).Error(err, "unable to list feeds referencing secret", "secret", secret.GetName())
+		return nil
+	}
+
+	requests := makeFeedRequests(feeds.Items)
+
+	accounts := new(skynewzdevv1alpha1.PutioAccountList)
+	if err := r.List(ctx, accounts); err != nil {
+		span.RecordError(err)
+		log.FromContext(ctx).Error(err, "unable to list accounts", "secret", secret.GetName())
+		return requests
+	}
+
+	for _, account := range accounts.Items {
+		if ref := account.Spec.TokenSecretRef; ref.Name != secret.GetName() || ref.Namespace != secret.GetNamespace() {
+			continue
+		}
+
+		feeds := new(skynewzdevv1alpha1.FeedList)
+		if err := r.List(ctx, feeds, client.MatchingFields{accountNameField: account.Name}); err != nil {
+			span.RecordError(err)
+			log.FromContext(ctx).Error(err, "unable to list feeds referencing account", "account", account.Name)
+			continue
+		}
+
+		requests = append(requests, makeFeedRequests(feeds.Items)...)
+	}
+
+	return requests
+}
+
+// makeFeedRequests returns a reconcile request for each given feed.
+func makeFeedRequests(feeds []skynewzdevv1alpha1.Feed) []reconcile.Request {
+	requests := make([]reconcile.Request, len(feeds))
+	for i, feed := range feeds {
+		requests[i] = reconcile.Request{NamespacedName: types.NamespacedName{Name: feed.Name, Namespace: feed.Namespace}}
+	}
+
+	return requests
+}
+
+// feedSecretNames returns the names of the secrets referenced by given feed.
+func feedSecretNames(feed *skynewzdevv1alpha1.Feed) []string {
+	names := make([]string, 0, 2)
+	if feed.Spec.AuthSecretRef != nil {
+		names = append(names, feed.Spec.AuthSecretRef.Name)
+	}
+
+	if from := feed.Spec.RssSourceURLFrom; from != nil && (feed.Spec.AuthSecretRef == nil || from.SecretKeyRef.Name != feed.Spec.AuthSecretRef.Name) {
+		names = append(names, from.SecretKeyRef.Name)
+	}
+
+	return names
+}
+
+// deleteFeed applies the deletion policy of the feed to its Put.io counterpart.
+// A feed never created or already deleted at Put.io has nothing left to do, even without valid credentials.
+func (r *FeedReconciler) deleteFeed(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (ctrl.Result, error) {
 	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.deleteFeed")
 	defer span.End()
 
-	span.SetAttributes(attribute.String("action", "delete"))
+	span.SetAttributes(attribute.String("action", "delete"), attribute.String("feed.deletion_policy", string(feed.Spec.DeletionPolicy)))
 
 	logger := log.FromContext(ctx)
-	logger.Info("Deleting feed")
 
 	if feed.Status.ID == nil {
-		return ctrl.Result{Requeue: false}, errCannotDeleteFeedWithoutID
+		logger.Info("Feed has no Put.io ID, nothing to delete")
+		return ctrl.Result{}, nil
 	}
 
 	span.SetAttributes(attribute.Int("feed.status.id", int(*feed.Status.ID)))
 
-	if err := putioClient.Rss.Delete(ctx, *feed.Status.ID); err != nil {
+	putioClient, err := r.makePutioClient(ctx, feed)
+	if err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	// releasing the feed updates it at Put.io, which requires the source URL
+	var rssSourceURL string
+	if feed.Spec.DeletionPolicy == skynewzdevv1alpha1.DeletionPolicyOrphan || feed.Spec.DeletionPolicy == skynewzdevv1alpha1.DeletionPolicyPause {
+		if rssSourceURL, err = r.resolveRSSSourceURL(ctx, feed); err != nil {
+			span.RecordError(err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	switch feed.Spec.DeletionPolicy {
+	case skynewzdevv1alpha1.DeletionPolicyOrphan:
+		logger.Info("Orphaning feed", "id", *feed.Status.ID)
+		err = releaseFeed(ctx, putioClient, feed, rssSourceURL, *feed.Status.ID, r.titleTemplate())
+	case skynewzdevv1alpha1.DeletionPolicyPause:
+		logger.Info("Pausing feed", "id", *feed.Status.ID)
+		if err = putioClient.Rss.Pause(ctx, *feed.Status.ID); err == nil {
+			err = releaseFeed(ctx, putioClient, feed, rssSourceURL, *feed.Status.ID, r.titleTemplate())
+		}
+	default:
+		logger.Info("Deleting feed", "id", *feed.Status.ID)
+		err = putioClient.Rss.Delete(ctx, *feed.Status.ID)
+	}
+
+	if putio.IsNotFound(err) {
+		logger.Info("Put.io feed already deleted", "id", *feed.Status.ID)
+		return ctrl.Result{}, nil
+	}
+
+	if err != nil {
+		span.RecordError(err)
 		return ctrl.Result{RequeueAfter: time.Minute}, fmt.Errorf("failed to delete feed: %w", err)
 	}
 
 	return ctrl.Result{}, nil
 }
 
-func (r *FeedReconciler) createOrUpdateFeed(ctx context.Context, feed *skynewzdevv1alpha1.Feed, putioClient *putio.Client) (*putio.Feed, error) {
+// releaseFeed strips the managed marker from the Put.io feed title so the operator no longer considers it its own.
+func releaseFeed(ctx context.Context, putioClient *putio.Client, feed *skynewzdevv1alpha1.Feed, rssSourceURL string, feedID uint, titles *FeedTitleTemplate) error {
+	ctx, span := tracer.Start(ctx, "controllers.releaseFeed")
+	defer span.End()
+
+	putioFeed := makePutioFeedFromSpec(ctx, feed, rssSourceURL, titles)
+	putioFeed.Title = feed.Spec.Title
+
+	return putioClient.Rss.Update(ctx, putioFeed, feedID) //nolint:wrapcheck
+}
+
+func (r *FeedReconciler) createOrUpdateFeed(ctx context.Context, feed *skynewzdevv1alpha1.Feed, putioClient *putio.Client, rssSourceURL string) (*putio.Feed, error) {
 	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.createOrUpdateFeed")
 	defer span.End()
 
@@ -199,10 +403,13 @@ func (r *FeedReconciler) createOrUpdateFeed(ctx context.Context, feed *skynewzde
 
 	var (
 		logger    = log.FromContext(ctx)
+		hash      = computeSpecHash(ctx, feed, rssSourceURL, r.titleTemplate())
 		putioFeed *putio.Feed
 		err       error
 	)
 
+	span.SetAttributes(attribute.String("feed.spec_hash", hash))
+
 	// search for existing feed
 	if feed.Status.ID != nil {
 		span.SetAttributes(attribute.Int("feed.id", int(*feed.Status.ID)))
@@ -214,12 +421,22 @@ func (r *FeedReconciler) createOrUpdateFeed(ctx context.Context, feed *skynewzde
 		}
 	}
 
+	// feed not found, looking for an existing one to take ownership of
+	if putioFeed == nil {
+		putioFeed, err = r.adoptFeed(ctx, feed, putioClient, rssSourceURL)
+		if err != nil {
+			span.RecordError(err)
+			r.Recorder.Event(feed, corev1.EventTypeWarning, eventUnableToAdoptFeed, err.Error())
+			return nil, err
+		}
+	}
+
 	// feed not found, creating it
 	if putioFeed == nil {
 		span.SetAttributes(attribute.String("action", "create"))
 		logger.Info("Put.io feed not found, creating it", "title", feed.Spec.Title)
 
-		putioFeed, err = putioClient.Rss.Create(ctx, makePutioFeedFromSpec(ctx, feed))
+		putioFeed, err = putioClient.Rss.Create(ctx, makePutioFeedFromSpec(ctx, feed, rssSourceURL, r.titleTemplate()))
 		if err != nil {
 			span.RecordError(err)
 			return nil, fmt.Errorf("unable to create feed to Put.io: %w", err)
@@ -232,29 +449,181 @@ func (r *FeedReconciler) createOrUpdateFeed(ctx context.Context, feed *skynewzde
 			return nil, fmt.Errorf("unable to update pause status to Put.io: %w", err)
 		}
 
+		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedDriftedCondition(metav1.ConditionFalse, FeedInSync, ""))
+		feed.Status.SpecHash = hash
 		logger.Info("Put.io feed successfully created", "id", putioFeed.ID)
 		return putioFeed, nil
 	}
 
 	// feed found, updating it if not already at the latest version
-	if !isAlreadyProcessed(ctx, putioFeed, feed) {
+	if !isAlreadyProcessed(ctx, feed, hash) {
 		span.SetAttributes(attribute.String("action", "update"))
 		logger.Info("Put.io feed found, updating", "id", putioFeed.ID)
 
-		if err := putioClient.Rss.Update(ctx, makePutioFeedFromSpec(ctx, feed), *putioFeed.ID); err != nil {
+		updated, err := r.pushSpec(ctx, putioClient, feed, rssSourceURL, *putioFeed.ID)
+		if err != nil {
 			span.RecordError(err)
-			return nil, fmt.Errorf("unable to update feed to Put.io: %w", err)
+			return nil, err
 		}
 
-		if err := r.setPauseStatus(ctx, putioClient, feed, *putioFeed.ID); err != nil {
-			span.RecordError(err)
-			return nil, fmt.Errorf("unable to update pause status to Put.io: %w", err)
+		if _, legacy := parseLegacyTitle(putioFeed.Title); legacy {
+			r.Recorder.Eventf(feed, corev1.EventTypeNormal, eventFeedTitleMigrated, "Put.io feed title migrated from %q", putioFeed.Title)
 		}
 
+		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedDriftedCondition(metav1.ConditionFalse, FeedInSync, ""))
+		feed.Status.SpecHash = hash
+		return updated, nil
+	}
+
+	// feed found at the latest version, making sure nobody changed it from Put.io
+	drifted := detectDrift(ctx, putioFeed, feed, rssSourceURL, r.titleTemplate())
+	if len(drifted) == 0 {
+		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedDriftedCondition(metav1.ConditionFalse, FeedInSync, ""))
+		logger.Info("Feed up to date")
 		return putioFeed, nil
 	}
 
-	logger.Info("Feed up to date")
+	span.SetAttributes(attribute.String("action", "heal"), attribute.StringSlice("feed.drifted", drifted))
+	logger.Info("Put.io feed drifted from spec, restoring it", "id", putioFeed.ID, "drifted", drifted)
+
+	message := "Put.io feed diverged from spec: " + strings.Join(drifted, ", ")
+	r.Recorder.Event(feed, corev1.EventTypeWarning, eventDriftDetected, message)
+	meta.SetStatusCondition(&feed.Status.Conditions, makeFeedDriftedCondition(metav1.ConditionTrue, FeedDriftDetected, message))
+
+	restored, err := r.pushSpec(ctx, putioClient, feed, rssSourceURL, *putioFeed.ID)
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(feed, corev1.EventTypeWarning, eventUnableToCorrectDrift, err.Error())
+		return nil, err
+	}
+	r.Recorder.Event(feed, corev1.EventTypeNormal, eventDriftSuccessfullyCorrected, "Put.io feed restored from spec")
+
+	return restored, nil
+}
+
+// adoptFeed searches Put.io for an existing feed matching the spec and returns it, nil when there is none.
+func (r *FeedReconciler) adoptFeed(ctx context.Context, feed *skynewzdevv1alpha1.Feed, putioClient *putio.Client, rssSourceURL string) (*putio.Feed, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.adoptFeed")
+	defer span.End()
+
+	logger := log.FromContext(ctx)
+
+	feeds, err := putioClient.Rss.List(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("unable to list Put.io feeds: %w", err)
+	}
+
+	claimed, err := r.claimedFeedIDs(ctx, feed)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	putioFeed, reason, err := findAdoptableFeed(ctx, feeds, feed, rssSourceURL, r.titleTemplate(), claimed)
+	if err != nil || putioFeed == nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("action", "adopt"), attribute.Int("feed.id", int(*putioFeed.ID)), attribute.String("feed.adoption", reason))
+	logger.Info("Adopting existing Put.io feed", "id", *putioFeed.ID, "matched", reason)
+	r.Recorder.Eventf(feed, corev1.EventTypeNormal, eventFeedAdopted, "adopted existing Put.io feed %d matched by %s", *putioFeed.ID, reason)
+
+	return putioFeed, nil
+}
+
+// claimedFeedIDs returns the Put.io IDs already owned by Feeds other than given one, in any namespace.
+func (r *FeedReconciler) claimedFeedIDs(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (map[uint]bool, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.claimedFeedIDs")
+	defer span.End()
+
+	feeds := new(skynewzdevv1alpha1.FeedList)
+	if err := r.List(ctx, feeds); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("unable to list feeds: %w", err)
+	}
+
+	claimed := make(map[uint]bool)
+	for _, f := range feeds.Items {
+		if f.UID != feed.UID && f.Status.ID != nil {
+			claimed[*f.Status.ID] = true
+		}
+	}
+
+	return claimed, nil
+}
+
+// findAdoptableFeed returns the feed to adopt among given feeds along with how it matched.
+// In order of precedence, it matches the ID of the adopt-id annotation, a feed managed under the same title,
+// then an unmanaged feed with the same source URL and keyword. Feeds whose ID is claimed by another Feed are never adopted.
+func findAdoptableFeed(ctx context.Context, feeds []*putio.Feed, feed *skynewzdevv1alpha1.Feed, rssSourceURL string, titles *FeedTitleTemplate, claimed map[uint]bool) (*putio.Feed, string, error) {
+	_, span := tracer.Start(ctx, "controllers.findAdoptableFeed")
+	defer span.End()
+
+	if value, ok := feed.GetAnnotations()[adoptIDAnnotation]; ok {
+		id, err := strconv.ParseUint(value, 10, 0)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid %s annotation %q: %w", adoptIDAnnotation, value, err)
+		}
+
+		if claimed[uint(id)] {
+			return nil, "", fmt.Errorf("cannot adopt feed %d: %w", id, errAdoptedFeedClaimed)
+		}
+
+		for _, f := range feeds {
+			if f.ID != nil && uint64(*f.ID) == id {
+				return f, adoptIDAnnotation + " annotation", nil
+			}
+		}
+
+		return nil, "", fmt.Errorf("cannot adopt feed %d: %w", id, errAdoptedFeedNotFound)
+	}
+
+	for _, f := range feeds {
+		if f.ID == nil || claimed[*f.ID] {
+			continue // owned by another Feed under the same title
+		}
+
+		if title, managed := titles.ParseManaged(f.Title); managed && title == feed.Spec.Title {
+			return f, "managed title", nil
+		}
+	}
+
+	keyword, _ := feed.PutioKeywords()
+	for _, f := range feeds {
+		if _, managed := titles.ParseManaged(f.Title); managed || f.ID == nil || claimed[*f.ID] {
+			continue // owned by another Feed
+		}
+
+		if f.RssSourceURL == rssSourceURL && f.Keyword == keyword {
+			return f, "source URL and keyword", nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// pushSpec updates the Put.io feed and its pause status from the spec, then returns the feed as Put.io now stores it.
+func (r *FeedReconciler) pushSpec(ctx context.Context, putioClient *putio.Client, feed *skynewzdevv1alpha1.Feed, rssSourceURL string, feedID uint) (*putio.Feed, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.pushSpec")
+	defer span.End()
+
+	if err := putioClient.Rss.Update(ctx, makePutioFeedFromSpec(ctx, feed, rssSourceURL, r.titleTemplate()), feedID); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("unable to update feed to Put.io: %w", err)
+	}
+
+	if err := r.setPauseStatus(ctx, putioClient, feed, feedID); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("unable to update pause status to Put.io: %w", err)
+	}
+
+	putioFeed, err := putioClient.Rss.Get(ctx, feedID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("unable to read Put.io feed: %w", err)
+	}
+
 	return putioFeed, nil
 }
 
@@ -267,6 +636,13 @@ func (r *FeedReconciler) updateFeedStatus(ctx context.Context, feed *skynewzdevv
 	// update status
 	logger.Info("Updating feed status")
 	feed.Status.ID = putioFeed.ID
+	feed.Status.LastFetch = makeStatusTime(putioFeed.LastFetch)
+	feed.Status.LastError = putioFeed.LastError
+	feed.Status.FailedItemCount = putioFeed.FailedItemCount
+	feed.Status.PausedAt = makeStatusTime(putioFeed.PausedAt)
+	feed.Status.StartAt = makeStatusTime(putioFeed.StartAt)
+	feed.Status.UpdatedAt = makeStatusTime(putioFeed.UpdatedAt)
+	feed.Status.Extract = putioFeed.Extract
 
 	if putioFeed.LastError == "" {
 		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedAvailableCondition(metav1.ConditionTrue, FeedSuccessfullyDeployed, ""))
@@ -277,12 +653,303 @@ func (r *FeedReconciler) updateFeedStatus(ctx context.Context, feed *skynewzdevv
 	return r.Client.Status().Update(ctx, feed) //nolint:wrapcheck
 }
 
-func (r *FeedReconciler) makePutioClient(ctx context.Context, token string) *putio.Client {
-	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.makePutioClient")
+// makePutioClient authenticates with the feed account when referenced, with the feed secret otherwise.
+func (r *FeedReconciler) makePutioClient(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (*putio.Client, error) {
+	logger := log.FromContext(ctx)
+
+	if feed.Spec.AccountRef != nil {
+		logger.Info("Setting up put.io client with feed account", "account", feed.Spec.AccountRef.Name)
+		putioClient, err := makePutioClientFromAccount(ctx, r, feed.Namespace, *feed.Spec.AccountRef)
+		if err != nil {
+			r.Recorder.Event(feed, corev1.EventTypeWarning, eventUnableToGetAccount, err.Error())
+		}
+
+		return putioClient, err
+	}
+
+	logger.Info("Setting up put.io client with feed secret")
+	putioClient, err := makePutioClientFromSecret(ctx, r, feed.Namespace, feed.AuthSecretRef())
+	if err != nil {
+		r.Recorder.Event(feed, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
+	}
+
+	return putioClient, err
+}
+
+// authenticate makes the Put.io client of the feed and makes sure Put.io accepts its token,
+// reporting the outcome in the AuthReady condition. The token is only checked against Put.io
+// when the secret holding it changed since it was last accepted.
+func (r *FeedReconciler) authenticate(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (*putio.Client, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.authenticate")
+	defer span.End()
+
+	logger := log.FromContext(ctx)
+
+	var version string
+	putioClient, err := r.makePutioClient(ctx, feed)
+	if err == nil {
+		version, err = r.tokenSecretVersion(ctx, feed)
+	}
+
+	if err == nil && !isTokenChecked(feed, version) {
+		span.SetAttributes(attribute.String("feed.auth_secret_version", version))
+		if _, err = putioClient.Account.Info(ctx); err != nil {
+			err = fmt.Errorf("unable to check token against Put.io: %w", err)
+		}
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		feed.Status.AuthSecretVersion = ""
+		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedAuthReadyCondition(metav1.ConditionFalse, authFailureReason(feed, err), err.Error()))
+		if err := r.Status().Update(ctx, feed); err != nil {
+			logger.Error(err, "unable to update feed status")
+		}
+
+		return nil, err
+	}
+
+	feed.Status.AuthSecretVersion = version
+	meta.SetStatusCondition(&feed.Status.Conditions, makeFeedAuthReadyCondition(metav1.ConditionTrue, FeedTokenAccepted, ""))
+	return putioClient, nil
+}
+
+// tokenSecretVersion returns the resource version of the secret holding the Put.io token of the feed,
+// referenced by the feed itself or by its account.
+func (r *FeedReconciler) tokenSecretVersion(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (string, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.tokenSecretVersion")
+	defer span.End()
+
+	key := types.NamespacedName{Name: feed.AuthSecretRef().Name, Namespace: feed.Namespace}
+	if feed.Spec.AccountRef != nil {
+		account := &skynewzdevv1alpha1.PutioAccount{}
+		if err := r.Get(ctx, types.NamespacedName{Name: feed.Spec.AccountRef.Name}, account); err != nil {
+			span.RecordError(err)
+			return "", fmt.Errorf("cannot get account %q: %w", feed.Spec.AccountRef.Name, err)
+		}
+
+		key = types.NamespacedName{Name: account.Spec.TokenSecretRef.Name, Namespace: account.Spec.TokenSecretRef.Namespace}
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		span.RecordError(err)
+		return "", fmt.Errorf("cannot get secret %q: %w", key.Name, err)
+	}
+
+	return secret.ResourceVersion, nil
+}
+
+// isTokenChecked tells whether Put.io already accepted the token of given version of the secret holding it.
+func isTokenChecked(feed *skynewzdevv1alpha1.Feed, version string) bool {
+	return version != "" && feed.Status.AuthSecretVersion == version && meta.IsStatusConditionTrue(feed.Status.Conditions, string(FeedAuthReady))
+}
+
+// authFailureReason tells why authentication of given feed failed from given error.
+func authFailureReason(feed *skynewzdevv1alpha1.Feed, err error) FeedConditionReason {
+	switch {
+	case putio.IsUnauthorized(err):
+		return FeedTokenRejected
+	case errors.Is(err, errSecretKeyMissing):
+		return FeedSecretKeyMissing
+	case errors.Is(err, errAccountNotAllowed):
+		return FeedAccountUnavailable
+	case apierrors.IsNotFound(err) && feed.Spec.AccountRef != nil:
+		return FeedAccountUnavailable
+	case apierrors.IsNotFound(err):
+		return FeedSecretNotFound
+	default:
+		return FeedTokenCheckFailed
+	}
+}
+
+// resolveRSSSourceURL returns the URL of the RSS feed, reading it or its passkey from the rssSourceURLFrom secret.
+// Failures are reported in the Available condition without the secret value.
+func (r *FeedReconciler) resolveRSSSourceURL(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (string, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.resolveRSSSourceURL")
+	defer span.End()
+
+	from := feed.Spec.RssSourceURLFrom
+	if from == nil {
+		return feed.Spec.RssSourceURL, nil
+	}
+
+	value, err := readSecretKey(ctx, r, feed.Namespace, from.SecretKeyRef.Name, from.SecretKeyRef.Key)
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(feed, corev1.EventTypeWarning, eventUnableToGetRSSSourceURL, err.Error())
+		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedAvailableCondition(metav1.ConditionFalse, FeedSourceURLUnavailable, err.Error()))
+		if err := r.Status().Update(ctx, feed); err != nil {
+			log.FromContext(ctx).Error(err, "unable to update feed status")
+		}
+
+		return "", fmt.Errorf("unable to read RSS source URL: %w", err)
+	}
+
+	rssSourceURL := renderRSSSourceURL(feed.Spec.RssSourceURL, string(value))
+	span.SetAttributes(attribute.String("feed.rss_source_url", rss.RedactURL(rssSourceURL)))
+	return rssSourceURL, nil
+}
+
+// renderRSSSourceURL replaces the passkey placeholder of given URL template by given secret value,
+// which is the whole URL when there is no template.
+func renderRSSSourceURL(template, value string) string {
+	value = strings.TrimSpace(value)
+	if template == "" {
+		return value
+	}
+
+	return strings.ReplaceAll(template, skynewzdevv1alpha1.PasskeyPlaceholder, value)
+}
+
+// resolveParentDir stores in status the file ID of the folder referenced by parentFolderRef, parent_dir_path or
+// createParentDir, and makes sure the folder the feed downloads to exists, is a folder and belongs to the account,
+// reporting the outcome in the ParentDirReady condition.
+func (r *FeedReconciler) resolveParentDir(ctx context.Context, feed *skynewzdevv1alpha1.Feed, putioClient *putio.Client) error {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.resolveParentDir")
+	defer span.End()
+
+	reason, err := r.findParentDir(ctx, feed, putioClient)
+	if err == nil {
+		var checkReason FeedConditionReason
+		checkReason, err = checkParentDir(ctx, putioClient.Files, feedParentDirID(feed))
+		if err != nil || reason == "" {
+			reason = checkReason
+		}
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedParentDirReadyCondition(metav1.ConditionFalse, reason, err.Error()))
+		if err := r.Status().Update(ctx, feed); err != nil {
+			log.FromContext(ctx).Error(err, "unable to update feed status")
+		}
+
+		return err
+	}
+
+	message := fmt.Sprintf("files are placed in folder %d", feedParentDirID(feed))
+	meta.SetStatusCondition(&feed.Status.Conditions, makeFeedParentDirReadyCondition(metav1.ConditionTrue, reason, message))
+	return nil
+}
+
+// findParentDir stores in status the file ID of the folder referenced by parentFolderRef, parent_dir_path or
+// createParentDir, creating the latter when missing. It returns FeedParentDirCreated when it did, the reason of the
+// failure on error, and an empty reason otherwise.
+func (r *FeedReconciler) findParentDir(ctx context.Context, feed *skynewzdevv1alpha1.Feed, putioClient *putio.Client) (FeedConditionReason, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.findParentDir")
+	defer span.End()
+
+	switch {
+	case feed.Spec.ParentFolderRef != nil:
+		folder := new(skynewzdevv1alpha1.Folder)
+		key := types.NamespacedName{Name: feed.Spec.ParentFolderRef.Name, Namespace: feed.Namespace}
+		if err := r.Get(ctx, key, folder); err != nil {
+			span.RecordError(err)
+			return FeedParentFolderNotReady, fmt.Errorf("cannot get folder %q: %w", key.Name, err)
+		}
+
+		if folder.Status.FileID == nil {
+			span.RecordError(errFolderNotReady)
+			return FeedParentFolderNotReady, fmt.Errorf("cannot use folder %q: %w", key.Name, errFolderNotReady)
+		}
+
+		feed.Status.ParentDirID = folder.Status.FileID
+	case feed.Spec.ParentDirPath != "":
+		id, err := resolveFolderPath(ctx, putioClient.Files, feed.Spec.ParentDirPath, false)
+		if err != nil {
+			span.RecordError(err)
+			return folderPathFailureReason(err), err
+		}
+
+		feed.Status.ParentDirID = &id
+	case feed.Spec.CreateParentDir != "":
+		id, err := resolveFolderPath(ctx, putioClient.Files, feed.Spec.CreateParentDir, false)
+		if err == nil {
+			feed.Status.ParentDirID = &id
+			return "", nil
+		}
+
+		if !errors.Is(err, errFolderNotFound) {
+			span.RecordError(err)
+			return folderPathFailureReason(err), err
+		}
+
+		if id, err = resolveFolderPath(ctx, putioClient.Files, feed.Spec.CreateParentDir, true); err != nil {
+			span.RecordError(err)
+			return FeedParentDirCheckFailed, err
+		}
+
+		feed.Status.ParentDirID = &id
+		r.Recorder.Eventf(feed, corev1.EventTypeNormal, eventParentDirCreated, "created folder %q (%d)", feed.Spec.CreateParentDir, id)
+		return FeedParentDirCreated, nil
+	default:
+		feed.Status.ParentDirID = nil
+	}
+
+	return "", nil
+}
+
+// folderPathFailureReason tells why a folder path could not be resolved from given error.
+func folderPathFailureReason(err error) FeedConditionReason {
+	if errors.Is(err, errFolderNotFound) {
+		return FeedParentDirNotFound
+	}
+
+	return FeedParentDirCheckFailed
+}
+
+// feedParentDirID returns the file ID of the folder given feed downloads to, either given as ID,
+// or resolved from a Folder or a path.
+func feedParentDirID(feed *skynewzdevv1alpha1.Feed) uint {
+	switch {
+	case feed.Spec.ParentDirID != nil:
+		return *feed.Spec.ParentDirID
+	case feed.Status.ParentDirID != nil:
+		return *feed.Status.ParentDirID
+	default:
+		return 0
+	}
+}
+
+// checkParentDir makes sure the file with given ID is a folder owned by the account, returning the reason of the
+// ParentDirReady condition. The root directory always is.
+func checkParentDir(ctx context.Context, files putio.FilesService, id uint) (FeedConditionReason, error) {
+	ctx, span := tracer.Start(ctx, "controllers.checkParentDir")
 	defer span.End()
 
-	httpClient := http.NewHTTPClient(token)
-	return putio.New(ctx, httpClient)
+	span.SetAttributes(attribute.Int("folder.id", int(id)))
+
+	if id == 0 {
+		return FeedParentDirFound, nil
+	}
+
+	file, err := files.Get(ctx, id)
+	switch {
+	case putio.IsNotFound(err):
+		span.RecordError(err)
+		return FeedParentDirNotFound, fmt.Errorf("cannot use folder %d: %w", id, errFolderNotFound)
+	case err != nil:
+		span.RecordError(err)
+		return FeedParentDirCheckFailed, fmt.Errorf("cannot get folder %d: %w", id, err)
+	case !file.IsDir():
+		span.RecordError(errNotADirectory)
+		return FeedParentDirNotADirectory, fmt.Errorf("cannot use %q (%d): %w", file.Name, id, errNotADirectory)
+	case file.IsShared:
+		span.RecordError(errFolderNotOwned)
+		return FeedParentDirNotOwned, fmt.Errorf("cannot use %q (%d): %w", file.Name, id, errFolderNotOwned)
+	default:
+		return FeedParentDirFound, nil
+	}
+}
+
+// makeStatusTime converts a Put.io time into a status time, nil when Put.io did not set it.
+func makeStatusTime(t putio.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &metav1.Time{Time: t.GetTime()}
 }
 
 func (r *FeedReconciler) setPauseStatus(ctx context.Context, putioClient *putio.Client, feed *skynewzdevv1alpha1.Feed, feedID uint) error {
@@ -291,7 +958,7 @@ func (r *FeedReconciler) setPauseStatus(ctx context.Context, putioClient *putio.
 
 	r.Recorder.Event(feed, corev1.EventTypeNormal, eventSetPauseStatus, "setting feed pause status")
 	var err error
-	switch *feed.Spec.Paused {
+	switch isFeedPaused(feed) {
 	case true:
 		err = putioClient.Rss.Pause(ctx, feedID)
 	case false:
@@ -307,39 +974,124 @@ func (r *FeedReconciler) setPauseStatus(ctx context.Context, putioClient *putio.
 	return err //nolint:wrapcheck
 }
 
-// makeFeedTitleWithGenerationNumber to prevent infinite reconciliation, make a checksum of current spec
-// and place it into title.
-func makeFeedTitleWithGenerationNumber(ctx context.Context, feed *skynewzdevv1alpha1.Feed) string {
-	_, span := tracer.Start(ctx, "controllers.makeFeedTitleWithGenerationNumber")
+// isFeedPaused tells whether given feed should be paused at Put.io, either from its spec, by its schedule,
+// once expired, or by the QuotaGuard.
+func isFeedPaused(feed *skynewzdevv1alpha1.Feed) bool {
+	return (feed.Spec.Paused != nil && *feed.Spec.Paused) || isOutsideSchedule(feed) || isPausedOnExpiry(feed) || isQuotaPaused(feed)
+}
+
+// requeueAfter returns when given feed must be reconciled again: at its next schedule transition, when it expires,
+// or after the resync interval, whichever comes first.
+func (r *FeedReconciler) requeueAfter(feed *skynewzdevv1alpha1.Feed, now time.Time) time.Duration {
+	requeueAfter := r.ResyncInterval
+	for _, deadline := range []*metav1.Time{feed.Status.NextTransition, expiryDeadline(feed)} {
+		if deadline == nil {
+			continue
+		}
+
+		untilDeadline := deadline.Sub(now)
+		if untilDeadline < minRequeueAfter {
+			untilDeadline = minRequeueAfter
+		}
+
+		if requeueAfter == 0 || untilDeadline < requeueAfter {
+			requeueAfter = untilDeadline
+		}
+	}
+
+	return requeueAfter
+}
+
+// titleTemplate returns the template rendering Put.io feed titles.
+func (r *FeedReconciler) titleTemplate() *FeedTitleTemplate {
+	if r.TitleTemplate == nil {
+		return defaultFeedTitleTemplate
+	}
+
+	return r.TitleTemplate
+}
+
+// computeSpecHash to prevent infinite reconciliation, fingerprints the Put.io feed payload made from the spec.
+func computeSpecHash(ctx context.Context, feed *skynewzdevv1alpha1.Feed, rssSourceURL string, titles *FeedTitleTemplate) string {
+	ctx, span := tracer.Start(ctx, "controllers.computeSpecHash")
 	defer span.End()
 
-	return fmt.Sprintf(titleFormat, feed.Spec.Title, feed.GetGeneration())
+	payload := makePutioFeedFromSpec(ctx, feed, rssSourceURL, titles)
+	payload.Paused = isFeedPaused(feed)
+
+	//nolint:errchkjson // a putio.Feed is always marshallable
+	b, _ := json.Marshal(payload)
+	return fmt.Sprintf("%x", sha256.Sum256(b))
 }
 
-func isAlreadyProcessed(ctx context.Context, putioFeed *putio.Feed, feed *skynewzdevv1alpha1.Feed) bool {
+// isAlreadyProcessed tells whether the Put.io feed has already been updated with given spec hash.
+func isAlreadyProcessed(ctx context.Context, feed *skynewzdevv1alpha1.Feed, hash string) bool {
 	_, span := tracer.Start(ctx, "controllers.isAlreadyProcessed")
 	defer span.End()
 
-	// parse current title
-	parsed := strings.Split(putioFeed.Title, titleSeparator)
-	if len(parsed) != 3 {
-		return false
-	}
-
-	return parsed[1] == strconv.FormatInt(feed.GetGeneration(), 10)
+	return feed.Status.SpecHash == hash
 }
 
-func makePutioFeedFromSpec(ctx context.Context, feed *skynewzdevv1alpha1.Feed) *putio.Feed {
+// makePutioFeedFromSpec makes the Put.io feed payload from the spec, along with given RSS source URL resolved by resolveRSSSourceURL.
+func makePutioFeedFromSpec(ctx context.Context, feed *skynewzdevv1alpha1.Feed, rssSourceURL string, titles *FeedTitleTemplate) *putio.Feed {
 	ctx, span := tracer.Start(ctx, "controllers.makePutioFeedFromSpec")
 	defer span.End()
 
+	keyword, unwanted := feed.PutioKeywords()
 	return &putio.Feed{
-		Title:                makeFeedTitleWithGenerationNumber(ctx, feed),
-		RssSourceURL:         feed.Spec.RssSourceURL,
-		ParentDirID:          *feed.Spec.ParentDirID,
+		Title:                titles.Render(feed),
+		RssSourceURL:         rssSourceURL,
+		ParentDirID:          feedParentDirID(feed),
 		DeleteOldFiles:       *feed.Spec.DeleteOldFiles,
 		DontProcessWholeFeed: *feed.Spec.DontProcessWholeFeed,
-		Keyword:              feed.Spec.Keyword,
-		UnwantedKeywords:     feed.Spec.UnwantedKeywords,
+		Keyword:              keyword,
+		UnwantedKeywords:     unwanted,
 	}
 }
+
+// detectDrift compares every managed field of the Put.io feed against the spec
+// and returns a description of each one that diverged.
+func detectDrift(ctx context.Context, putioFeed *putio.Feed, feed *skynewzdevv1alpha1.Feed, rssSourceURL string, titles *FeedTitleTemplate) []string {
+	_, span := tracer.Start(ctx, "controllers.detectDrift")
+	defer span.End()
+
+	var (
+		drifted = make([]string, 0)
+		want    = makePutioFeedFromSpec(ctx, feed, rssSourceURL, titles)
+		paused  = isFeedPaused(feed)
+	)
+
+	if putioFeed.Title != want.Title {
+		drifted = append(drifted, fmt.Sprintf("title %q instead of %q", putioFeed.Title, want.Title))
+	}
+
+	if putioFeed.Keyword != want.Keyword {
+		drifted = append(drifted, fmt.Sprintf("keyword %q instead of %q", putioFeed.Keyword, want.Keyword))
+	}
+
+	if putioFeed.UnwantedKeywords != want.UnwantedKeywords {
+		drifted = append(drifted, fmt.Sprintf("unwanted_keywords %q instead of %q", putioFeed.UnwantedKeywords, want.UnwantedKeywords))
+	}
+
+	if putioFeed.RssSourceURL != want.RssSourceURL {
+		drifted = append(drifted, fmt.Sprintf("rss_source_url %q instead of %q", rss.RedactURL(putioFeed.RssSourceURL), rss.RedactURL(want.RssSourceURL)))
+	}
+
+	if putioFeed.ParentDirID != want.ParentDirID {
+		drifted = append(drifted, fmt.Sprintf("parent_dir_id %d instead of %d", putioFeed.ParentDirID, want.ParentDirID))
+	}
+
+	if putioFeed.DeleteOldFiles != want.DeleteOldFiles {
+		drifted = append(drifted, fmt.Sprintf("delete_old_files %t instead of %t", putioFeed.DeleteOldFiles, want.DeleteOldFiles))
+	}
+
+	if putioFeed.DontProcessWholeFeed != want.DontProcessWholeFeed {
+		drifted = append(drifted, fmt.Sprintf("dont_process_whole_feed %t instead of %t", putioFeed.DontProcessWholeFeed, want.DontProcessWholeFeed))
+	}
+
+	if putioFeed.Paused != paused {
+		drifted = append(drifted, fmt.Sprintf("paused %t instead of %t", putioFeed.Paused, paused))
+	}
+
+	return drifted
+}
@@ -2,8 +2,9 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"net/http"
 	"testing"
 	"time"
 
@@ -12,10 +13,14 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"golang.org/x/oauth2"
+	goputio "github.com/putdotio/go-putio"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 func Test_makePutioFeedFromSpec(t *testing.T) {
@@ -46,14 +51,14 @@ func Test_makePutioFeedFromSpec(t *testing.T) {
 						Keyword:              "foo",
 						UnwantedKeywords:     "bar",
 						// Paused:               true, // Pause is not handle during creation/update
-						AuthSecretRef: skynewzdevv1alpha1.AuthSecretReference{},
+						AuthSecretRef: &skynewzdevv1alpha1.AuthSecretReference{},
 					},
 					Status: skynewzdevv1alpha1.FeedStatus{},
 				},
 			},
 			want: &putio.Feed{
 				ID:                   nil,
-				Title:                "foo|0|managed by Kubernetes/putio-operator",
+				Title:                "foo (managed by Kubernetes/putio-operator)",
 				RssSourceURL:         "https://www.google.com",
 				ParentDirID:          parentDirID,
 				DeleteOldFiles:       true,
@@ -71,6 +76,34 @@ func Test_makePutioFeedFromSpec(t *testing.T) {
 				UpdatedAt:       putio.Time{},
 			},
 		},
+		{
+			name: "structured keywords",
+			args: args{
+				ctx: context.Background(),
+				feed: &skynewzdevv1alpha1.Feed{
+					Spec: skynewzdevv1alpha1.FeedSpec{
+						Title:                "foo",
+						RssSourceURL:         "https://www.google.com",
+						ParentDirID:          &parentDirID,
+						DeleteOldFiles:       boolToPtr(false),
+						DontProcessWholeFeed: boolToPtr(false),
+						Keywords: &skynewzdevv1alpha1.Keywords{
+							AllOf:  []string{"House.of.the.Dragon"},
+							AnyOf:  []string{"1080p", "2160p"},
+							NoneOf: []string{"HDR"},
+						},
+						AuthSecretRef: &skynewzdevv1alpha1.AuthSecretReference{},
+					},
+				},
+			},
+			want: &putio.Feed{
+				Title:            "foo (managed by Kubernetes/putio-operator)",
+				RssSourceURL:     "https://www.google.com",
+				ParentDirID:      parentDirID,
+				Keyword:          "House.of.the.Dragon&1080p,House.of.the.Dragon&2160p",
+				UnwantedKeywords: "HDR",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -78,7 +111,7 @@ func Test_makePutioFeedFromSpec(t *testing.T) {
 			//	t.Errorf("makePutioFeedFromSpec() = %v, want %v", got, tt.want)
 			//}
 
-			got := makePutioFeedFromSpec(tt.args.ctx, tt.args.feed)
+			got := makePutioFeedFromSpec(tt.args.ctx, tt.args.feed, tt.args.feed.Spec.RssSourceURL, defaultFeedTitleTemplate)
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("makePutioFeedFromSpec() mismatch (-want +got):\n%s", diff)
 			}
@@ -86,52 +119,196 @@ func Test_makePutioFeedFromSpec(t *testing.T) {
 	}
 }
 
-func Test_makeFeedTitleWithGenerationNumber(t *testing.T) {
+func Test_computeSpecHash(t *testing.T) {
+	makeFeed := func(keyword string, paused bool) *skynewzdevv1alpha1.Feed {
+		return &skynewzdevv1alpha1.Feed{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1234},
+			Spec: skynewzdevv1alpha1.FeedSpec{
+				Title:                "foo",
+				RssSourceURL:         "https://www.google.com",
+				ParentDirID:          new(uint),
+				DeleteOldFiles:       new(bool),
+				DontProcessWholeFeed: new(bool),
+				Keyword:              keyword,
+				Paused:               boolToPtr(paused),
+			},
+		}
+	}
+
+	var (
+		ctx   = context.Background()
+		hash  = computeSpecHash(ctx, makeFeed("foo", false), "https://www.google.com", defaultFeedTitleTemplate)
+		other = makeFeed("foo", false)
+	)
+
+	other.Generation = 4321
+	if got := computeSpecHash(ctx, other, "https://www.google.com", defaultFeedTitleTemplate); got != hash {
+		t.Errorf("computeSpecHash() = %v, want %v when only the generation changed", got, hash)
+	}
+
+	if got := computeSpecHash(ctx, makeFeed("bar", false), "https://www.google.com", defaultFeedTitleTemplate); got == hash {
+		t.Errorf("computeSpecHash() = %v, want another hash when the keyword changed", got)
+	}
+
+	if got := computeSpecHash(ctx, makeFeed("foo", true), "https://www.google.com", defaultFeedTitleTemplate); got == hash {
+		t.Errorf("computeSpecHash() = %v, want another hash when the feed is paused", got)
+	}
+
+	if got := computeSpecHash(ctx, makeFeed("foo", false), "https://www.google.com", MustNewFeedTitleTemplate("{{ .Title }}")); got == hash {
+		t.Errorf("computeSpecHash() = %v, want another hash when the title template changed", got)
+	}
+
+	if got := computeSpecHash(ctx, makeFeed("foo", false), "https://www.google.com?passkey=rotated", defaultFeedTitleTemplate); got == hash {
+		t.Errorf("computeSpecHash() = %v, want another hash when the source URL secret changed", got)
+	}
+}
+
+func Test_renderRSSSourceURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		value    string
+		want     string
+	}{
+		{
+			name:     "whole URL",
+			template: "",
+			value:    "https://rss.site.fr/rss?id=2184&passkey=secret\n",
+			want:     "https://rss.site.fr/rss?id=2184&passkey=secret",
+		},
+		{
+			name:     "passkey",
+			template: "https://rss.site.fr/rss?id=2184&passkey=${passkey}",
+			value:    "secret",
+			want:     "https://rss.site.fr/rss?id=2184&passkey=secret",
+		},
+		{
+			name:     "passkey in path",
+			template: "https://rss.site.fr/${passkey}/rss",
+			value:    " secret ",
+			want:     "https://rss.site.fr/secret/rss",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderRSSSourceURL(tt.template, tt.value); got != tt.want {
+				t.Errorf("renderRSSSourceURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_feedSecretNames(t *testing.T) {
+	auth := &skynewzdevv1alpha1.AuthSecretReference{Name: "putio-token", Key: "token"}
+	tests := []struct {
+		name string
+		spec skynewzdevv1alpha1.FeedSpec
+		want []string
+	}{
+		{
+			name: "account",
+			spec: skynewzdevv1alpha1.FeedSpec{AccountRef: &skynewzdevv1alpha1.AccountReference{Name: "main"}},
+			want: []string{},
+		},
+		{
+			name: "auth secret",
+			spec: skynewzdevv1alpha1.FeedSpec{AuthSecretRef: auth},
+			want: []string{"putio-token"},
+		},
+		{
+			name: "auth and source URL secrets",
+			spec: skynewzdevv1alpha1.FeedSpec{
+				AuthSecretRef:    auth,
+				RssSourceURLFrom: &skynewzdevv1alpha1.RssSourceURLSource{SecretKeyRef: skynewzdevv1alpha1.SecretKeyReference{Name: "tracker", Key: "passkey"}},
+			},
+			want: []string{"putio-token", "tracker"},
+		},
+		{
+			name: "same secret",
+			spec: skynewzdevv1alpha1.FeedSpec{
+				AuthSecretRef:    auth,
+				RssSourceURLFrom: &skynewzdevv1alpha1.RssSourceURLSource{SecretKeyRef: skynewzdevv1alpha1.SecretKeyReference{Name: "putio-token", Key: "passkey"}},
+			},
+			want: []string{"putio-token"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := feedSecretNames(&skynewzdevv1alpha1.Feed{Spec: tt.spec})
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("feedSecretNames() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_isAlreadyProcessed(t *testing.T) {
 	type args struct {
 		ctx  context.Context
 		feed *skynewzdevv1alpha1.Feed
+		hash string
 	}
 	tests := []struct {
 		name string
 		args args
-		want string
+		want bool
 	}{
 		{
-			name: "expected title",
+			name: "feed already processed",
 			args: args{
-				ctx: context.Background(),
-				feed: &skynewzdevv1alpha1.Feed{
-					TypeMeta: metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{
-						Generation: 1234,
-					},
-					Spec: skynewzdevv1alpha1.FeedSpec{
-						Title:                "foo",
-						RssSourceURL:         "",
-						ParentDirID:          nil,
-						DeleteOldFiles:       new(bool),
-						DontProcessWholeFeed: new(bool),
-						Keyword:              "",
-						UnwantedKeywords:     "",
-						Paused:               new(bool),
-						AuthSecretRef:        skynewzdevv1alpha1.AuthSecretReference{},
-					},
-					Status: skynewzdevv1alpha1.FeedStatus{},
-				},
+				ctx:  context.Background(),
+				feed: &skynewzdevv1alpha1.Feed{Status: skynewzdevv1alpha1.FeedStatus{SpecHash: "1234"}},
+				hash: "1234",
+			},
+			want: true,
+		},
+		{
+			name: "feed not processed yet",
+			args: args{
+				ctx:  context.Background(),
+				feed: &skynewzdevv1alpha1.Feed{Status: skynewzdevv1alpha1.FeedStatus{SpecHash: "4321"}},
+				hash: "1234",
+			},
+			want: false,
+		},
+		{
+			name: "feed without hash, e.g. titled by a previous version",
+			args: args{
+				ctx:  context.Background(),
+				feed: &skynewzdevv1alpha1.Feed{},
+				hash: "1234",
 			},
-			want: "foo|1234|managed by Kubernetes/putio-operator",
+			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := makeFeedTitleWithGenerationNumber(tt.args.ctx, tt.args.feed); got != tt.want {
-				t.Errorf("makeFeedTitleWithGenerationNumber() = %v, want %v", got, tt.want)
+			if got := isAlreadyProcessed(tt.args.ctx, tt.args.feed, tt.args.hash); got != tt.want {
+				t.Errorf("isAlreadyProcessed() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_isAlreadyProcessed(t *testing.T) {
+func Test_detectDrift(t *testing.T) {
+	parentDirID := uint(1234)
+	feed := &skynewzdevv1alpha1.Feed{
+		TypeMeta:   metav1.TypeMeta{},
+		ObjectMeta: metav1.ObjectMeta{},
+		Spec: skynewzdevv1alpha1.FeedSpec{
+			Title:                "foo",
+			RssSourceURL:         "https://www.google.com",
+			ParentDirID:          &parentDirID,
+			DeleteOldFiles:       boolToPtr(false),
+			DontProcessWholeFeed: boolToPtr(true),
+			Keyword:              "foo",
+			UnwantedKeywords:     "bar",
+			Paused:               boolToPtr(true),
+			AuthSecretRef:        &skynewzdevv1alpha1.AuthSecretReference{},
+		},
+		Status: skynewzdevv1alpha1.FeedStatus{},
+	}
+
 	type args struct {
 		ctx       context.Context
 		putioFeed *putio.Feed
@@ -140,81 +317,463 @@ func Test_isAlreadyProcessed(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want bool
+		want []string
 	}{
 		{
-			name: "feed already processed",
+			name: "in sync",
 			args: args{
 				ctx: context.Background(),
 				putioFeed: &putio.Feed{
-					ID:                   nil,
-					Title:                "foo|1234|managed by Kubernetes/putio-operator",
-					RssSourceURL:         "",
-					ParentDirID:          0,
+					Title:                "foo (managed by Kubernetes/putio-operator)",
+					RssSourceURL:         "https://www.google.com",
+					ParentDirID:          parentDirID,
 					DeleteOldFiles:       false,
-					DontProcessWholeFeed: false,
-					Keyword:              "",
-					UnwantedKeywords:     "",
-					Paused:               false,
-					Extract:              false,
-					FailedItemCount:      0,
-					LastError:            "",
-					LastFetch:            putio.Time{},
-					CreatedAt:            putio.Time{},
-					PausedAt:             putio.Time{},
-					StartAt:              putio.Time{},
-					UpdatedAt:            putio.Time{},
+					DontProcessWholeFeed: true,
+					Keyword:              "foo",
+					UnwantedKeywords:     "bar",
+					Paused:               true,
 				},
-				feed: &skynewzdevv1alpha1.Feed{
-					TypeMeta: metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{
-						Generation: 1234,
-					},
-					Spec:   skynewzdevv1alpha1.FeedSpec{},
-					Status: skynewzdevv1alpha1.FeedStatus{},
+				feed: feed,
+			},
+			want: []string{},
+		},
+		{
+			name: "rss source url drifted",
+			args: args{
+				ctx: context.Background(),
+				putioFeed: &putio.Feed{
+					Title:                "foo (managed by Kubernetes/putio-operator)",
+					RssSourceURL:         "https://www.example.com",
+					ParentDirID:          parentDirID,
+					DeleteOldFiles:       false,
+					DontProcessWholeFeed: true,
+					Keyword:              "foo",
+					UnwantedKeywords:     "bar",
+					Paused:               true,
 				},
+				feed: feed,
 			},
-			want: true,
+			want: []string{`rss_source_url "https://www.example.com" instead of "https://www.google.com"`},
 		},
 		{
-			name: "feed already processed",
+			name: "dont process whole feed drifted",
 			args: args{
 				ctx: context.Background(),
 				putioFeed: &putio.Feed{
-					ID:                   nil,
-					Title:                "foo|4321|managed by Kubernetes/putio-operator",
-					RssSourceURL:         "",
-					ParentDirID:          0,
+					Title:                "foo (managed by Kubernetes/putio-operator)",
+					RssSourceURL:         "https://www.google.com",
+					ParentDirID:          parentDirID,
 					DeleteOldFiles:       false,
 					DontProcessWholeFeed: false,
-					Keyword:              "",
-					UnwantedKeywords:     "",
-					Paused:               false,
-					Extract:              false,
-					FailedItemCount:      0,
-					LastError:            "",
-					LastFetch:            putio.Time{},
-					CreatedAt:            putio.Time{},
-					PausedAt:             putio.Time{},
-					StartAt:              putio.Time{},
-					UpdatedAt:            putio.Time{},
+					Keyword:              "foo",
+					UnwantedKeywords:     "bar",
+					Paused:               true,
 				},
-				feed: &skynewzdevv1alpha1.Feed{
-					TypeMeta: metav1.TypeMeta{},
-					ObjectMeta: metav1.ObjectMeta{
-						Generation: 1234,
-					},
-					Spec:   skynewzdevv1alpha1.FeedSpec{},
-					Status: skynewzdevv1alpha1.FeedStatus{},
+				feed: feed,
+			},
+			want: []string{"dont_process_whole_feed false instead of true"},
+		},
+		{
+			name: "drifted",
+			args: args{
+				ctx: context.Background(),
+				putioFeed: &putio.Feed{
+					Title:            "foo|0|managed by Kubernetes/putio-operator",
+					RssSourceURL:     "https://www.google.com",
+					ParentDirID:      0,
+					DeleteOldFiles:   true,
+					Keyword:          "baz",
+					UnwantedKeywords: "",
+					Paused:           false,
 				},
+				feed: feed,
+			},
+			want: []string{
+				`title "foo|0|managed by Kubernetes/putio-operator" instead of "foo (managed by Kubernetes/putio-operator)"`,
+				`keyword "baz" instead of "foo"`,
+				`unwanted_keywords "" instead of "bar"`,
+				"parent_dir_id 0 instead of 1234",
+				"delete_old_files true instead of false",
+				"dont_process_whole_feed false instead of true",
+				"paused false instead of true",
 			},
-			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := isAlreadyProcessed(tt.args.ctx, tt.args.putioFeed, tt.args.feed); got != tt.want {
-				t.Errorf("isAlreadyProcessed() = %v, want %v", got, tt.want)
+			got := detectDrift(tt.args.ctx, tt.args.putioFeed, tt.args.feed, tt.args.feed.Spec.RssSourceURL, defaultFeedTitleTemplate)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("detectDrift() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_findAdoptableFeed(t *testing.T) {
+	makeFeed := func(annotations map[string]string) *skynewzdevv1alpha1.Feed {
+		return &skynewzdevv1alpha1.Feed{
+			ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			Spec: skynewzdevv1alpha1.FeedSpec{
+				Title:        "foo",
+				RssSourceURL: "https://www.google.com",
+				Keyword:      "foo",
+			},
+		}
+	}
+
+	feeds := []*putio.Feed{
+		{ID: uintToPtr(1), Title: "bar|1|managed by Kubernetes/putio-operator", RssSourceURL: "https://www.google.com", Keyword: "foo"},
+		{ID: uintToPtr(2), Title: "manual", RssSourceURL: "https://www.google.com", Keyword: "foo"},
+		{ID: uintToPtr(3), Title: "foo (managed by Kubernetes/putio-operator)", RssSourceURL: "https://www.example.com", Keyword: "baz"},
+	}
+
+	type args struct {
+		feeds   []*putio.Feed
+		feed    *skynewzdevv1alpha1.Feed
+		claimed map[uint]bool
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantID     *uint
+		wantReason string
+		wantErr    bool
+	}{
+		{
+			name:       "annotation",
+			args:       args{feeds: feeds, feed: makeFeed(map[string]string{adoptIDAnnotation: "2"})},
+			wantID:     uintToPtr(2),
+			wantReason: "feed.skynewz.dev/adopt-id annotation",
+		},
+		{
+			name:    "annotation with unknown ID",
+			args:    args{feeds: feeds, feed: makeFeed(map[string]string{adoptIDAnnotation: "42"})},
+			wantErr: true,
+		},
+		{
+			name:    "invalid annotation",
+			args:    args{feeds: feeds, feed: makeFeed(map[string]string{adoptIDAnnotation: "foo"})},
+			wantErr: true,
+		},
+		{
+			name:       "managed title",
+			args:       args{feeds: feeds, feed: makeFeed(nil)},
+			wantID:     uintToPtr(3),
+			wantReason: "managed title",
+		},
+		{
+			name:       "source URL and keyword skips feeds managed by another Feed",
+			args:       args{feeds: feeds[:2], feed: makeFeed(nil)},
+			wantID:     uintToPtr(2),
+			wantReason: "source URL and keyword",
+		},
+		{
+			name:    "annotation with ID claimed by another Feed",
+			args:    args{feeds: feeds, feed: makeFeed(map[string]string{adoptIDAnnotation: "2"}), claimed: map[uint]bool{2: true}},
+			wantErr: true,
+		},
+		{
+			name:       "managed title claimed by another Feed with the same title",
+			args:       args{feeds: feeds, feed: makeFeed(nil), claimed: map[uint]bool{3: true}},
+			wantID:     uintToPtr(2),
+			wantReason: "source URL and keyword",
+		},
+		{
+			name: "source URL and keyword claimed by another Feed",
+			args: args{feeds: feeds[:2], feed: makeFeed(nil), claimed: map[uint]bool{2: true}},
+		},
+		{
+			name: "nothing to adopt",
+			args: args{feeds: feeds[:1], feed: makeFeed(nil)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason, err := findAdoptableFeed(context.Background(), tt.args.feeds, tt.args.feed, tt.args.feed.Spec.RssSourceURL, defaultFeedTitleTemplate, tt.args.claimed)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("findAdoptableFeed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var gotID *uint
+			if got != nil {
+				gotID = got.ID
+			}
+			if diff := cmp.Diff(tt.wantID, gotID); diff != "" {
+				t.Errorf("findAdoptableFeed() mismatch (-want +got):\n%s", diff)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("findAdoptableFeed() reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestFeedReconciler_deleteFeed_withoutID(t *testing.T) {
+	// no Put.io call, nor credentials, are needed to delete a feed never created at Put.io
+	r := &FeedReconciler{}
+	for _, policy := range []skynewzdevv1alpha1.DeletionPolicy{
+		skynewzdevv1alpha1.DeletionPolicyDelete,
+		skynewzdevv1alpha1.DeletionPolicyOrphan,
+		skynewzdevv1alpha1.DeletionPolicyPause,
+	} {
+		t.Run(string(policy), func(t *testing.T) {
+			feed := &skynewzdevv1alpha1.Feed{Spec: skynewzdevv1alpha1.FeedSpec{DeletionPolicy: policy}}
+			got, err := r.deleteFeed(context.Background(), feed)
+			if err != nil {
+				t.Errorf("deleteFeed() error = %v", err)
+			}
+			if diff := cmp.Diff(ctrl.Result{}, got); diff != "" {
+				t.Errorf("deleteFeed() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_authFailureReason(t *testing.T) {
+	var (
+		secretFeed  = &skynewzdevv1alpha1.Feed{Spec: skynewzdevv1alpha1.FeedSpec{AuthSecretRef: &skynewzdevv1alpha1.AuthSecretReference{Name: "putio-token", Key: "token"}}}
+		accountFeed = &skynewzdevv1alpha1.Feed{Spec: skynewzdevv1alpha1.FeedSpec{AccountRef: &skynewzdevv1alpha1.AccountReference{Name: "shared"}}}
+		notFound    = apierrors.NewNotFound(schema.GroupResource{}, "foo")
+	)
+
+	type args struct {
+		feed *skynewzdevv1alpha1.Feed
+		err  error
+	}
+	tests := []struct {
+		name string
+		args args
+		want FeedConditionReason
+	}{
+		{
+			name: "secret not found",
+			args: args{feed: secretFeed, err: fmt.Errorf("cannot get secret: %w", notFound)},
+			want: FeedSecretNotFound,
+		},
+		{
+			name: "secret key missing",
+			args: args{feed: secretFeed, err: fmt.Errorf("cannot read key: %w", errSecretKeyMissing)},
+			want: FeedSecretKeyMissing,
+		},
+		{
+			name: "account not found",
+			args: args{feed: accountFeed, err: fmt.Errorf("cannot get account: %w", notFound)},
+			want: FeedAccountUnavailable,
+		},
+		{
+			name: "account not allowed",
+			args: args{feed: accountFeed, err: fmt.Errorf("cannot use account: %w", errAccountNotAllowed)},
+			want: FeedAccountUnavailable,
+		},
+		{
+			name: "token rejected",
+			args: args{feed: secretFeed, err: fmt.Errorf("unable to check token: %w", &goputio.ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnauthorized}})},
+			want: FeedTokenRejected,
+		},
+		{
+			name: "Put.io unavailable",
+			args: args{feed: secretFeed, err: fmt.Errorf("unable to check token: %w", &goputio.ErrorResponse{Response: &http.Response{StatusCode: http.StatusBadGateway}})},
+			want: FeedTokenCheckFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authFailureReason(tt.args.feed, tt.args.err); got != tt.want {
+				t.Errorf("authFailureReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isTokenChecked(t *testing.T) {
+	accepted := []metav1.Condition{makeFeedAuthReadyCondition(metav1.ConditionTrue, FeedTokenAccepted, "")}
+	rejected := []metav1.Condition{makeFeedAuthReadyCondition(metav1.ConditionFalse, FeedTokenRejected, "")}
+
+	tests := []struct {
+		name    string
+		status  skynewzdevv1alpha1.FeedStatus
+		version string
+		want    bool
+	}{
+		{
+			name:    "accepted at this version",
+			status:  skynewzdevv1alpha1.FeedStatus{AuthSecretVersion: "42", Conditions: accepted},
+			version: "42",
+			want:    true,
+		},
+		{
+			name:    "secret changed",
+			status:  skynewzdevv1alpha1.FeedStatus{AuthSecretVersion: "42", Conditions: accepted},
+			version: "43",
+			want:    false,
+		},
+		{
+			name:    "rejected at this version",
+			status:  skynewzdevv1alpha1.FeedStatus{AuthSecretVersion: "42", Conditions: rejected},
+			version: "42",
+			want:    false,
+		},
+		{
+			name:    "never checked",
+			status:  skynewzdevv1alpha1.FeedStatus{},
+			version: "",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTokenChecked(&skynewzdevv1alpha1.Feed{Status: tt.status}, tt.version); got != tt.want {
+				t.Errorf("isTokenChecked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_makeStatusTime(t *testing.T) {
+	now := time.Date(2022, time.September, 11, 19, 46, 39, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		t    putio.Time
+		want *metav1.Time
+	}{
+		{
+			name: "not set by Put.io",
+			t:    putio.Time{},
+			want: nil,
+		},
+		{
+			name: "expected",
+			t:    putio.Time{Time: now},
+			want: &metav1.Time{Time: now},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, makeStatusTime(tt.t)); diff != "" {
+				t.Errorf("makeStatusTime() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_checkParentDir(t *testing.T) {
+	files := &fakeFilesService{
+		files: []*putio.File{
+			{ID: 1, Name: "TV Shows", ContentType: "application/x-directory"},
+			{ID: 2, Name: "movie.mkv", ContentType: "video/x-matroska"},
+			{ID: 3, Name: "Shared with me", ContentType: "application/x-directory", IsShared: true},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		want    FeedConditionReason
+		wantErr error
+	}{
+		{
+			name: "root directory",
+			id:   0,
+			want: FeedParentDirFound,
+		},
+		{
+			name: "folder",
+			id:   1,
+			want: FeedParentDirFound,
+		},
+		{
+			name:    "missing folder",
+			id:      42,
+			want:    FeedParentDirNotFound,
+			wantErr: errFolderNotFound,
+		},
+		{
+			name:    "file",
+			id:      2,
+			want:    FeedParentDirNotADirectory,
+			wantErr: errNotADirectory,
+		},
+		{
+			name:    "shared folder",
+			id:      3,
+			want:    FeedParentDirNotOwned,
+			wantErr: errFolderNotOwned,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkParentDir(context.Background(), files, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkParentDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("checkParentDir() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedReconciler_findParentDir(t *testing.T) {
+	makeClient := func() *putio.Client {
+		return &putio.Client{Files: &fakeFilesService{
+			nextID: 100,
+			files:  []*putio.File{{ID: 1, Name: "TV Shows", ContentType: "application/x-directory"}},
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		spec    skynewzdevv1alpha1.FeedSpec
+		want    FeedConditionReason
+		wantID  *uint
+		wantErr error
+	}{
+		{
+			name:   "folder ID",
+			spec:   skynewzdevv1alpha1.FeedSpec{ParentDirID: uintToPtr(1)},
+			want:   "",
+			wantID: nil,
+		},
+		{
+			name:   "existing folder path",
+			spec:   skynewzdevv1alpha1.FeedSpec{ParentDirPath: "TV Shows"},
+			want:   "",
+			wantID: uintToPtr(1),
+		},
+		{
+			name:    "missing folder path",
+			spec:    skynewzdevv1alpha1.FeedSpec{ParentDirPath: "TV Shows/For all mankind"},
+			want:    FeedParentDirNotFound,
+			wantErr: errFolderNotFound,
+		},
+		{
+			name:   "existing folder to create",
+			spec:   skynewzdevv1alpha1.FeedSpec{CreateParentDir: "TV Shows"},
+			want:   "",
+			wantID: uintToPtr(1),
+		},
+		{
+			name:   "missing folder to create",
+			spec:   skynewzdevv1alpha1.FeedSpec{CreateParentDir: "TV Shows/For all mankind"},
+			want:   FeedParentDirCreated,
+			wantID: uintToPtr(101),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &FeedReconciler{Recorder: record.NewFakeRecorder(10)}
+			feed := &skynewzdevv1alpha1.Feed{Spec: tt.spec}
+			got, err := r.findParentDir(context.Background(), feed, makeClient())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("findParentDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("findParentDir() got = %v, want %v", got, tt.want)
+			}
+			if diff := cmp.Diff(tt.wantID, feed.Status.ParentDirID); err == nil && diff != "" {
+				t.Errorf("findParentDir() status.parent_dir_id mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
@@ -248,7 +807,7 @@ var _ = Describe("Feed controller", func() {
 				Immutable: nil,
 				Data:      nil,
 				StringData: map[string]string{
-					SecretKeyName: os.Getenv("PUTIO_TESTING_OAUTH_TOKEN"),
+					SecretKeyName: fakePutioToken,
 				},
 				Type: corev1.SecretTypeOpaque,
 			}
@@ -276,7 +835,7 @@ var _ = Describe("Feed controller", func() {
 					Keyword:              "foo",
 					UnwantedKeywords:     "",
 					Paused:               boolToPtr(true),
-					AuthSecretRef: skynewzdevv1alpha1.AuthSecretReference{
+					AuthSecretRef: &skynewzdevv1alpha1.AuthSecretReference{
 						Name: SecretName,
 						Key:  SecretKeyName,
 					},
@@ -316,7 +875,10 @@ var _ = Describe("Feed controller", func() {
 			Expect(f.Keyword).Should(Equal("foo"))
 
 			By("By checking title")
-			Expect(f.Title).Should(Equal(fmt.Sprintf(titleFormat, createdFeed.Spec.Title, createdFeed.GetGeneration())))
+			Expect(f.Title).Should(Equal(defaultFeedTitleTemplate.Render(createdFeed)))
+
+			By("By checking spec hash")
+			Expect(createdFeed.Status.SpecHash).Should(Equal(computeSpecHash(ctx, createdFeed, createdFeed.Spec.RssSourceURL, defaultFeedTitleTemplate)))
 		})
 	})
 
@@ -370,16 +932,74 @@ var _ = Describe("Feed controller", func() {
 			Expect(f).Should(BeNil())
 		})
 	})
+
+	Context("When creating two feeds with the same title", func() {
+		It("Should not adopt the Put.io feed of the other one", func() {
+			names := []string{FeedName + "-first", FeedName + "-second"}
+			ids := make([]*uint, len(names))
+
+			for i, name := range names {
+				By("By creating feed " + name)
+				feed := &skynewzdevv1alpha1.Feed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: FeedNamespace,
+					},
+					Spec: skynewzdevv1alpha1.FeedSpec{
+						Title:                FeedName,
+						RssSourceURL:         "https://www.google.com",
+						ParentDirID:          uintToPtr(0),
+						DeleteOldFiles:       boolToPtr(false),
+						DontProcessWholeFeed: boolToPtr(false),
+						Keyword:              name,
+						Paused:               boolToPtr(true),
+						AuthSecretRef: &skynewzdevv1alpha1.AuthSecretReference{
+							Name: SecretName,
+							Key:  SecretKeyName,
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, feed)).Should(Succeed())
+
+				// wait for the first feed to claim its Put.io feed before creating the second one
+				feedLookupKey := types.NamespacedName{Name: name, Namespace: FeedNamespace}
+				Eventually(func() (*uint, error) {
+					createdFeed := &skynewzdevv1alpha1.Feed{}
+					if err := k8sClient.Get(ctx, feedLookupKey, createdFeed); err != nil {
+						return nil, err
+					}
+
+					ids[i] = createdFeed.Status.ID
+					return ids[i], nil
+				}, timeout, interval).ShouldNot(BeNil())
+			}
+
+			By("By checking each feed owns its own Put.io feed")
+			Expect(*ids[1]).ShouldNot(Equal(*ids[0]))
+
+			for i, name := range names {
+				f, err := getPutioFeed(ids[i])
+				Expect(err).Should(BeNil())
+				Expect(f.Keyword).Should(Equal(name))
+			}
+		})
+	})
 })
 
+// getPutioFeed returns the feed with given ID stored by the fake Put.io API.
 func getPutioFeed(feedID *uint) (*putio.Feed, error) {
-	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: os.Getenv("PUTIO_TESTING_OAUTH_TOKEN")})
-	oauthClient := oauth2.NewClient(ctx, tokenSource)
-	putioClient := putio.New(context.Background(), oauthClient)
+	feed, ok := fakePutio.Feed(*feedID)
+	if !ok {
+		return nil, fmt.Errorf("feed %d not found", *feedID)
+	}
 
-	return putioClient.Rss.Get(ctx, *feedID)
+	return &feed, nil
 }
 
 func boolToPtr(v bool) *bool {
 	return &v
 }
+
+func uintToPtr(v uint) *uint {
+	return &v
+}
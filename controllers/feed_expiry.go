@@ -0,0 +1,122 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// checkExpiry records whether given feed expired at given time in its Expired condition, from the items counted in
+// its status. The condition is removed from feeds without expiry.
+func (r *FeedReconciler) checkExpiry(ctx context.Context, feed *skynewzdevv1alpha1.Feed, now time.Time) {
+	_, span := tracer.Start(ctx, "controllers.FeedReconciler.checkExpiry")
+	defer span.End()
+
+	if feed.Spec.Expiry == nil {
+		meta.RemoveStatusCondition(&feed.Status.Conditions, string(FeedExpired))
+		return
+	}
+
+	wasExpired := isExpired(feed)
+	condition := evaluateExpiry(feed, now)
+	meta.SetStatusCondition(&feed.Status.Conditions, condition)
+
+	span.SetAttributes(attribute.String("feed.expiry", condition.Reason))
+
+	if isExpired(feed) && !wasExpired {
+		r.Recorder.Eventf(feed, corev1.EventTypeNormal, eventFeedExpired, "%s, applying %s action", condition.Message, expiryAction(feed))
+	}
+}
+
+// evaluateExpiry returns the Expired condition of given feed at given time, from the first expiry reached.
+func evaluateExpiry(feed *skynewzdevv1alpha1.Feed, now time.Time) metav1.Condition {
+	expiry := feed.Spec.Expiry
+
+	if expiry.After != nil && !now.Before(expiry.After.Time) {
+		message := fmt.Sprintf("feed expired on %s", expiry.After.UTC().Format(time.RFC3339))
+		return makeFeedExpiredCondition(metav1.ConditionTrue, FeedExpiryDateReached, message)
+	}
+
+	if expiry.AfterMatches != nil && feed.Status.CompletedItemCount >= *expiry.AfterMatches {
+		message := fmt.Sprintf("feed expired after %d completed items", feed.Status.CompletedItemCount)
+		return makeFeedExpiredCondition(metav1.ConditionTrue, FeedMatchesReached, message)
+	}
+
+	if deadline := inactivityDeadline(feed); deadline != nil && !now.Before(deadline.Time) {
+		message := fmt.Sprintf("feed expired after no new item for %s", expiry.InactiveFor.Duration)
+		return makeFeedExpiredCondition(metav1.ConditionTrue, FeedInactive, message)
+	}
+
+	return makeFeedExpiredCondition(metav1.ConditionFalse, FeedNotExpired, "")
+}
+
+// inactivityDeadline returns when given feed expires for lack of new items, nil without inactiveFor.
+// Feeds which never processed an item are inactive since their creation.
+func inactivityDeadline(feed *skynewzdevv1alpha1.Feed) *metav1.Time {
+	if feed.Spec.Expiry == nil || feed.Spec.Expiry.InactiveFor == nil {
+		return nil
+	}
+
+	since := feed.CreationTimestamp
+	if feed.Status.LastItemAt != nil {
+		since = *feed.Status.LastItemAt
+	}
+
+	return &metav1.Time{Time: since.Add(feed.Spec.Expiry.InactiveFor.Duration)}
+}
+
+// expiryDeadline returns the earliest time given feed expires at regardless of its items, nil when it has none
+// or already expired.
+func expiryDeadline(feed *skynewzdevv1alpha1.Feed) *metav1.Time {
+	if feed.Spec.Expiry == nil || isExpired(feed) {
+		return nil
+	}
+
+	deadline := feed.Spec.Expiry.After
+	if inactive := inactivityDeadline(feed); inactive != nil && (deadline == nil || inactive.Before(deadline)) {
+		deadline = inactive
+	}
+
+	return deadline
+}
+
+// expiryAction returns what happens to given feed once expired.
+func expiryAction(feed *skynewzdevv1alpha1.Feed) skynewzdevv1alpha1.ExpiryAction {
+	if feed.Spec.Expiry == nil || feed.Spec.Expiry.OnExpiry == "" {
+		return skynewzdevv1alpha1.ExpiryActionPause
+	}
+
+	return feed.Spec.Expiry.OnExpiry
+}
+
+// isExpired tells whether given feed expired.
+func isExpired(feed *skynewzdevv1alpha1.Feed) bool {
+	return meta.IsStatusConditionTrue(feed.Status.Conditions, string(FeedExpired))
+}
+
+// isPausedOnExpiry tells whether given feed is paused because it expired.
+func isPausedOnExpiry(feed *skynewzdevv1alpha1.Feed) bool {
+	return isExpired(feed) && expiryAction(feed) == skynewzdevv1alpha1.ExpiryActionPause
+}
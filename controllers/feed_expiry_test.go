@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_evaluateExpiry(t *testing.T) {
+	now := time.Date(2022, 10, 13, 21, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(d)}
+	}
+
+	tests := []struct {
+		name   string
+		expiry *skynewzdevv1alpha1.FeedExpiry
+		status skynewzdevv1alpha1.FeedStatus
+		want   metav1.Condition
+	}{
+		{
+			name:   "before expiry date",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{After: at(time.Minute)},
+			want:   makeFeedExpiredCondition(metav1.ConditionFalse, FeedNotExpired, ""),
+		},
+		{
+			name:   "expiry date reached",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{After: at(0)},
+			want:   makeFeedExpiredCondition(metav1.ConditionTrue, FeedExpiryDateReached, "feed expired on 2022-10-13T21:00:00Z"),
+		},
+		{
+			name:   "matches not reached",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{AfterMatches: int32ToPtr(3)},
+			status: skynewzdevv1alpha1.FeedStatus{CompletedItemCount: 2},
+			want:   makeFeedExpiredCondition(metav1.ConditionFalse, FeedNotExpired, ""),
+		},
+		{
+			name:   "matches reached",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{AfterMatches: int32ToPtr(3)},
+			status: skynewzdevv1alpha1.FeedStatus{CompletedItemCount: 3},
+			want:   makeFeedExpiredCondition(metav1.ConditionTrue, FeedMatchesReached, "feed expired after 3 completed items"),
+		},
+		{
+			name:   "recent item",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{InactiveFor: &metav1.Duration{Duration: 24 * time.Hour}},
+			status: skynewzdevv1alpha1.FeedStatus{LastItemAt: at(-time.Hour)},
+			want:   makeFeedExpiredCondition(metav1.ConditionFalse, FeedNotExpired, ""),
+		},
+		{
+			name:   "inactive since last item",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{InactiveFor: &metav1.Duration{Duration: 24 * time.Hour}},
+			status: skynewzdevv1alpha1.FeedStatus{LastItemAt: at(-25 * time.Hour)},
+			want:   makeFeedExpiredCondition(metav1.ConditionTrue, FeedInactive, "feed expired after no new item for 24h0m0s"),
+		},
+		{
+			name:   "inactive since creation",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{InactiveFor: &metav1.Duration{Duration: time.Hour}},
+			want:   makeFeedExpiredCondition(metav1.ConditionTrue, FeedInactive, "feed expired after no new item for 1h0m0s"),
+		},
+		{
+			name: "expiry date first",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{
+				After:        at(-time.Minute),
+				AfterMatches: int32ToPtr(1),
+			},
+			status: skynewzdevv1alpha1.FeedStatus{CompletedItemCount: 1},
+			want:   makeFeedExpiredCondition(metav1.ConditionTrue, FeedExpiryDateReached, "feed expired on 2022-10-13T20:59:00Z"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := &skynewzdevv1alpha1.Feed{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: *at(-2 * time.Hour)},
+				Spec:       skynewzdevv1alpha1.FeedSpec{Expiry: tt.expiry},
+				Status:     tt.status,
+			}
+			if diff := cmp.Diff(tt.want, evaluateExpiry(feed, now)); diff != "" {
+				t.Errorf("evaluateExpiry() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_expiryDeadline(t *testing.T) {
+	now := time.Date(2022, 10, 13, 21, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(d)}
+	}
+
+	tests := []struct {
+		name    string
+		expiry  *skynewzdevv1alpha1.FeedExpiry
+		status  skynewzdevv1alpha1.FeedStatus
+		expired bool
+		want    *metav1.Time
+	}{
+		{name: "no expiry", want: nil},
+		{name: "matches only", expiry: &skynewzdevv1alpha1.FeedExpiry{AfterMatches: int32ToPtr(1)}, want: nil},
+		{name: "expiry date", expiry: &skynewzdevv1alpha1.FeedExpiry{After: at(time.Hour)}, want: at(time.Hour)},
+		{
+			name:   "inactivity since creation",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{InactiveFor: &metav1.Duration{Duration: 3 * time.Hour}},
+			want:   at(time.Hour),
+		},
+		{
+			name:   "inactivity since last item",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{InactiveFor: &metav1.Duration{Duration: 3 * time.Hour}},
+			status: skynewzdevv1alpha1.FeedStatus{LastItemAt: at(0)},
+			want:   at(3 * time.Hour),
+		},
+		{
+			name: "earliest deadline",
+			expiry: &skynewzdevv1alpha1.FeedExpiry{
+				After:       at(2 * time.Hour),
+				InactiveFor: &metav1.Duration{Duration: 3 * time.Hour},
+			},
+			want: at(time.Hour),
+		},
+		{name: "already expired", expiry: &skynewzdevv1alpha1.FeedExpiry{After: at(time.Hour)}, expired: true, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := &skynewzdevv1alpha1.Feed{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: *at(-2 * time.Hour)},
+				Spec:       skynewzdevv1alpha1.FeedSpec{Expiry: tt.expiry},
+				Status:     tt.status,
+			}
+			if tt.expired {
+				meta.SetStatusCondition(&feed.Status.Conditions, makeFeedExpiredCondition(metav1.ConditionTrue, FeedExpiryDateReached, ""))
+			}
+			if diff := cmp.Diff(tt.want, expiryDeadline(feed)); diff != "" {
+				t.Errorf("expiryDeadline() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_isPausedOnExpiry(t *testing.T) {
+	tests := []struct {
+		name     string
+		onExpiry skynewzdevv1alpha1.ExpiryAction
+		expired  bool
+		want     bool
+	}{
+		{name: "not expired", onExpiry: skynewzdevv1alpha1.ExpiryActionPause, expired: false, want: false},
+		{name: "paused", onExpiry: skynewzdevv1alpha1.ExpiryActionPause, expired: true, want: true},
+		{name: "default action", onExpiry: "", expired: true, want: true},
+		{name: "condition only", onExpiry: skynewzdevv1alpha1.ExpiryActionCondition, expired: true, want: false},
+		{name: "deleted", onExpiry: skynewzdevv1alpha1.ExpiryActionDelete, expired: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := &skynewzdevv1alpha1.Feed{
+				Spec: skynewzdevv1alpha1.FeedSpec{Expiry: &skynewzdevv1alpha1.FeedExpiry{OnExpiry: tt.onExpiry}},
+			}
+			status := metav1.ConditionFalse
+			if tt.expired {
+				status = metav1.ConditionTrue
+			}
+			meta.SetStatusCondition(&feed.Status.Conditions, makeFeedExpiredCondition(status, FeedMatchesReached, ""))
+			if got := isPausedOnExpiry(feed); got != tt.want {
+				t.Errorf("isPausedOnExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func int32ToPtr(v int32) *int32 {
+	return &v
+}
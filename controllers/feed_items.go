@@ -0,0 +1,106 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"go.opentelemetry.io/otel/attribute"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// countItems counts in status the items Put.io processed for given feed since the last reconciliation, when its
+// expiry or an Alert needs them. Feeds not created at Put.io yet have no item.
+func (r *FeedReconciler) countItems(ctx context.Context, putioClient *putio.Client, feed *skynewzdevv1alpha1.Feed) error {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.countItems")
+	defer span.End()
+
+	if feed.Status.ID == nil {
+		return nil
+	}
+
+	needed, err := r.needsItemCount(ctx, feed)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if !needed {
+		return nil
+	}
+
+	items, err := putioClient.Rss.Items(ctx, *feed.Status.ID)
+	if err != nil && !putio.IsNotFound(err) {
+		span.RecordError(err)
+		return fmt.Errorf("unable to list Put.io feed items: %w", err)
+	}
+
+	countFeedItems(feed, items)
+	span.SetAttributes(attribute.Int("feed.completed_items", int(feed.Status.CompletedItemCount)))
+
+	return nil
+}
+
+// needsItemCount tells whether the items of given feed have to be counted: its expiry depends on them, or an Alert
+// notifies about its downloads.
+func (r *FeedReconciler) needsItemCount(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (bool, error) {
+	if expiry := feed.Spec.Expiry; expiry != nil && (expiry.AfterMatches != nil || expiry.InactiveFor != nil) {
+		return true, nil
+	}
+
+	alerts := new(skynewzdevv1alpha1.AlertList)
+	if err := r.List(ctx, alerts, client.InNamespace(feed.Namespace)); err != nil {
+		return false, fmt.Errorf("unable to list alerts: %w", err)
+	}
+
+	return anyAlertMatches(alerts.Items, feed, skynewzdevv1alpha1.AlertEventDownload), nil
+}
+
+// countFeedItems adds to the status of given feed the items processed since the last one counted.
+// Items are counted from their ID, so that clearing the Put.io feed log does not count them again.
+// The last item ID is set from the first count on, even without item, telling the feed items are tracked.
+func countFeedItems(feed *skynewzdevv1alpha1.Feed, items []*putio.FeedItem) {
+	var last uint
+	if feed.Status.LastItemID != nil {
+		last = *feed.Status.LastItemID
+	}
+
+	newest, newestCompleted := last, last
+	for _, item := range items {
+		if item.ID <= last {
+			continue
+		}
+
+		if !item.IsFailed {
+			feed.Status.CompletedItemCount++
+			if item.ID > newestCompleted {
+				newestCompleted = item.ID
+				feed.Status.LastCompletedItem = item.Title
+			}
+		}
+
+		if item.ID > newest {
+			newest = item.ID
+			feed.Status.LastItemAt = makeStatusTime(item.ProcessedAt)
+		}
+	}
+
+	feed.Status.LastItemID = &newest
+}
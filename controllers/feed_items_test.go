@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// listClient is a client.Client listing the fixed resources of its reader.
+type listClient struct {
+	client.Client
+	reader *listReader
+}
+
+func (c listClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return c.reader.List(ctx, list, opts...)
+}
+
+func Test_countFeedItems(t *testing.T) {
+	processedAt := time.Date(2022, 10, 13, 21, 0, 0, 0, time.UTC)
+	items := []*putio.FeedItem{
+		{ID: 12, Title: "episode 3", ProcessedAt: putio.Time{Time: processedAt}},
+		{ID: 11, Title: "episode 2", IsFailed: true, ProcessedAt: putio.Time{Time: processedAt.Add(-time.Hour)}},
+		{ID: 10, Title: "episode 1", ProcessedAt: putio.Time{Time: processedAt.Add(-2 * time.Hour)}},
+	}
+	lastItemID := func(id uint) *uint { return &id }
+
+	tests := []struct {
+		name   string
+		status skynewzdevv1alpha1.FeedStatus
+		items  []*putio.FeedItem
+		want   skynewzdevv1alpha1.FeedStatus
+	}{
+		{
+			name:  "no item",
+			items: nil,
+			want:  skynewzdevv1alpha1.FeedStatus{LastItemID: lastItemID(0)},
+		},
+		{
+			name:  "first items",
+			items: items,
+			want: skynewzdevv1alpha1.FeedStatus{
+				CompletedItemCount: 2,
+				LastItemID:         lastItemID(12),
+				LastItemAt:         &metav1.Time{Time: processedAt},
+				LastCompletedItem:  "episode 3",
+			},
+		},
+		{
+			name: "already counted items",
+			status: skynewzdevv1alpha1.FeedStatus{
+				CompletedItemCount: 1,
+				LastItemID:         lastItemID(10),
+				LastItemAt:         &metav1.Time{Time: processedAt.Add(-2 * time.Hour)},
+				LastCompletedItem:  "episode 1",
+			},
+			items: items,
+			want: skynewzdevv1alpha1.FeedStatus{
+				CompletedItemCount: 2,
+				LastItemID:         lastItemID(12),
+				LastItemAt:         &metav1.Time{Time: processedAt},
+				LastCompletedItem:  "episode 3",
+			},
+		},
+		{
+			name: "newest item failed",
+			status: skynewzdevv1alpha1.FeedStatus{
+				CompletedItemCount: 1,
+				LastItemID:         lastItemID(10),
+				LastCompletedItem:  "episode 1",
+			},
+			items: items[1:],
+			want: skynewzdevv1alpha1.FeedStatus{
+				CompletedItemCount: 1,
+				LastItemID:         lastItemID(11),
+				LastItemAt:         &metav1.Time{Time: processedAt.Add(-time.Hour)},
+				LastCompletedItem:  "episode 1",
+			},
+		},
+		{
+			name: "log cleared",
+			status: skynewzdevv1alpha1.FeedStatus{
+				CompletedItemCount: 2,
+				LastItemID:         lastItemID(12),
+				LastItemAt:         &metav1.Time{Time: processedAt},
+				LastCompletedItem:  "episode 3",
+			},
+			items: nil,
+			want: skynewzdevv1alpha1.FeedStatus{
+				CompletedItemCount: 2,
+				LastItemID:         lastItemID(12),
+				LastItemAt:         &metav1.Time{Time: processedAt},
+				LastCompletedItem:  "episode 3",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := &skynewzdevv1alpha1.Feed{Status: tt.status}
+			countFeedItems(feed, tt.items)
+			if diff := cmp.Diff(tt.want, feed.Status); diff != "" {
+				t.Errorf("countFeedItems() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFeedReconciler_needsItemCount(t *testing.T) {
+	matches := int32(10)
+	feed := func(expiry *skynewzdevv1alpha1.FeedExpiry) *skynewzdevv1alpha1.Feed {
+		return &skynewzdevv1alpha1.Feed{
+			ObjectMeta: metav1.ObjectMeta{Name: "andor", Namespace: "default"},
+			Spec:       skynewzdevv1alpha1.FeedSpec{Expiry: expiry},
+		}
+	}
+	alert := func(namespace string, events ...skynewzdevv1alpha1.AlertEventType) skynewzdevv1alpha1.Alert {
+		return skynewzdevv1alpha1.Alert{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: namespace},
+			Spec:       skynewzdevv1alpha1.AlertSpec{Events: events},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		feed   *skynewzdevv1alpha1.Feed
+		alerts []skynewzdevv1alpha1.Alert
+		want   bool
+	}{
+		{
+			name: "no expiry, no alert",
+			feed: feed(nil),
+			want: false,
+		},
+		{
+			name: "expiry after a date",
+			feed: feed(&skynewzdevv1alpha1.FeedExpiry{After: &metav1.Time{Time: time.Now()}}),
+			want: false,
+		},
+		{
+			name: "expiry after matches",
+			feed: feed(&skynewzdevv1alpha1.FeedExpiry{AfterMatches: &matches}),
+			want: true,
+		},
+		{
+			name: "expiry after inactivity",
+			feed: feed(&skynewzdevv1alpha1.FeedExpiry{InactiveFor: &metav1.Duration{Duration: time.Hour}}),
+			want: true,
+		},
+		{
+			name:   "alert subscribing to downloads",
+			feed:   feed(nil),
+			alerts: []skynewzdevv1alpha1.Alert{alert("default", skynewzdevv1alpha1.AlertEventDownload)},
+			want:   true,
+		},
+		{
+			name:   "alert subscribing to every event",
+			feed:   feed(nil),
+			alerts: []skynewzdevv1alpha1.Alert{alert("default")},
+			want:   true,
+		},
+		{
+			name:   "alert subscribing to other events",
+			feed:   feed(nil),
+			alerts: []skynewzdevv1alpha1.Alert{alert("default", skynewzdevv1alpha1.AlertEventError)},
+			want:   false,
+		},
+		{
+			name:   "alert of another namespace",
+			feed:   feed(nil),
+			alerts: []skynewzdevv1alpha1.Alert{alert("other", skynewzdevv1alpha1.AlertEventDownload)},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &FeedReconciler{Client: listClient{reader: &listReader{alerts: tt.alerts}}}
+			got, err := r.needsItemCount(context.Background(), tt.feed)
+			if err != nil {
+				t.Fatalf("needsItemCount() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("needsItemCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
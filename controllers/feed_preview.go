@@ -0,0 +1,132 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/rss"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// maxPreviewItems bounds the number of matched item titles written in status.
+const maxPreviewItems = 100
+
+// defaultPreviewHTTPClient fetches RSS feeds. It is not instrumented as tracing would record the source URL,
+// rss.Fetch traces the requests with a redacted URL instead.
+var defaultPreviewHTTPClient = &http.Client{
+	Timeout: time.Second * 30,
+}
+
+// previewFeed fetches the RSS feed and writes the items matching the keywords in status, instead of creating the Put.io feed.
+// The RSS feed is fetched again on resync or when the spec changes, not on every reconciliation.
+func (r *FeedReconciler) previewFeed(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.previewFeed")
+	defer span.End()
+
+	if refreshIn, fresh := previewRefreshIn(feed, time.Now(), r.ResyncInterval); fresh {
+		log.FromContext(ctx).Info("Feed preview up to date", "refreshIn", refreshIn.String())
+		return ctrl.Result{RequeueAfter: refreshIn}, nil
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = defaultPreviewHTTPClient
+	}
+
+	rssSourceURL, err := r.resolveRSSSourceURL(ctx, feed)
+	if err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	keyword, unwanted := feed.PutioKeywords()
+	preview, err := rss.MakePreview(ctx, client, rssSourceURL, keyword, unwanted)
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(feed, corev1.EventTypeWarning, eventUnableToPreviewFeed, err.Error())
+		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedAvailableCondition(metav1.ConditionFalse, FeedPreviewFailed, err.Error()))
+		if updateErr := r.Status().Update(ctx, feed); updateErr != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to update feed status: %w", updateErr)
+		}
+
+		return ctrl.Result{}, fmt.Errorf("unable to preview feed: %w", err)
+	}
+
+	feed.Status.Preview = makeFeedPreview(preview, metav1.Now())
+	message := fmt.Sprintf("%d of %d items match, the Put.io feed is not created while previewing", feed.Status.Preview.MatchedItemCount, feed.Status.Preview.TotalItems)
+	condition := makeFeedAvailableCondition(metav1.ConditionFalse, FeedPreviewing, message)
+	condition.ObservedGeneration = feed.Generation
+	meta.SetStatusCondition(&feed.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, feed); err != nil {
+		r.Recorder.Event(feed, corev1.EventTypeWarning, eventUnableToUpdateFeedStatus, err.Error())
+		return ctrl.Result{}, err //nolint:wrapcheck
+	}
+
+	r.Recorder.Event(feed, corev1.EventTypeNormal, eventFeedPreviewed, message)
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// previewRefreshIn tells whether the preview in status has been made from the current spec,
+// and when it must be refreshed. A zero resync interval keeps the preview until the spec changes.
+func previewRefreshIn(feed *skynewzdevv1alpha1.Feed, now time.Time, resyncInterval time.Duration) (time.Duration, bool) {
+	condition := meta.FindStatusCondition(feed.Status.Conditions, string(FeedAvailable))
+	if feed.Status.Preview == nil || condition == nil || condition.Reason != string(FeedPreviewing) || condition.ObservedGeneration != feed.Generation {
+		return 0, false
+	}
+
+	if resyncInterval == 0 {
+		return 0, true
+	}
+
+	refreshIn := feed.Status.Preview.FetchedAt.Add(resyncInterval).Sub(now)
+	return refreshIn, refreshIn > 0
+}
+
+// makeFeedPreview lists the titles of the matched items, the most recent first.
+func makeFeedPreview(preview *rss.Preview, fetchedAt metav1.Time) *skynewzdevv1alpha1.FeedPreview {
+	matches := make([]rss.Item, len(preview.Matches))
+	copy(matches, preview.Matches)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Published.After(matches[j].Published)
+	})
+
+	if len(matches) > maxPreviewItems {
+		matches = matches[:maxPreviewItems]
+	}
+
+	titles := make([]string, 0, len(matches))
+	for _, item := range matches {
+		titles = append(titles, item.Title)
+	}
+
+	return &skynewzdevv1alpha1.FeedPreview{
+		TotalItems:       len(preview.Items),
+		MatchedItems:     titles,
+		MatchedItemCount: len(preview.Matches),
+		FetchedAt:        fetchedAt,
+	}
+}
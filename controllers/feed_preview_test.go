@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/rss"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// statusWriter counts status updates instead of sending them.
+type statusWriter struct {
+	updates int
+}
+
+func (w *statusWriter) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	w.updates++
+	return nil
+}
+
+func (w *statusWriter) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return nil
+}
+
+// statusClient is a client.Client only able to update status.
+type statusClient struct {
+	client.Client
+	status *statusWriter
+}
+
+func (c statusClient) Status() client.StatusWriter {
+	return c.status
+}
+
+func Test_makeFeedPreview(t *testing.T) {
+	now := metav1.Now()
+	day := time.Date(2022, 8, 21, 0, 0, 0, 0, time.UTC)
+
+	many := make([]rss.Item, 0, maxPreviewItems+20)
+	for i := 0; i < maxPreviewItems+20; i++ {
+		many = append(many, rss.Item{Title: fmt.Sprintf("item %d", i), Published: day.Add(time.Duration(i) * time.Hour)})
+	}
+
+	tests := []struct {
+		name    string
+		preview *rss.Preview
+		want    *skynewzdevv1alpha1.FeedPreview
+	}{
+		{
+			name: "most recent first",
+			preview: &rss.Preview{
+				Items: []rss.Item{{Title: "a"}, {Title: "b"}, {Title: "c"}},
+				Matches: []rss.Item{
+					{Title: "a", Published: day},
+					{Title: "c", Published: day.Add(time.Hour)},
+				},
+			},
+			want: &skynewzdevv1alpha1.FeedPreview{
+				TotalItems:       3,
+				MatchedItems:     []string{"c", "a"},
+				MatchedItemCount: 2,
+				FetchedAt:        now,
+			},
+		},
+		{
+			name:    "nothing matches",
+			preview: &rss.Preview{Items: []rss.Item{{Title: "a"}}},
+			want: &skynewzdevv1alpha1.FeedPreview{
+				TotalItems:       1,
+				MatchedItems:     []string{},
+				MatchedItemCount: 0,
+				FetchedAt:        now,
+			},
+		},
+		{
+			name:    "titles are capped",
+			preview: &rss.Preview{Items: many, Matches: many},
+			want: func() *skynewzdevv1alpha1.FeedPreview {
+				titles := make([]string, 0, maxPreviewItems)
+				for i := len(many) - 1; len(titles) < maxPreviewItems; i-- {
+					titles = append(titles, many[i].Title)
+				}
+
+				return &skynewzdevv1alpha1.FeedPreview{
+					TotalItems:       len(many),
+					MatchedItems:     titles,
+					MatchedItemCount: len(many),
+					FetchedAt:        now,
+				}
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := makeFeedPreview(tt.preview, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("makeFeedPreview() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_previewRefreshIn(t *testing.T) {
+	now := time.Date(2022, 8, 21, 10, 0, 0, 0, time.UTC)
+	makeFeed := func(generation, observedGeneration int64, reason FeedConditionReason, fetchedAt time.Time) *skynewzdevv1alpha1.Feed {
+		condition := makeFeedAvailableCondition(metav1.ConditionFalse, reason, "")
+		condition.ObservedGeneration = observedGeneration
+
+		return &skynewzdevv1alpha1.Feed{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Status: skynewzdevv1alpha1.FeedStatus{
+				Preview:    &skynewzdevv1alpha1.FeedPreview{FetchedAt: metav1.NewTime(fetchedAt)},
+				Conditions: []metav1.Condition{condition},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		feed           *skynewzdevv1alpha1.Feed
+		resyncInterval time.Duration
+		want           time.Duration
+		wantFresh      bool
+	}{
+		{
+			name:           "fresh",
+			feed:           makeFeed(1, 1, FeedPreviewing, now.Add(-time.Minute)),
+			resyncInterval: time.Minute * 10,
+			want:           time.Minute * 9,
+			wantFresh:      true,
+		},
+		{
+			name:           "resync due",
+			feed:           makeFeed(1, 1, FeedPreviewing, now.Add(-time.Minute*10)),
+			resyncInterval: time.Minute * 10,
+			wantFresh:      false,
+		},
+		{
+			name:           "spec changed",
+			feed:           makeFeed(2, 1, FeedPreviewing, now.Add(-time.Minute)),
+			resyncInterval: time.Minute * 10,
+			wantFresh:      false,
+		},
+		{
+			name:           "preview failed",
+			feed:           makeFeed(1, 1, FeedPreviewFailed, now.Add(-time.Minute)),
+			resyncInterval: time.Minute * 10,
+			wantFresh:      false,
+		},
+		{
+			name:           "never previewed",
+			feed:           &skynewzdevv1alpha1.Feed{ObjectMeta: metav1.ObjectMeta{Generation: 1}},
+			resyncInterval: time.Minute * 10,
+			wantFresh:      false,
+		},
+		{
+			name:           "resync disabled",
+			feed:           makeFeed(1, 1, FeedPreviewing, now.Add(-time.Hour*24)),
+			resyncInterval: 0,
+			want:           0,
+			wantFresh:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fresh := previewRefreshIn(tt.feed, now, tt.resyncInterval)
+			if fresh != tt.wantFresh {
+				t.Errorf("previewRefreshIn() fresh = %v, want %v", fresh, tt.wantFresh)
+			}
+			if fresh && got != tt.want {
+				t.Errorf("previewRefreshIn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedReconciler_previewFeed_fetchesOncePerResync(t *testing.T) {
+	var fetches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		_, _ = w.Write([]byte(`<rss><channel><item><title>House.of.the.Dragon.S01E01</title></item></channel></rss>`))
+	}))
+	defer server.Close()
+
+	feed := &skynewzdevv1alpha1.Feed{
+		ObjectMeta: metav1.ObjectMeta{Name: "house-of-the-dragon", Namespace: "default", Generation: 1},
+		Spec: skynewzdevv1alpha1.FeedSpec{
+			RssSourceURL: server.URL,
+			Keyword:      "House.of.the.Dragon",
+			Preview:      true,
+		},
+	}
+
+	r := &FeedReconciler{
+		Client:         statusClient{status: &statusWriter{}},
+		Recorder:       record.NewFakeRecorder(10),
+		ResyncInterval: time.Hour,
+		HTTPClient:     server.Client(),
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := r.previewFeed(context.Background(), feed)
+		if err != nil {
+			t.Fatalf("previewFeed() error = %v", err)
+		}
+		if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+			t.Errorf("previewFeed() RequeueAfter = %v, want at most %v", result.RequeueAfter, time.Hour)
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("previewFeed() fetched the RSS feed %d times, want once", fetches)
+	}
+
+	// a spec change previews again
+	feed.Generation++
+	if _, err := r.previewFeed(context.Background(), feed); err != nil {
+		t.Fatalf("previewFeed() error = %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("previewFeed() fetched the RSS feed %d times after a spec change, want twice", fetches)
+	}
+}
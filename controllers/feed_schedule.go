@@ -0,0 +1,98 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// applySchedule records whether the schedule of given feed is active at given time in its ScheduleActive condition,
+// and when it next changes in status. Both are removed from feeds without schedule.
+func (r *FeedReconciler) applySchedule(ctx context.Context, feed *skynewzdevv1alpha1.Feed, now time.Time) error {
+	ctx, span := tracer.Start(ctx, "controllers.FeedReconciler.applySchedule")
+	defer span.End()
+
+	if feed.Spec.Schedule == nil {
+		meta.RemoveStatusCondition(&feed.Status.Conditions, string(FeedScheduleActive))
+		feed.Status.NextTransition = nil
+		return nil
+	}
+
+	schedule, err := feed.Spec.Schedule.Compile()
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(feed, corev1.EventTypeWarning, eventInvalidSchedule, err.Error())
+		meta.SetStatusCondition(&feed.Status.Conditions, makeFeedScheduleActiveCondition(metav1.ConditionUnknown, FeedInvalidSchedule, err.Error()))
+		feed.Status.NextTransition = nil
+		if err := r.Status().Update(ctx, feed); err != nil {
+			log.FromContext(ctx).Error(err, "unable to update feed status")
+		}
+
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	wasOutside := isOutsideSchedule(feed)
+	condition, next := evaluateSchedule(schedule, now)
+	meta.SetStatusCondition(&feed.Status.Conditions, condition)
+
+	feed.Status.NextTransition = nil
+	if !next.IsZero() {
+		feed.Status.NextTransition = &metav1.Time{Time: next}
+	}
+
+	span.SetAttributes(attribute.String("feed.schedule", condition.Reason))
+
+	switch outside := isOutsideSchedule(feed); {
+	case outside && !wasOutside:
+		r.Recorder.Event(feed, corev1.EventTypeNormal, eventFeedScheduled, "feed paused outside of its schedule")
+	case !outside && wasOutside:
+		r.Recorder.Event(feed, corev1.EventTypeNormal, eventFeedScheduled, "feed resumed by its schedule")
+	}
+
+	return nil
+}
+
+// evaluateSchedule returns the ScheduleActive condition of a feed with given schedule at given time,
+// along with when the schedule next changes, zero when it cannot tell.
+func evaluateSchedule(schedule *skynewzdevv1alpha1.Schedule, now time.Time) (metav1.Condition, time.Time) {
+	next := schedule.NextTransition(now)
+
+	var until string
+	if !next.IsZero() {
+		until = " until " + next.Format(time.RFC3339)
+	}
+
+	if schedule.Active(now) {
+		return makeFeedScheduleActiveCondition(metav1.ConditionTrue, FeedInsideWindow, "feed is active"+until), next
+	}
+
+	return makeFeedScheduleActiveCondition(metav1.ConditionFalse, FeedOutsideWindow, "feed is paused"+until), next
+}
+
+// isOutsideSchedule tells whether given feed is paused by its schedule.
+func isOutsideSchedule(feed *skynewzdevv1alpha1.Feed) bool {
+	return meta.IsStatusConditionFalse(feed.Status.Conditions, string(FeedScheduleActive))
+}
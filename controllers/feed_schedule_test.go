@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// releaseDays is active on Mondays and Thursdays from 20:00 to 23:00 UTC.
+var releaseDays = &skynewzdevv1alpha1.FeedSchedule{
+	Windows: []skynewzdevv1alpha1.ScheduleWindow{
+		{Days: []skynewzdevv1alpha1.Weekday{"Monday", "Thursday"}, Start: "20:00", End: "23:00"},
+	},
+}
+
+func Test_evaluateSchedule(t *testing.T) {
+	schedule, err := releaseDays.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		want     metav1.Condition
+		wantNext time.Time
+	}{
+		{
+			name: "inside window",
+			now:  time.Date(2022, 10, 13, 21, 0, 0, 0, time.UTC),
+			want: metav1.Condition{
+				Type:    string(FeedScheduleActive),
+				Status:  metav1.ConditionTrue,
+				Reason:  string(FeedInsideWindow),
+				Message: "feed is active until 2022-10-13T23:00:00Z",
+			},
+			wantNext: time.Date(2022, 10, 13, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "outside window",
+			now:  time.Date(2022, 10, 13, 23, 0, 0, 0, time.UTC),
+			want: metav1.Condition{
+				Type:    string(FeedScheduleActive),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(FeedOutsideWindow),
+				Message: "feed is paused until 2022-10-17T20:00:00Z",
+			},
+			wantNext: time.Date(2022, 10, 17, 20, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next := evaluateSchedule(schedule, tt.now)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("evaluateSchedule() mismatch (-want +got):\n%s", diff)
+			}
+
+			if !next.Equal(tt.wantNext) {
+				t.Errorf("evaluateSchedule() next = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestFeedReconciler_applySchedule(t *testing.T) {
+	tests := []struct {
+		name           string
+		schedule       *skynewzdevv1alpha1.FeedSchedule
+		conditions     []metav1.Condition
+		now            time.Time
+		wantCondition  *metav1.Condition
+		wantTransition *metav1.Time
+		wantEvents     []string
+	}{
+		{
+			name:       "no schedule",
+			conditions: []metav1.Condition{makeFeedScheduleActiveCondition(metav1.ConditionFalse, FeedOutsideWindow, "")},
+			now:        time.Date(2022, 10, 13, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "paused by schedule",
+			schedule: releaseDays,
+			now:      time.Date(2022, 10, 12, 21, 0, 0, 0, time.UTC),
+			wantCondition: &metav1.Condition{
+				Type:    string(FeedScheduleActive),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(FeedOutsideWindow),
+				Message: "feed is paused until 2022-10-13T20:00:00Z",
+			},
+			wantTransition: &metav1.Time{Time: time.Date(2022, 10, 13, 20, 0, 0, 0, time.UTC)},
+			wantEvents:     []string{"Normal FeedScheduled feed paused outside of its schedule"},
+		},
+		{
+			name:       "resumed by schedule",
+			schedule:   releaseDays,
+			conditions: []metav1.Condition{makeFeedScheduleActiveCondition(metav1.ConditionFalse, FeedOutsideWindow, "")},
+			now:        time.Date(2022, 10, 13, 20, 0, 0, 0, time.UTC),
+			wantCondition: &metav1.Condition{
+				Type:    string(FeedScheduleActive),
+				Status:  metav1.ConditionTrue,
+				Reason:  string(FeedInsideWindow),
+				Message: "feed is active until 2022-10-13T23:00:00Z",
+			},
+			wantTransition: &metav1.Time{Time: time.Date(2022, 10, 13, 23, 0, 0, 0, time.UTC)},
+			wantEvents:     []string{"Normal FeedScheduled feed resumed by its schedule"},
+		},
+		{
+			name:       "still active",
+			schedule:   releaseDays,
+			conditions: []metav1.Condition{makeFeedScheduleActiveCondition(metav1.ConditionTrue, FeedInsideWindow, "")},
+			now:        time.Date(2022, 10, 13, 22, 0, 0, 0, time.UTC),
+			wantCondition: &metav1.Condition{
+				Type:    string(FeedScheduleActive),
+				Status:  metav1.ConditionTrue,
+				Reason:  string(FeedInsideWindow),
+				Message: "feed is active until 2022-10-13T23:00:00Z",
+			},
+			wantTransition: &metav1.Time{Time: time.Date(2022, 10, 13, 23, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &FeedReconciler{Recorder: recorder}
+			feed := &skynewzdevv1alpha1.Feed{
+				Spec:   skynewzdevv1alpha1.FeedSpec{Schedule: tt.schedule},
+				Status: skynewzdevv1alpha1.FeedStatus{Conditions: tt.conditions},
+			}
+
+			if err := r.applySchedule(context.Background(), feed, tt.now); err != nil {
+				t.Fatalf("applySchedule() error = %v", err)
+			}
+
+			got := meta.FindStatusCondition(feed.Status.Conditions, string(FeedScheduleActive))
+			if diff := cmp.Diff(tt.wantCondition, got, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("applySchedule() condition mismatch (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tt.wantTransition, feed.Status.NextTransition); diff != "" {
+				t.Errorf("applySchedule() next transition mismatch (-want +got):\n%s", diff)
+			}
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+
+			if diff := cmp.Diff(tt.wantEvents, events); diff != "" {
+				t.Errorf("applySchedule() events mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFeedReconciler_requeueAfter(t *testing.T) {
+	now := time.Date(2022, 10, 13, 21, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(d)}
+	}
+
+	tests := []struct {
+		name           string
+		resyncInterval time.Duration
+		nextTransition *metav1.Time
+		expiry         *skynewzdevv1alpha1.FeedExpiry
+		want           time.Duration
+	}{
+		{name: "no schedule", resyncInterval: 10 * time.Minute, want: 10 * time.Minute},
+		{name: "transition first", resyncInterval: 10 * time.Minute, nextTransition: at(3 * time.Minute), want: 3 * time.Minute},
+		{name: "resync first", resyncInterval: 10 * time.Minute, nextTransition: at(2 * time.Hour), want: 10 * time.Minute},
+		{name: "resync disabled", resyncInterval: 0, nextTransition: at(2 * time.Hour), want: 2 * time.Hour},
+		{name: "transition due", resyncInterval: 10 * time.Minute, nextTransition: at(-time.Second), want: minRequeueAfter},
+		{name: "expiry first", resyncInterval: 10 * time.Minute, nextTransition: at(time.Hour), expiry: &skynewzdevv1alpha1.FeedExpiry{After: at(time.Minute)}, want: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &FeedReconciler{ResyncInterval: tt.resyncInterval}
+			feed := &skynewzdevv1alpha1.Feed{
+				Spec:   skynewzdevv1alpha1.FeedSpec{Expiry: tt.expiry},
+				Status: skynewzdevv1alpha1.FeedStatus{NextTransition: tt.nextTransition},
+			}
+			if got := r.requeueAfter(feed, now); got != tt.want {
+				t.Errorf("requeueAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
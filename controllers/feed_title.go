@@ -0,0 +1,149 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+)
+
+// DefaultFeedTitleTemplate decorates Put.io feed titles so the operator can recognize the feeds it manages.
+const DefaultFeedTitleTemplate = "{{ .Title }} (managed by Kubernetes/putio-operator)"
+
+const (
+	// <wanted title>|generation|managed by Kubernetes/putio-operator, used before titles were templated.
+	legacyTitleSeparator = "|"
+	legacyTitleMarker    = "managed by Kubernetes/putio-operator"
+
+	// sentinels rendered in place of the template data to recognize rendered titles.
+	titleSentinel     = "\x00title\x00"
+	nameSentinel      = "\x00name\x00"
+	namespaceSentinel = "\x00namespace\x00"
+)
+
+var errTitleTemplateWithoutTitle = errors.New("template must render {{ .Title }} exactly once")
+
+// defaultFeedTitleTemplate is used by reconcilers not given a template.
+var defaultFeedTitleTemplate = MustNewFeedTitleTemplate(DefaultFeedTitleTemplate)
+
+// FeedTitleData is given to the title template.
+type FeedTitleData struct {
+	// Title of the Feed spec.
+	Title string
+	// Name of the Feed.
+	Name string
+	// Namespace of the Feed.
+	Namespace string
+}
+
+// FeedTitleTemplate renders the title of Put.io feeds, and recognizes the rendered titles.
+type FeedTitleTemplate struct {
+	tmpl *template.Template
+
+	// matches rendered titles, nil when rendered titles are not recognizable from user titles.
+	managed *regexp.Regexp
+}
+
+// NewFeedTitleTemplate parses given text/template.
+func NewFeedTitleTemplate(text string) (*FeedTitleTemplate, error) {
+	tmpl, err := template.New("title").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse title template: %w", err)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, FeedTitleData{Title: titleSentinel, Name: nameSentinel, Namespace: namespaceSentinel}); err != nil {
+		return nil, fmt.Errorf("cannot render title template: %w", err)
+	}
+
+	if strings.Count(rendered.String(), titleSentinel) != 1 {
+		return nil, errTitleTemplateWithoutTitle
+	}
+
+	t := &FeedTitleTemplate{tmpl: tmpl}
+
+	// a template rendering the title alone leaves it clean, there is no way to tell managed feeds apart
+	if rendered.String() != titleSentinel {
+		pattern := regexp.QuoteMeta(rendered.String())
+		pattern = strings.Replace(pattern, regexp.QuoteMeta(titleSentinel), "(.*)", 1)
+		pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta(nameSentinel), "[a-z0-9.-]+")
+		pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta(namespaceSentinel), "[a-z0-9-]+")
+		t.managed = regexp.MustCompile("^" + pattern + "$")
+	}
+
+	return t, nil
+}
+
+// MustNewFeedTitleTemplate is like NewFeedTitleTemplate but panics on error.
+func MustNewFeedTitleTemplate(text string) *FeedTitleTemplate {
+	t, err := NewFeedTitleTemplate(text)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// Render returns the Put.io title of given feed.
+func (t *FeedTitleTemplate) Render(feed *skynewzdevv1alpha1.Feed) string {
+	var rendered strings.Builder
+	if err := t.tmpl.Execute(&rendered, FeedTitleData{Title: feed.Spec.Title, Name: feed.Name, Namespace: feed.Namespace}); err != nil {
+		return feed.Spec.Title // cannot happen, the template has been rendered once when parsed
+	}
+
+	return rendered.String()
+}
+
+// ParseManaged returns the wanted title of a Put.io feed managed by the operator, false if it is not managed.
+// Titles in the legacy format are recognized as well.
+func (t *FeedTitleTemplate) ParseManaged(title string) (string, bool) {
+	if wanted, ok := parseLegacyTitle(title); ok {
+		return wanted, true
+	}
+
+	if t.managed == nil {
+		return "", false
+	}
+
+	matches := t.managed.FindStringSubmatch(title)
+	if matches == nil {
+		return "", false
+	}
+
+	return matches[1], true
+}
+
+// parseLegacyTitle returns the wanted title of a Put.io feed titled
+// "<wanted title>|<generation>|managed by Kubernetes/putio-operator", false if it is not.
+func parseLegacyTitle(title string) (string, bool) {
+	parsed := strings.Split(title, legacyTitleSeparator)
+	if len(parsed) < 3 || parsed[len(parsed)-1] != legacyTitleMarker {
+		return "", false
+	}
+
+	if _, err := strconv.ParseInt(parsed[len(parsed)-2], 10, 64); err != nil {
+		return "", false
+	}
+
+	return strings.Join(parsed[:len(parsed)-2], legacyTitleSeparator), true
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"testing"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewFeedTitleTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{name: "default", text: DefaultFeedTitleTemplate, wantErr: false},
+		{name: "clean", text: "{{ .Title }}", wantErr: false},
+		{name: "with namespace", text: "[{{ .Namespace }}] {{ .Title }}", wantErr: false},
+		{name: "invalid", text: "{{ .Title", wantErr: true},
+		{name: "unknown field", text: "{{ .Title }} {{ .Foo }}", wantErr: true},
+		{name: "without title", text: "{{ .Name }}", wantErr: true},
+		{name: "title twice", text: "{{ .Title }} {{ .Title }}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFeedTitleTemplate(tt.text); (err != nil) != tt.wantErr {
+				t.Errorf("NewFeedTitleTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeedTitleTemplate_Render(t *testing.T) {
+	feed := &skynewzdevv1alpha1.Feed{
+		ObjectMeta: metav1.ObjectMeta{Name: "house-of-the-dragon", Namespace: "default"},
+		Spec:       skynewzdevv1alpha1.FeedSpec{Title: "House of the Dragon | S01"},
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "default", text: DefaultFeedTitleTemplate, want: "House of the Dragon | S01 (managed by Kubernetes/putio-operator)"},
+		{name: "clean", text: "{{ .Title }}", want: "House of the Dragon | S01"},
+		{name: "with name", text: "{{ .Title }} [{{ .Namespace }}/{{ .Name }}]", want: "House of the Dragon | S01 [default/house-of-the-dragon]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MustNewFeedTitleTemplate(tt.text).Render(feed); got != tt.want {
+				t.Errorf("Render() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedTitleTemplate_ParseManaged(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		title       string
+		want        string
+		wantManaged bool
+	}{
+		{
+			name:        "default",
+			text:        DefaultFeedTitleTemplate,
+			title:       "House of the Dragon | S01 (managed by Kubernetes/putio-operator)",
+			want:        "House of the Dragon | S01",
+			wantManaged: true,
+		},
+		{
+			name:        "not managed",
+			text:        DefaultFeedTitleTemplate,
+			title:       "House of the Dragon",
+			want:        "",
+			wantManaged: false,
+		},
+		{
+			name:        "legacy title",
+			text:        DefaultFeedTitleTemplate,
+			title:       "House of the Dragon|12|managed by Kubernetes/putio-operator",
+			want:        "House of the Dragon",
+			wantManaged: true,
+		},
+		{
+			name:        "legacy title with separator",
+			text:        "{{ .Title }}",
+			title:       "House of the Dragon | S01|3|managed by Kubernetes/putio-operator",
+			want:        "House of the Dragon | S01",
+			wantManaged: true,
+		},
+		{
+			name:        "clean titles are never managed",
+			text:        "{{ .Title }}",
+			title:       "House of the Dragon (managed by Kubernetes/putio-operator)",
+			want:        "",
+			wantManaged: false,
+		},
+		{
+			name:        "with name",
+			text:        "{{ .Title }} [{{ .Namespace }}/{{ .Name }}]",
+			title:       "House of the Dragon [default/house-of-the-dragon]",
+			want:        "House of the Dragon",
+			wantManaged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, managed := MustNewFeedTitleTemplate(tt.text).ParseManaged(tt.title)
+			if got != tt.want || managed != tt.wantManaged {
+				t.Errorf("ParseManaged() = %q, %v, want %q, %v", got, managed, tt.want, tt.wantManaged)
+			}
+		})
+	}
+}
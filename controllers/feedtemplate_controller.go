@@ -0,0 +1,238 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// FeedTemplateReconciler reconciles a FeedTemplate object.
+type FeedTemplateReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=feedtemplates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=feedtemplates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=feeds,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile renders a Feed for each instance of the template, and deletes the Feeds of removed instances.
+func (r *FeedTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FeedTemplateReconciler.Reconcile")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("feedtemplate.name", req.Name),
+		attribute.String("feedtemplate.namespace", req.Namespace),
+	)
+
+	logger := log.FromContext(ctx)
+
+	feedTemplate := new(skynewzdevv1alpha1.FeedTemplate)
+	if err := r.Get(ctx, req.NamespacedName, feedTemplate); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	// generated feeds are garbage collected with their owner
+	if !feedTemplate.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	feeds, err := renderTemplateFeeds(feedTemplate)
+	if err != nil {
+		// the template has to be fixed, retrying won't help
+		span.RecordError(err)
+		r.Recorder.Event(feedTemplate, corev1.EventTypeWarning, eventUnableToRenderFeedTemplate, err.Error())
+		meta.SetStatusCondition(&feedTemplate.Status.Conditions, makeFeedTemplateReadyCondition(metav1.ConditionFalse, FeedTemplateFailedToRender, err.Error()))
+		if err := r.Status().Update(ctx, feedTemplate); err != nil {
+			return ctrl.Result{}, err //nolint:wrapcheck
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	names := make([]string, 0, len(feeds))
+	for _, desired := range feeds {
+		if err := r.applyFeed(ctx, feedTemplate, desired); err != nil {
+			span.RecordError(err)
+			r.Recorder.Event(feedTemplate, corev1.EventTypeWarning, eventUnableToApplyTemplateFeed, err.Error())
+			meta.SetStatusCondition(&feedTemplate.Status.Conditions, makeFeedTemplateReadyCondition(metav1.ConditionFalse, FeedTemplateFailedToApply, err.Error()))
+			if err := r.Status().Update(ctx, feedTemplate); err != nil {
+				logger.Error(err, "unable to update feed template status")
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		names = append(names, desired.Name)
+	}
+
+	if err := r.pruneFeeds(ctx, feedTemplate, names); err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	feedTemplate.Status.Feeds = names
+	meta.SetStatusCondition(&feedTemplate.Status.Conditions, makeFeedTemplateReadyCondition(metav1.ConditionTrue, FeedTemplateRendered, fmt.Sprintf("%d feeds rendered", len(names))))
+	if err := r.Status().Update(ctx, feedTemplate); err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err //nolint:wrapcheck
+	}
+
+	logger.Info("FeedTemplate successfully reconciled", "feeds", len(names))
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FeedTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "controllers.FeedTemplateReconciler.SetupWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&skynewzdevv1alpha1.FeedTemplate{}).
+		Owns(&skynewzdevv1alpha1.Feed{}).
+		Complete(r)
+}
+
+// applyFeed creates given feed, or updates its spec when it already exists.
+func (r *FeedTemplateReconciler) applyFeed(ctx context.Context, feedTemplate *skynewzdevv1alpha1.FeedTemplate, desired *skynewzdevv1alpha1.Feed) error {
+	ctx, span := tracer.Start(ctx, "controllers.FeedTemplateReconciler.applyFeed")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed.name", desired.Name))
+
+	feed := &skynewzdevv1alpha1.Feed{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, feed, func() error {
+		// existing feeds are only updated when generated by this template, never adopted
+		if err := checkTemplateFeedOwner(feedTemplate, feed); err != nil {
+			return err
+		}
+
+		if feed.Labels == nil {
+			feed.Labels = make(map[string]string, len(desired.Labels))
+		}
+
+		for k, v := range desired.Labels {
+			feed.Labels[k] = v
+		}
+
+		feed.Spec = desired.Spec
+		return controllerutil.SetControllerReference(feedTemplate, feed, r.Scheme) //nolint:wrapcheck
+	})
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot apply feed %q: %w", desired.Name, err)
+	}
+
+	switch result {
+	case controllerutil.OperationResultCreated:
+		r.Recorder.Eventf(feedTemplate, corev1.EventTypeNormal, eventTemplateFeedCreated, "feed %q created", feed.Name)
+	case controllerutil.OperationResultUpdated:
+		r.Recorder.Eventf(feedTemplate, corev1.EventTypeNormal, eventTemplateFeedUpdated, "feed %q updated", feed.Name)
+	}
+
+	return nil
+}
+
+// checkTemplateFeedOwner ensures given feed does not exist yet, or is controlled by given template.
+func checkTemplateFeedOwner(feedTemplate *skynewzdevv1alpha1.FeedTemplate, feed *skynewzdevv1alpha1.Feed) error {
+	if feed.ResourceVersion == "" {
+		return nil
+	}
+
+	if owner := metav1.GetControllerOf(feed); owner == nil || owner.UID != feedTemplate.UID {
+		return fmt.Errorf("feed %q: %w", feed.Name, errFeedNotOwnedByTemplate)
+	}
+
+	return nil
+}
+
+// pruneFeeds deletes the feeds owned by the template which are not named in keep.
+func (r *FeedTemplateReconciler) pruneFeeds(ctx context.Context, feedTemplate *skynewzdevv1alpha1.FeedTemplate, keep []string) error {
+	ctx, span := tracer.Start(ctx, "controllers.FeedTemplateReconciler.pruneFeeds")
+	defer span.End()
+
+	feeds := new(skynewzdevv1alpha1.FeedList)
+	if err := r.List(ctx, feeds, client.InNamespace(feedTemplate.Namespace), client.MatchingLabels{feedTemplateLabel: feedTemplate.Name}); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot list feeds of template: %w", err)
+	}
+
+	for _, feed := range findPrunableFeeds(feedTemplate, feeds.Items, keep) {
+		if err := r.Delete(ctx, feed); client.IgnoreNotFound(err) != nil {
+			span.RecordError(err)
+			r.Recorder.Event(feedTemplate, corev1.EventTypeWarning, eventUnableToPruneTemplateFeed, err.Error())
+			return fmt.Errorf("cannot delete feed %q: %w", feed.Name, err)
+		}
+
+		r.Recorder.Eventf(feedTemplate, corev1.EventTypeNormal, eventTemplateFeedPruned, "feed %q deleted", feed.Name)
+	}
+
+	return nil
+}
+
+// findPrunableFeeds returns the feeds controlled by the template which are not named in keep.
+func findPrunableFeeds(feedTemplate *skynewzdevv1alpha1.FeedTemplate, feeds []skynewzdevv1alpha1.Feed, keep []string) []*skynewzdevv1alpha1.Feed {
+	kept := make(map[string]struct{}, len(keep))
+	for _, name := range keep {
+		kept[name] = struct{}{}
+	}
+
+	prunable := make([]*skynewzdevv1alpha1.Feed, 0)
+	for i := range feeds {
+		feed := &feeds[i]
+		if owner := metav1.GetControllerOf(feed); owner == nil || owner.UID != feedTemplate.UID {
+			continue
+		}
+
+		if _, ok := kept[feed.Name]; !ok {
+			prunable = append(prunable, feed)
+		}
+	}
+
+	return prunable
+}
+
+// renderTemplateFeeds returns the feed of each instance of the template, sorted by name and labelled with the template.
+func renderTemplateFeeds(feedTemplate *skynewzdevv1alpha1.FeedTemplate) ([]*skynewzdevv1alpha1.Feed, error) {
+	feeds, err := feedTemplate.RenderFeeds()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	for _, feed := range feeds {
+		feed.Labels = map[string]string{feedTemplateLabel: feedTemplate.Name}
+	}
+
+	return feeds, nil
+}
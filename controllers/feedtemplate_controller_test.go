@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// feedClient is a client.Client holding a single existing Feed, counting the writes it receives.
+type feedClient struct {
+	client.Client
+	feed   *skynewzdevv1alpha1.Feed
+	writes int
+}
+
+func (c *feedClient) Get(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+	c.feed.DeepCopyInto(obj.(*skynewzdevv1alpha1.Feed))
+	return nil
+}
+
+func (c *feedClient) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	c.writes++
+	return nil
+}
+
+func Test_renderTemplateFeeds(t *testing.T) {
+	paused := true
+	disabled := false
+	parentDirID := uint(42)
+	rootDirID := uint(0)
+
+	spec := skynewzdevv1alpha1.FeedTemplateSpec{
+		RssSourceURL:     "https://example.com/rss",
+		KeywordTemplate:  "{{.Show}}.S{{.Season}}&1080p",
+		UnwantedKeywords: "HDR",
+		Paused:           &paused,
+		AuthSecretRef:    &skynewzdevv1alpha1.AuthSecretReference{Name: "putio-token", Key: "token"},
+	}
+
+	makeTemplate := func(keywordTemplate string, instances ...skynewzdevv1alpha1.FeedTemplateInstance) *skynewzdevv1alpha1.FeedTemplate {
+		s := spec
+		s.KeywordTemplate = keywordTemplate
+		s.Instances = instances
+		return &skynewzdevv1alpha1.FeedTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "shows", Namespace: "default"},
+			Spec:       s,
+		}
+	}
+
+	makeFeed := func(name, title, keyword string, paused bool, parentDirID uint) *skynewzdevv1alpha1.Feed {
+		return &skynewzdevv1alpha1.Feed{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{feedTemplateLabel: "shows"},
+			},
+			Spec: skynewzdevv1alpha1.FeedSpec{
+				Title:                title,
+				RssSourceURL:         "https://example.com/rss",
+				ParentDirID:          &parentDirID,
+				DeleteOldFiles:       &disabled,
+				DontProcessWholeFeed: &disabled,
+				Keyword:              keyword,
+				UnwantedKeywords:     "HDR",
+				Paused:               &paused,
+				DeletionPolicy:       skynewzdevv1alpha1.DeletionPolicyDelete,
+				AuthSecretRef:        &skynewzdevv1alpha1.AuthSecretReference{Name: "putio-token", Key: "token"},
+			},
+		}
+	}
+
+	withSchedule := func(feed *skynewzdevv1alpha1.Feed, schedule *skynewzdevv1alpha1.FeedSchedule) *skynewzdevv1alpha1.Feed {
+		feed.Spec.Schedule = schedule
+		return feed
+	}
+
+	tests := []struct {
+		name     string
+		template *skynewzdevv1alpha1.FeedTemplate
+		want     []*skynewzdevv1alpha1.Feed
+		wantErr  bool
+	}{
+		{
+			name: "renders keyword and title",
+			template: makeTemplate(spec.KeywordTemplate,
+				skynewzdevv1alpha1.FeedTemplateInstance{Name: "hotd", Show: "House.of.the.Dragon", Season: "01", ParentDirID: &parentDirID},
+				skynewzdevv1alpha1.FeedTemplateInstance{Name: "andor", Title: "Andor", Show: "Andor", Season: "02", Paused: &disabled},
+			),
+			want: []*skynewzdevv1alpha1.Feed{
+				makeFeed("shows-andor", "Andor", "Andor.S02&1080p", false, rootDirID),
+				makeFeed("shows-hotd", "House.of.the.Dragon", "House.of.the.Dragon.S01&1080p", true, parentDirID),
+			},
+		},
+		{
+			name: "instance keyword and values",
+			template: makeTemplate("{{.Show}}&{{.Values.quality}}",
+				skynewzdevv1alpha1.FeedTemplateInstance{Name: "a", Show: "A", Values: map[string]string{"quality": "2160p"}},
+				skynewzdevv1alpha1.FeedTemplateInstance{Name: "b", Title: "B", Keyword: "B&720p"},
+			),
+			want: []*skynewzdevv1alpha1.Feed{
+				makeFeed("shows-a", "A", "A&2160p", true, rootDirID),
+				makeFeed("shows-b", "B", "B&720p", true, rootDirID),
+			},
+		},
+		{
+			name: "instance schedule",
+			template: makeTemplate(spec.KeywordTemplate,
+				skynewzdevv1alpha1.FeedTemplateInstance{Name: "hotd", Show: "House.of.the.Dragon", Season: "01", Schedule: releaseDays},
+			),
+			want: []*skynewzdevv1alpha1.Feed{
+				withSchedule(makeFeed("shows-hotd", "House.of.the.Dragon", "House.of.the.Dragon.S01&1080p", true, rootDirID), releaseDays),
+			},
+		},
+		{
+			name:     "invalid template",
+			template: makeTemplate("{{.Show", skynewzdevv1alpha1.FeedTemplateInstance{Name: "a", Show: "A"}),
+			wantErr:  true,
+		},
+		{
+			name:     "unknown placeholder",
+			template: makeTemplate("{{.Episode}}", skynewzdevv1alpha1.FeedTemplateInstance{Name: "a", Show: "A"}),
+			wantErr:  true,
+		},
+		{
+			name:     "missing value",
+			template: makeTemplate("{{.Values.quality}}", skynewzdevv1alpha1.FeedTemplateInstance{Name: "a", Show: "A"}),
+			wantErr:  true,
+		},
+		{
+			name:     "empty keyword",
+			template: makeTemplate("", skynewzdevv1alpha1.FeedTemplateInstance{Name: "a", Show: "A"}),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderTemplateFeeds(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("renderTemplateFeeds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("renderTemplateFeeds() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_findPrunableFeeds(t *testing.T) {
+	isController := true
+	feedTemplate := &skynewzdevv1alpha1.FeedTemplate{ObjectMeta: metav1.ObjectMeta{Name: "shows", UID: types.UID("template-uid")}}
+
+	makeFeed := func(name string, owner types.UID) skynewzdevv1alpha1.Feed {
+		feed := skynewzdevv1alpha1.Feed{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if owner != "" {
+			feed.OwnerReferences = []metav1.OwnerReference{{Name: "shows", UID: owner, Controller: &isController}}
+		}
+
+		return feed
+	}
+
+	feeds := []skynewzdevv1alpha1.Feed{
+		makeFeed("shows-kept", "template-uid"),
+		makeFeed("shows-removed", "template-uid"),
+		makeFeed("shows-other-owner", "other-uid"),
+		makeFeed("shows-not-owned", ""),
+	}
+
+	got := findPrunableFeeds(feedTemplate, feeds, []string{"shows-kept"})
+	if len(got) != 1 || got[0].Name != "shows-removed" {
+		names := make([]string, 0, len(got))
+		for _, f := range got {
+			names = append(names, f.Name)
+		}
+
+		t.Errorf("findPrunableFeeds() = %v, want [shows-removed]", names)
+	}
+}
+
+func TestFeedTemplateReconciler_applyFeed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = skynewzdevv1alpha1.AddToScheme(scheme)
+
+	feedTemplate := &skynewzdevv1alpha1.FeedTemplate{ObjectMeta: metav1.ObjectMeta{Name: "shows", Namespace: "default", UID: "template"}}
+	desired := &skynewzdevv1alpha1.Feed{
+		ObjectMeta: metav1.ObjectMeta{Name: "shows-andor", Namespace: "default"},
+		Spec:       skynewzdevv1alpha1.FeedSpec{Keyword: "Andor"},
+	}
+	controller := true
+
+	tests := []struct {
+		name       string
+		owners     []metav1.OwnerReference
+		wantErr    error
+		wantWrites int
+	}{
+		{
+			name:       "generated by the template",
+			owners:     []metav1.OwnerReference{{APIVersion: skynewzdevv1alpha1.GroupVersion.String(), Kind: "FeedTemplate", Name: "shows", UID: "template", Controller: &controller}},
+			wantErr:    nil,
+			wantWrites: 1,
+		},
+		{
+			name:       "created by the user",
+			owners:     nil,
+			wantErr:    errFeedNotOwnedByTemplate,
+			wantWrites: 0,
+		},
+		{
+			name:       "generated by another template",
+			owners:     []metav1.OwnerReference{{APIVersion: skynewzdevv1alpha1.GroupVersion.String(), Kind: "FeedTemplate", Name: "others", UID: "other", Controller: &controller}},
+			wantErr:    errFeedNotOwnedByTemplate,
+			wantWrites: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &feedClient{feed: &skynewzdevv1alpha1.Feed{
+				ObjectMeta: metav1.ObjectMeta{Name: "shows-andor", Namespace: "default", ResourceVersion: "1", OwnerReferences: tt.owners},
+				Spec:       skynewzdevv1alpha1.FeedSpec{Keyword: "Andor.S01"},
+			}}
+			r := &FeedTemplateReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+			if err := r.applyFeed(context.Background(), feedTemplate, desired); !errors.Is(err, tt.wantErr) {
+				t.Errorf("applyFeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if c.writes != tt.wantWrites {
+				t.Errorf("applyFeed() wrote the feed %d times, want %d", c.writes, tt.wantWrites)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// FolderReconciler reconciles a Folder object.
+type FolderReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// ResyncInterval is the period after which the folder path is resolved again,
+	// recreating it if it has been removed from Put.io. Zero disables periodic resync.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=folders,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=folders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=putioaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile ensures the folder path exists at Put.io and reports its file ID in status.
+func (r *FolderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FolderReconciler.Reconcile")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("folder.name", req.Name),
+		attribute.String("folder.namespace", req.Namespace),
+	)
+
+	logger := log.FromContext(ctx)
+
+	folder := new(skynewzdevv1alpha1.Folder)
+	if err := r.Get(ctx, req.NamespacedName, folder); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	// deleting a Folder leaves the Put.io folder untouched
+	if !folder.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	putioClient, err := r.makePutioClient(ctx, folder)
+	if err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Resolving Put.io folder", "path", folder.Spec.Path)
+	id, err := resolveFolderPath(ctx, putioClient.Files, folder.Spec.Path, true)
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(folder, corev1.EventTypeWarning, eventUnableToResolveFolder, err.Error())
+		meta.SetStatusCondition(&folder.Status.Conditions, makeFolderReadyCondition(metav1.ConditionFalse, FolderFailedToResolve, err.Error()))
+		if err := r.Status().Update(ctx, folder); err != nil {
+			logger.Error(err, "unable to update folder status")
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if folder.Status.FileID == nil || *folder.Status.FileID != id {
+		r.Recorder.Eventf(folder, corev1.EventTypeNormal, eventFolderResolved, "folder %q resolved to file ID %d", folder.Spec.Path, id)
+	}
+
+	folder.Status.FileID = &id
+	meta.SetStatusCondition(&folder.Status.Conditions, makeFolderReadyCondition(metav1.ConditionTrue, FolderResolved, ""))
+
+	if err := r.Status().Update(ctx, folder); err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err //nolint:wrapcheck
+	}
+
+	logger.Info("Folder successfully reconciled", "id", id)
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FolderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "controllers.FolderReconciler.SetupWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&skynewzdevv1alpha1.Folder{}).
+		Complete(r)
+}
+
+// makePutioClient authenticates with the folder account when referenced, with the folder secret otherwise.
+func (r *FolderReconciler) makePutioClient(ctx context.Context, folder *skynewzdevv1alpha1.Folder) (*putio.Client, error) {
+	switch {
+	case folder.Spec.AccountRef != nil:
+		putioClient, err := makePutioClientFromAccount(ctx, r, folder.Namespace, *folder.Spec.AccountRef)
+		if err != nil {
+			r.Recorder.Event(folder, corev1.EventTypeWarning, eventUnableToGetAccount, err.Error())
+		}
+
+		return putioClient, err
+	case folder.Spec.AuthSecretRef != nil:
+		putioClient, err := makePutioClientFromSecret(ctx, r, folder.Namespace, *folder.Spec.AuthSecretRef)
+		if err != nil {
+			r.Recorder.Event(folder, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
+		}
+
+		return putioClient, err
+	default:
+		r.Recorder.Event(folder, corev1.EventTypeWarning, eventUnableToGetAuthSecret, errMissingAuthentication.Error())
+		return nil, errMissingAuthentication
+	}
+}
+
+// resolveFolderPath walks given slash-separated path from the root directory and returns
+// the file ID of its last folder. Missing folders are created when create is true.
+func resolveFolderPath(ctx context.Context, files putio.FilesService, path string, create bool) (uint, error) {
+	ctx, span := tracer.Start(ctx, "controllers.resolveFolderPath")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("folder.path", path), attribute.Bool("folder.create", create))
+
+	var parentID uint // root directory
+	for _, name := range strings.Split(path, "/") {
+		if name == "" {
+			continue
+		}
+
+		children, err := files.List(ctx, parentID)
+		if err != nil {
+			span.RecordError(err)
+			return 0, fmt.Errorf("cannot list folder %d: %w", parentID, err)
+		}
+
+		folder := findFolder(children, name)
+		if folder == nil {
+			if !create {
+				span.RecordError(errFolderNotFound)
+				return 0, fmt.Errorf("cannot resolve %q: %q: %w", path, name, errFolderNotFound)
+			}
+
+			folder, err = files.CreateFolder(ctx, name, parentID)
+			if err != nil {
+				span.RecordError(err)
+				return 0, fmt.Errorf("cannot create folder %q: %w", name, err)
+			}
+		}
+
+		parentID = folder.ID
+	}
+
+	span.SetAttributes(attribute.Int("folder.id", int(parentID)))
+	return parentID, nil
+}
+
+// findFolder returns the folder named name among given files, nil when there is none.
+func findFolder(files []*putio.File, name string) *putio.File {
+	for _, f := range files {
+		if f.IsDir() && f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	goputio "github.com/putdotio/go-putio"
+)
+
+// fakeFilesService is an in-memory putio.FilesService.
+type fakeFilesService struct {
+	files   []*putio.File
+	trashed []uint
+	nextID  uint
+}
+
+func (s *fakeFilesService) List(_ context.Context, parentID uint) ([]*putio.File, error) {
+	children := make([]*putio.File, 0)
+	for _, f := range s.files {
+		if f.ParentID == parentID {
+			children = append(children, f)
+		}
+	}
+
+	return children, nil
+}
+
+func (s *fakeFilesService) Get(_ context.Context, id uint) (*putio.File, error) {
+	for _, f := range s.files {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+
+	return nil, &goputio.ErrorResponse{Type: "NotFound"}
+}
+
+func (s *fakeFilesService) CreateFolder(_ context.Context, name string, parentID uint) (*putio.File, error) {
+	s.nextID++
+	f := &putio.File{ID: s.nextID, Name: name, ParentID: parentID, ContentType: "application/x-directory"}
+	s.files = append(s.files, f)
+	return f, nil
+}
+
+func (s *fakeFilesService) Delete(_ context.Context, ids []uint) error {
+	kept := make([]*putio.File, 0, len(s.files))
+	for _, f := range s.files {
+		if !containsID(ids, f.ID) {
+			kept = append(kept, f)
+		}
+	}
+
+	s.files = kept
+	return nil
+}
+
+func (s *fakeFilesService) Trash(ctx context.Context, ids []uint) error {
+	s.trashed = append(s.trashed, ids...)
+	return s.Delete(ctx, ids)
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Test_resolveFolderPath(t *testing.T) {
+	makeFiles := func() *fakeFilesService {
+		return &fakeFilesService{
+			nextID: 100,
+			files: []*putio.File{
+				{ID: 1, Name: "TV Shows", ParentID: 0, ContentType: "application/x-directory"},
+				{ID: 2, Name: "House of the Dragon", ParentID: 1, ContentType: "application/x-directory"},
+				{ID: 3, Name: "Movies", ParentID: 0, ContentType: "video/x-matroska"},
+			},
+		}
+	}
+
+	type args struct {
+		path   string
+		create bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    uint
+		wantErr error
+	}{
+		{
+			name: "root directory",
+			args: args{path: "/", create: false},
+			want: 0,
+		},
+		{
+			name: "existing folder",
+			args: args{path: "TV Shows/House of the Dragon", create: false},
+			want: 2,
+		},
+		{
+			name: "existing folder with extra slashes",
+			args: args{path: "/TV Shows//House of the Dragon/", create: false},
+			want: 2,
+		},
+		{
+			name:    "missing folder",
+			args:    args{path: "TV Shows/For all mankind", create: false},
+			wantErr: errFolderNotFound,
+		},
+		{
+			name:    "file with the same name is not a folder",
+			args:    args{path: "Movies", create: false},
+			wantErr: errFolderNotFound,
+		},
+		{
+			name: "missing folders are created",
+			args: args{path: "TV Shows/For all mankind/Season 3", create: true},
+			want: 102,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveFolderPath(context.Background(), makeFiles(), tt.args.path, tt.args.create)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("resolveFolderPath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("resolveFolderPath() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
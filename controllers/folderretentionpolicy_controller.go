@@ -0,0 +1,384 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// maxListedPrunedFiles is the maximum number of pruned files listed in status.
+const maxListedPrunedFiles = 100
+
+// retentionRetryInterval delays the next run of a policy whose last run failed, unless its interval is shorter.
+const retentionRetryInterval = 5 * time.Minute
+
+// rules pruning a file, listed in status.
+const (
+	retentionReasonMaxAge       = "MaxAge"
+	retentionReasonKeepLast     = "KeepLast"
+	retentionReasonMaxTotalSize = "MaxTotalSize"
+)
+
+// FolderRetentionPolicyReconciler reconciles a FolderRetentionPolicy object.
+type FolderRetentionPolicyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=folderretentionpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=folderretentionpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=putioaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile prunes the folder content matching the policy rules every interval.
+func (r *FolderRetentionPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, "controllers.FolderRetentionPolicyReconciler.Reconcile")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("folderretentionpolicy.name", req.Name),
+		attribute.String("folderretentionpolicy.namespace", req.Namespace),
+	)
+
+	logger := log.FromContext(ctx)
+
+	policy := new(skynewzdevv1alpha1.FolderRetentionPolicy)
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	// deleting a policy leaves the folder content untouched
+	if !policy.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	if wait := untilNextRetentionRun(policy, now); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	if err := validateRetentionPolicy(policy.Spec); err != nil {
+		// the spec has to be fixed, retrying would not help
+		r.Recorder.Event(policy, corev1.EventTypeWarning, eventInvalidRetentionPolicy, err.Error())
+		return ctrl.Result{}, r.setRetentionPolicyFailed(ctx, policy, RetentionPolicyInvalid, err)
+	}
+
+	putioClient, err := r.makePutioClient(ctx, policy)
+	if err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	folderID, err := resolveRetentionFolder(ctx, putioClient.Files, policy.Spec)
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(policy, corev1.EventTypeWarning, eventUnableToResolveFolder, err.Error())
+		return r.retryRetentionRun(ctx, policy, RetentionPolicyFailedToResolve, err, now)
+	}
+
+	files, err := putioClient.Files.List(ctx, folderID)
+	if err != nil {
+		span.RecordError(err)
+		err = fmt.Errorf("cannot list folder %d: %w", folderID, err)
+		return r.retryRetentionRun(ctx, policy, RetentionPolicyFailedToResolve, err, now)
+	}
+
+	pruned := selectPrunableFiles(files, policy.Spec, now)
+	if len(pruned) > 0 && !policy.Spec.DryRun {
+		if err := pruneFiles(ctx, putioClient.Files, policy.Spec.Action, pruned); err != nil {
+			span.RecordError(err)
+			r.Recorder.Event(policy, corev1.EventTypeWarning, eventUnableToPruneFiles, err.Error())
+			return r.retryRetentionRun(ctx, policy, RetentionPolicyFailedToPrune, err, now)
+		}
+
+		r.Recorder.Eventf(policy, corev1.EventTypeNormal, eventFilesPruned, "%d files pruned from folder %d", len(pruned), folderID)
+	}
+
+	setRetentionPolicyStatus(policy, folderID, pruned, now)
+	if err := r.Status().Update(ctx, policy); err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err //nolint:wrapcheck
+	}
+
+	logger.Info("FolderRetentionPolicy successfully enforced", "folder", folderID, "pruned", len(pruned), "dryRun", policy.Spec.DryRun)
+	return ctrl.Result{RequeueAfter: policy.Spec.Interval.Duration}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FolderRetentionPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "controllers.FolderRetentionPolicyReconciler.SetupWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&skynewzdevv1alpha1.FolderRetentionPolicy{}).
+		Complete(r)
+}
+
+// makePutioClient authenticates with the policy account when referenced, with the policy secret otherwise.
+func (r *FolderRetentionPolicyReconciler) makePutioClient(ctx context.Context, policy *skynewzdevv1alpha1.FolderRetentionPolicy) (*putio.Client, error) {
+	switch {
+	case policy.Spec.AccountRef != nil:
+		putioClient, err := makePutioClientFromAccount(ctx, r, policy.Namespace, *policy.Spec.AccountRef)
+		if err != nil {
+			r.Recorder.Event(policy, corev1.EventTypeWarning, eventUnableToGetAccount, err.Error())
+		}
+
+		return putioClient, err
+	case policy.Spec.AuthSecretRef != nil:
+		putioClient, err := makePutioClientFromSecret(ctx, r, policy.Namespace, *policy.Spec.AuthSecretRef)
+		if err != nil {
+			r.Recorder.Event(policy, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
+		}
+
+		return putioClient, err
+	default:
+		r.Recorder.Event(policy, corev1.EventTypeWarning, eventUnableToGetAuthSecret, errMissingAuthentication.Error())
+		return nil, errMissingAuthentication
+	}
+}
+
+// setRetentionPolicyFailed reports given error in the Ready condition, and returns the error of the status update.
+func (r *FolderRetentionPolicyReconciler) setRetentionPolicyFailed(ctx context.Context, policy *skynewzdevv1alpha1.FolderRetentionPolicy, reason RetentionPolicyConditionReason, err error) error {
+	ctx, span := tracer.Start(ctx, "controllers.FolderRetentionPolicyReconciler.setRetentionPolicyFailed")
+	defer span.End()
+
+	meta.SetStatusCondition(&policy.Status.Conditions, makeRetentionPolicyReadyCondition(metav1.ConditionFalse, reason, err.Error()))
+	if err := r.Status().Update(ctx, policy); err != nil {
+		span.RecordError(err)
+		log.FromContext(ctx).Error(err, "unable to update folder retention policy status")
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// retryRetentionRun reports given error of the run started at now in the Ready condition, and retries the run
+// after retentionRetryInterval rather than right away, as the status update triggers another reconciliation.
+func (r *FolderRetentionPolicyReconciler) retryRetentionRun(ctx context.Context, policy *skynewzdevv1alpha1.FolderRetentionPolicy, reason RetentionPolicyConditionReason, err error, now time.Time) (ctrl.Result, error) {
+	policy.Status.LastRun = &metav1.Time{Time: now}
+	policy.Status.ObservedGeneration = policy.Generation
+	if err := r.setRetentionPolicyFailed(ctx, policy, reason, err); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: untilNextRetentionRun(policy, now)}, nil
+}
+
+// untilNextRetentionRun returns how long to wait before enforcing given policy again, zero when it is due.
+// A policy is enforced again right away when its spec changed, and after retentionRetryInterval when its last run failed.
+func untilNextRetentionRun(policy *skynewzdevv1alpha1.FolderRetentionPolicy, now time.Time) time.Duration {
+	if policy.Status.LastRun == nil || policy.Status.ObservedGeneration != policy.Generation {
+		return 0
+	}
+
+	interval := policy.Spec.Interval.Duration
+	if !meta.IsStatusConditionTrue(policy.Status.Conditions, string(RetentionPolicyReady)) && interval > retentionRetryInterval {
+		interval = retentionRetryInterval
+	}
+
+	wait := policy.Status.LastRun.Add(interval).Sub(now)
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+// validateRetentionPolicy ensures exactly one of folder_id and path is given and is not the root folder, that at least
+// one rule is given, and that globs and interval are valid.
+func validateRetentionPolicy(spec skynewzdevv1alpha1.FolderRetentionPolicySpec) error {
+	switch {
+	case spec.FolderID == nil && spec.Path == "":
+		return errMissingRetentionFolder
+	case spec.FolderID != nil && spec.Path != "":
+		return errRetentionFolderConflict
+	case spec.FolderID != nil && *spec.FolderID == 0, spec.FolderID == nil && strings.Trim(spec.Path, "/") == "":
+		return errRetentionRootFolder
+	case spec.MaxAge == nil && spec.KeepLast == nil && spec.MaxTotalSize == nil:
+		return errMissingRetentionRule
+	case spec.Interval.Duration <= 0:
+		return fmt.Errorf("interval %s: %w", spec.Interval.Duration, errInvalidRetentionInterval)
+	}
+
+	for _, pattern := range append(append([]string{}, spec.Include...), spec.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// resolveRetentionFolder returns the file ID of the folder given policy prunes.
+func resolveRetentionFolder(ctx context.Context, files putio.FilesService, spec skynewzdevv1alpha1.FolderRetentionPolicySpec) (uint, error) {
+	ctx, span := tracer.Start(ctx, "controllers.resolveRetentionFolder")
+	defer span.End()
+
+	if spec.FolderID != nil {
+		return *spec.FolderID, nil
+	}
+
+	return resolveFolderPath(ctx, files, spec.Path, false)
+}
+
+// selectPrunableFiles returns the files of the folder the policy rules prune, the oldest first.
+// Every rule applies to the files matching include and exclude only, from the most recent one:
+// a file is pruned when it is older than max_age, beyond the keep_last most recent files,
+// or when keeping it would exceed max_total_size.
+func selectPrunableFiles(files []*putio.File, spec skynewzdevv1alpha1.FolderRetentionPolicySpec, now time.Time) []skynewzdevv1alpha1.PrunedFile {
+	candidates := make([]*putio.File, 0, len(files))
+	for _, f := range files {
+		if matchesAnyGlob(spec.Include, f.Name, true) && !matchesAnyGlob(spec.Exclude, f.Name, false) {
+			candidates = append(candidates, f)
+		}
+	}
+
+	// most recent first, by ID when created at the same time
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if !candidates[i].CreatedAt.Equal(candidates[j].CreatedAt.Time) {
+			return candidates[i].CreatedAt.After(candidates[j].CreatedAt.Time)
+		}
+
+		return candidates[i].ID > candidates[j].ID
+	})
+
+	var (
+		pruned   = make([]skynewzdevv1alpha1.PrunedFile, 0)
+		keptSize int64
+		kept     int32
+	)
+
+	for _, f := range candidates {
+		var reason string
+		switch {
+		case spec.MaxAge != nil && !f.CreatedAt.IsZero() && now.Sub(f.CreatedAt.Time) > spec.MaxAge.Duration:
+			reason = retentionReasonMaxAge
+		case spec.KeepLast != nil && kept >= *spec.KeepLast:
+			reason = retentionReasonKeepLast
+		case spec.MaxTotalSize != nil && keptSize+f.Size > spec.MaxTotalSize.Value():
+			reason = retentionReasonMaxTotalSize
+		}
+
+		if reason == "" {
+			kept++
+			keptSize += f.Size
+			continue
+		}
+
+		pruned = append(pruned, skynewzdevv1alpha1.PrunedFile{
+			ID:        f.ID,
+			Name:      f.Name,
+			Size:      f.Size,
+			CreatedAt: makeStatusTime(f.CreatedAt),
+			Reason:    reason,
+		})
+	}
+
+	// oldest first
+	for i, j := 0, len(pruned)-1; i < j; i, j = i+1, j-1 {
+		pruned[i], pruned[j] = pruned[j], pruned[i]
+	}
+
+	return pruned
+}
+
+// matchesAnyGlob tells whether name matches one of given globs, returning fallback when there is none.
+func matchesAnyGlob(globs []string, name string, fallback bool) bool {
+	if len(globs) == 0 {
+		return fallback
+	}
+
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pruneFiles moves given files to the trash, or deletes them permanently.
+func pruneFiles(ctx context.Context, files putio.FilesService, action skynewzdevv1alpha1.RetentionAction, pruned []skynewzdevv1alpha1.PrunedFile) error {
+	ctx, span := tracer.Start(ctx, "controllers.pruneFiles")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("action", string(action)), attribute.Int("count", len(pruned)))
+
+	ids := make([]uint, len(pruned))
+	for i, f := range pruned {
+		ids[i] = f.ID
+	}
+
+	var err error
+	if action == skynewzdevv1alpha1.RetentionActionDelete {
+		err = files.Delete(ctx, ids)
+	} else {
+		err = files.Trash(ctx, ids)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot prune %d files: %w", len(ids), err)
+	}
+
+	return nil
+}
+
+// setRetentionPolicyStatus reports the outcome of a successful run of given policy.
+func setRetentionPolicyStatus(policy *skynewzdevv1alpha1.FolderRetentionPolicy, folderID uint, pruned []skynewzdevv1alpha1.PrunedFile, now time.Time) {
+	var size int64
+	for _, f := range pruned {
+		size += f.Size
+	}
+
+	policy.Status.FolderID = &folderID
+	policy.Status.LastRun = &metav1.Time{Time: now}
+	policy.Status.ObservedGeneration = policy.Generation
+	policy.Status.PrunedCount = len(pruned)
+	policy.Status.PrunedSize = size
+	policy.Status.Pruned = pruned
+	if len(pruned) > maxListedPrunedFiles {
+		policy.Status.Pruned = pruned[:maxListedPrunedFiles]
+	}
+
+	reason, message := RetentionPolicyEnforced, fmt.Sprintf("%d files pruned, %d bytes freed", len(pruned), size)
+	if policy.Spec.DryRun {
+		reason, message = RetentionPolicyDryRun, fmt.Sprintf("%d files would be pruned, freeing %d bytes", len(pruned), size)
+	}
+
+	meta.SetStatusCondition(&policy.Status.Conditions, makeRetentionPolicyReadyCondition(metav1.ConditionTrue, reason, message))
+}
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_selectPrunableFiles(t *testing.T) {
+	now := time.Date(2022, time.October, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) putio.Time {
+		return putio.Time{Time: now.AddDate(0, 0, -days)}
+	}
+
+	files := []*putio.File{
+		{ID: 1, Name: "S01E01.mkv", Size: 4, CreatedAt: daysAgo(40)},
+		{ID: 2, Name: "S01E02.mkv", Size: 4, CreatedAt: daysAgo(20)},
+		{ID: 3, Name: "S01E03.mkv", Size: 4, CreatedAt: daysAgo(10)},
+		{ID: 4, Name: "S01E04", Size: 6, CreatedAt: daysAgo(1), ContentType: "application/x-directory"},
+		{ID: 5, Name: "notes.txt", Size: 1, CreatedAt: daysAgo(60)},
+	}
+
+	keepLast := int32(2)
+	maxTotalSize := resource.MustParse("12")
+
+	tests := []struct {
+		name string
+		spec skynewzdevv1alpha1.FolderRetentionPolicySpec
+		want map[uint]string
+	}{
+		{
+			name: "no rule",
+			spec: skynewzdevv1alpha1.FolderRetentionPolicySpec{},
+			want: map[uint]string{},
+		},
+		{
+			name: "max age",
+			spec: skynewzdevv1alpha1.FolderRetentionPolicySpec{MaxAge: &metav1.Duration{Duration: 30 * 24 * time.Hour}},
+			want: map[uint]string{1: retentionReasonMaxAge, 5: retentionReasonMaxAge},
+		},
+		{
+			name: "keep last",
+			spec: skynewzdevv1alpha1.FolderRetentionPolicySpec{KeepLast: &keepLast},
+			want: map[uint]string{1: retentionReasonKeepLast, 2: retentionReasonKeepLast, 5: retentionReasonKeepLast},
+		},
+		{
+			name: "max total size",
+			spec: skynewzdevv1alpha1.FolderRetentionPolicySpec{MaxTotalSize: &maxTotalSize},
+			want: map[uint]string{1: retentionReasonMaxTotalSize, 2: retentionReasonMaxTotalSize},
+		},
+		{
+			name: "include and exclude",
+			spec: skynewzdevv1alpha1.FolderRetentionPolicySpec{
+				MaxAge:  &metav1.Duration{Duration: 5 * 24 * time.Hour},
+				Include: []string{"*.mkv"},
+				Exclude: []string{"S01E01*"},
+			},
+			want: map[uint]string{2: retentionReasonMaxAge, 3: retentionReasonMaxAge},
+		},
+		{
+			name: "size kept by the other rules only",
+			spec: skynewzdevv1alpha1.FolderRetentionPolicySpec{
+				MaxAge:       &metav1.Duration{Duration: 15 * 24 * time.Hour},
+				MaxTotalSize: &maxTotalSize,
+			},
+			want: map[uint]string{1: retentionReasonMaxAge, 2: retentionReasonMaxAge, 5: retentionReasonMaxAge},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pruned := selectPrunableFiles(files, tt.spec, now)
+
+			got := make(map[uint]string, len(pruned))
+			for i, f := range pruned {
+				got[f.ID] = f.Reason
+				if i > 0 && pruned[i-1].CreatedAt.After(f.CreatedAt.Time) {
+					t.Errorf("selectPrunableFiles() not sorted oldest first: %d before %d", pruned[i-1].ID, f.ID)
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("selectPrunableFiles() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_validateRetentionPolicy(t *testing.T) {
+	interval := metav1.Duration{Duration: time.Hour}
+	maxAge := &metav1.Duration{Duration: 30 * 24 * time.Hour}
+	tests := []struct {
+		name    string
+		spec    skynewzdevv1alpha1.FolderRetentionPolicySpec
+		wantErr error
+	}{
+		{
+			name:    "folder ID",
+			spec:    skynewzdevv1alpha1.FolderRetentionPolicySpec{FolderID: uintToPtr(42), MaxAge: maxAge, Interval: interval},
+			wantErr: nil,
+		},
+		{
+			name:    "path",
+			spec:    skynewzdevv1alpha1.FolderRetentionPolicySpec{Path: "TV Shows", MaxAge: maxAge, Interval: interval, Include: []string{"*.mkv"}},
+			wantErr: nil,
+		},
+		{
+			name:    "no folder",
+			spec:    skynewzdevv1alpha1.FolderRetentionPolicySpec{MaxAge: maxAge, Interval: interval},
+			wantErr: errMissingRetentionFolder,
+		},
+		{
+			name:    "folder ID and path",
+			spec:    skynewzdevv1alpha1.FolderRetentionPolicySpec{FolderID: uintToPtr(42), Path: "TV Shows", MaxAge: maxAge, Interval: interval},
+			wantErr: errRetentionFolderConflict,
+		},
+		{
+			name:    "root folder ID",
+			spec:    skynewzdevv1alpha1.FolderRetentionPolicySpec{FolderID: uintToPtr(0), MaxAge: maxAge, Interval: interval},
+			wantErr: errRetentionRootFolder,
+		},
+		{
+			name:    "root path",
+			spec:    skynewzdevv1alpha1.FolderRetentionPolicySpec{Path: "/", MaxAge: maxAge, Interval: interval},
+			wantErr: errRetentionRootFolder,
+		},
+		{
+			name:    "no rule",
+			spec:    skynewzdevv1alpha1.FolderRetentionPolicySpec{Path: "TV Shows", Interval: interval},
+			wantErr: errMissingRetentionRule,
+		},
+		{
+			name:    "no interval",
+			spec:    skynewzdevv1alpha1.FolderRetentionPolicySpec{Path: "TV Shows", MaxAge: maxAge},
+			wantErr: errInvalidRetentionInterval,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRetentionPolicy(tt.spec); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateRetentionPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("invalid glob", func(t *testing.T) {
+		spec := skynewzdevv1alpha1.FolderRetentionPolicySpec{Path: "TV Shows", MaxAge: maxAge, Interval: interval, Exclude: []string{"[a-"}}
+		if err := validateRetentionPolicy(spec); err == nil {
+			t.Errorf("validateRetentionPolicy() error = nil, want an invalid glob error")
+		}
+	})
+}
+
+func Test_untilNextRetentionRun(t *testing.T) {
+	now := time.Date(2022, time.October, 1, 12, 0, 0, 0, time.UTC)
+	ready := []metav1.Condition{makeRetentionPolicyReadyCondition(metav1.ConditionTrue, RetentionPolicyEnforced, "")}
+	failed := []metav1.Condition{makeRetentionPolicyReadyCondition(metav1.ConditionFalse, RetentionPolicyFailedToPrune, "")}
+
+	makePolicy := func(generation, observed int64, lastRun *metav1.Time, conditions []metav1.Condition) *skynewzdevv1alpha1.FolderRetentionPolicy {
+		return &skynewzdevv1alpha1.FolderRetentionPolicy{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Spec:       skynewzdevv1alpha1.FolderRetentionPolicySpec{Interval: metav1.Duration{Duration: time.Hour}},
+			Status: skynewzdevv1alpha1.FolderRetentionPolicyStatus{
+				LastRun:            lastRun,
+				ObservedGeneration: observed,
+				Conditions:         conditions,
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		policy *skynewzdevv1alpha1.FolderRetentionPolicy
+		want   time.Duration
+	}{
+		{
+			name:   "never run",
+			policy: makePolicy(1, 0, nil, nil),
+			want:   0,
+		},
+		{
+			name:   "run recently",
+			policy: makePolicy(1, 1, &metav1.Time{Time: now.Add(-20 * time.Minute)}, ready),
+			want:   40 * time.Minute,
+		},
+		{
+			name:   "interval elapsed",
+			policy: makePolicy(1, 1, &metav1.Time{Time: now.Add(-2 * time.Hour)}, ready),
+			want:   0,
+		},
+		{
+			name:   "spec changed",
+			policy: makePolicy(2, 1, &metav1.Time{Time: now.Add(-20 * time.Minute)}, ready),
+			want:   0,
+		},
+		{
+			name:   "last run failed",
+			policy: makePolicy(1, 1, &metav1.Time{Time: now.Add(-20 * time.Minute)}, failed),
+			want:   0,
+		},
+		{
+			name:   "last run failed recently",
+			policy: makePolicy(1, 1, &metav1.Time{Time: now.Add(-2 * time.Minute)}, failed),
+			want:   3 * time.Minute,
+		},
+		{
+			name:   "last run failed, spec changed",
+			policy: makePolicy(2, 1, &metav1.Time{Time: now.Add(-2 * time.Minute)}, failed),
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := untilNextRetentionRun(tt.policy, now); got != tt.want {
+				t.Errorf("untilNextRetentionRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pruneFiles(t *testing.T) {
+	pruned := []skynewzdevv1alpha1.PrunedFile{{ID: 1}, {ID: 2}}
+	tests := []struct {
+		name        string
+		action      skynewzdevv1alpha1.RetentionAction
+		wantTrashed []uint
+	}{
+		{
+			name:        "trash",
+			action:      skynewzdevv1alpha1.RetentionActionTrash,
+			wantTrashed: []uint{1, 2},
+		},
+		{
+			name:        "delete",
+			action:      skynewzdevv1alpha1.RetentionActionDelete,
+			wantTrashed: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := &fakeFilesService{files: []*putio.File{{ID: 1}, {ID: 2}, {ID: 3}}}
+			if err := pruneFiles(context.Background(), files, tt.action, pruned); err != nil {
+				t.Fatalf("pruneFiles() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantTrashed, files.trashed); diff != "" {
+				t.Errorf("pruneFiles() trashed mismatch (-want +got):\n%s", diff)
+			}
+
+			if len(files.files) != 1 || files.files[0].ID != 3 {
+				t.Errorf("pruneFiles() left %v, want only file 3", files.files)
+			}
+		})
+	}
+}
@@ -0,0 +1,616 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/notifier"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// DefaultAlertTemplate renders the notification messages of Alerts not given a template.
+const DefaultAlertTemplate = "[{{ .Feed.Namespace }}/{{ .Feed.Name }}] {{ .Message }}"
+
+const (
+	// notificationQueueSize is the number of feed events waiting to be dispatched, the next ones are dropped.
+	notificationQueueSize = 100
+
+	// alertQueueSize is the number of notifications of an Alert waiting to be delivered, the next ones are dropped.
+	alertQueueSize = 20
+
+	// notificationTimeout bounds the delivery of a notification.
+	notificationTimeout = 10 * time.Second
+
+	// defaultStaleAfter is used by Alerts not given stale_after.
+	defaultStaleAfter = 24 * time.Hour
+)
+
+// reasons a notification is dropped for.
+const (
+	dropReasonQueueFull      = "queue_full"
+	dropReasonAlertQueueFull = "alert_queue_full"
+	dropReasonRateLimited    = "rate_limited"
+)
+
+var droppedNotifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "putio_notifications_dropped_total",
+	Help: "Feed events and notifications dropped by the notification dispatcher, per namespace and reason.",
+}, []string{"namespace", "reason"})
+
+func init() {
+	metrics.Registry.MustRegister(droppedNotifications)
+}
+
+var (
+	_ manager.Runnable               = &NotificationDispatcher{}
+	_ manager.LeaderElectionRunnable = &NotificationDispatcher{}
+)
+
+// NotificationDispatcher watches Feeds and sends a notification to the provider of each Alert subscribing to their
+// events: transitions of the Available condition, new errors, new completed items, and Feeds going stale.
+// Each Alert delivers its notifications from its own goroutine, so that a slow provider does not delay the others.
+type NotificationDispatcher struct {
+	client.Client
+	Recorder record.EventRecorder
+
+	// Informers provides the Feed informer whose updates are turned into events.
+	Informers cache.Informers
+
+	// StaleCheckInterval between two looks for stale Feeds. Zero disables Stale events.
+	StaleCheckInterval time.Duration
+
+	// HTTPClient posts webhook notifications. Default to a client tracing requests.
+	HTTPClient *http.Client
+
+	logger   logr.Logger
+	events   chan feedEvent
+	limiters map[types.UID]*alertLimiter
+	workers  map[types.UID]*alertWorker
+	wg       sync.WaitGroup
+
+	// stale holds the alert and feed pairs already notified about a stale feed, until it is fetched again.
+	stale map[string]bool
+}
+
+// feedEvent is something that happened to a Feed Alerts may notify about.
+type feedEvent struct {
+	event    skynewzdevv1alpha1.AlertEventType
+	severity notifier.Severity
+	message  string
+	feed     *skynewzdevv1alpha1.Feed
+	at       time.Time
+}
+
+// alertLimiter rate limits the notifications of an Alert generation.
+type alertLimiter struct {
+	generation int64
+	limiter    *rate.Limiter
+}
+
+// alertWorker delivers the notifications of an Alert queued by the dispatcher.
+type alertWorker struct {
+	namespace     string
+	notifications chan alertNotification
+	stop          context.CancelFunc
+}
+
+// alertNotification is the notification of a feed event to deliver for an Alert.
+type alertNotification struct {
+	alert *skynewzdevv1alpha1.Alert
+	event feedEvent
+
+	// link to the span which queued the notification.
+	link trace.Link
+}
+
+// alertTemplateData is given to the template of Alerts.
+type alertTemplateData struct {
+	// Feed the event is about.
+	Feed *skynewzdevv1alpha1.Feed
+	// Event type.
+	Event skynewzdevv1alpha1.AlertEventType
+	// Severity of the event, info or error.
+	Severity notifier.Severity
+	// Message describing the event.
+	Message string
+}
+
+// Start dispatches feed events until given context is done.
+func (d *NotificationDispatcher) Start(ctx context.Context) error {
+	d.logger = log.FromContext(ctx).WithName("notification-dispatcher")
+	ctx = log.IntoContext(ctx, d.logger)
+
+	if d.HTTPClient == nil {
+		d.HTTPClient = &http.Client{Transport: otelhttp.NewTransport(nil)}
+	}
+
+	d.events = make(chan feedEvent, notificationQueueSize)
+	d.limiters = make(map[types.UID]*alertLimiter)
+	d.workers = make(map[types.UID]*alertWorker)
+	d.stale = make(map[string]bool)
+
+	// workers are stopped along with the dispatcher, which waits for them to return
+	defer d.wg.Wait()
+	defer d.stopWorkers("", nil)
+
+	informer, err := d.Informers.GetInformer(ctx, &skynewzdevv1alpha1.Feed{})
+	if err != nil {
+		return fmt.Errorf("cannot get feed informer: %w", err)
+	}
+
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{UpdateFunc: d.enqueueFeedEvents})
+
+	var staleCheck <-chan time.Time
+	if d.StaleCheckInterval > 0 {
+		ticker := time.NewTicker(d.StaleCheckInterval)
+		defer ticker.Stop()
+		staleCheck = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-d.events:
+			d.dispatch(ctx, e)
+		case now := <-staleCheck:
+			if err := d.checkStale(ctx, now); err != nil {
+				d.logger.Error(err, "unable to look for stale feeds")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only one replica sends notifications.
+func (d *NotificationDispatcher) NeedLeaderElection() bool {
+	return true
+}
+
+// enqueueFeedEvents queues the events of a feed update, dropping them when the queue is full.
+func (d *NotificationDispatcher) enqueueFeedEvents(oldObj, newObj interface{}) {
+	oldFeed, ok := oldObj.(*skynewzdevv1alpha1.Feed)
+	if !ok {
+		return
+	}
+
+	newFeed, ok := newObj.(*skynewzdevv1alpha1.Feed)
+	if !ok {
+		return
+	}
+
+	for _, e := range diffFeedEvents(oldFeed, newFeed, time.Now()) {
+		select {
+		case d.events <- e:
+		default:
+			d.logger.Info("Notification queue full, dropping feed event", "feed", client.ObjectKeyFromObject(newFeed), "event", e.event)
+			droppedNotifications.WithLabelValues(newFeed.Namespace, dropReasonQueueFull).Inc()
+			d.Recorder.Eventf(newFeed, corev1.EventTypeWarning, eventNotificationDropped, "%s event dropped, the notification queue is full", e.event)
+		}
+	}
+}
+
+// dispatch notifies every alert of the feed namespace matching given event.
+func (d *NotificationDispatcher) dispatch(ctx context.Context, e feedEvent) {
+	ctx, span := tracer.Start(ctx, "controllers.NotificationDispatcher.dispatch")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("feed.name", e.feed.Name),
+		attribute.String("feed.namespace", e.feed.Namespace),
+		attribute.String("event", string(e.event)),
+	)
+
+	alerts := new(skynewzdevv1alpha1.AlertList)
+	if err := d.List(ctx, alerts, client.InNamespace(e.feed.Namespace)); err != nil {
+		span.RecordError(err)
+		d.logger.Error(err, "unable to list alerts", "namespace", e.feed.Namespace)
+		return
+	}
+
+	d.stopWorkers(e.feed.Namespace, alerts.Items)
+	for i := range alerts.Items {
+		alert := &alerts.Items[i]
+		if alertMatches(alert, e.feed, e.event) {
+			d.notify(ctx, alert, e)
+		}
+	}
+}
+
+// checkStale notifies the alerts subscribing to Stale events about the feeds which became stale since the last check.
+func (d *NotificationDispatcher) checkStale(ctx context.Context, now time.Time) error {
+	ctx, span := tracer.Start(ctx, "controllers.NotificationDispatcher.checkStale")
+	defer span.End()
+
+	alerts := new(skynewzdevv1alpha1.AlertList)
+	if err := d.List(ctx, alerts); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot list alerts: %w", err)
+	}
+
+	d.pruneLimiters(alerts.Items)
+	d.stopWorkers("", alerts.Items)
+
+	stale := make(map[string]bool)
+	for i := range alerts.Items {
+		alert := &alerts.Items[i]
+		if alert.Spec.Suspend || !alert.Subscribes(skynewzdevv1alpha1.AlertEventStale) {
+			continue
+		}
+
+		feeds := new(skynewzdevv1alpha1.FeedList)
+		if err := d.List(ctx, feeds, client.InNamespace(alert.Namespace)); err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("cannot list feeds: %w", err)
+		}
+
+		staleAfter := alertStaleAfter(alert)
+		for j := range feeds.Items {
+			feed := &feeds.Items[j]
+			if !alertMatches(alert, feed, skynewzdevv1alpha1.AlertEventStale) || !isFeedStale(feed, staleAfter, now) {
+				continue
+			}
+
+			key := string(alert.UID) + "/" + string(feed.UID)
+			stale[key] = true
+			if !d.stale[key] {
+				d.notify(ctx, alert, makeStaleEvent(feed, now))
+			}
+		}
+	}
+
+	// feeds fetched again, deleted or no longer selected are forgotten
+	d.stale = stale
+	return nil
+}
+
+// notify queues the notification of given event for the worker of given alert, unless its rate limit is exceeded or
+// its queue is full.
+func (d *NotificationDispatcher) notify(ctx context.Context, alert *skynewzdevv1alpha1.Alert, e feedEvent) {
+	ctx, span := tracer.Start(ctx, "controllers.NotificationDispatcher.notify")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("alert.name", alert.Name),
+		attribute.String("alert.namespace", alert.Namespace),
+	)
+
+	logger := d.logger.WithValues("alert", client.ObjectKeyFromObject(alert), "feed", e.feed.Name, "event", e.event)
+
+	if !d.allow(alert, e.at) {
+		logger.Info("Notification rate limited")
+		droppedNotifications.WithLabelValues(alert.Namespace, dropReasonRateLimited).Inc()
+		d.Recorder.Eventf(alert, corev1.EventTypeWarning, eventNotificationRateLimited, "%s notification about feed %q dropped", e.event, e.feed.Name)
+		return
+	}
+
+	select {
+	case d.workerFor(alert).notifications <- alertNotification{alert: alert, event: e, link: trace.LinkFromContext(ctx)}:
+	default:
+		logger.Info("Alert queue full, dropping notification")
+		droppedNotifications.WithLabelValues(alert.Namespace, dropReasonAlertQueueFull).Inc()
+		d.Recorder.Eventf(alert, corev1.EventTypeWarning, eventNotificationDropped, "%s notification about feed %q dropped, the provider is not keeping up", e.event, e.feed.Name)
+	}
+}
+
+// workerFor returns the worker of given alert, started on its first notification.
+// Workers run until their alert is deleted or the dispatcher stops.
+func (d *NotificationDispatcher) workerFor(alert *skynewzdevv1alpha1.Alert) *alertWorker {
+	if w, ok := d.workers[alert.UID]; ok {
+		return w
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	w := &alertWorker{namespace: alert.Namespace, notifications: make(chan alertNotification, alertQueueSize), stop: stop}
+	d.workers[alert.UID] = w
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case queued := <-w.notifications:
+				d.deliver(ctx, queued)
+			}
+		}
+	}()
+
+	return w
+}
+
+// stopWorkers stops the workers of the deleted alerts of given namespace, of every namespace when empty.
+func (d *NotificationDispatcher) stopWorkers(namespace string, alerts []skynewzdevv1alpha1.Alert) {
+	existing := make(map[types.UID]bool, len(alerts))
+	for _, alert := range alerts {
+		existing[alert.UID] = true
+	}
+
+	for uid, w := range d.workers {
+		if (namespace == "" || w.namespace == namespace) && !existing[uid] {
+			w.stop()
+			delete(d.workers, uid)
+		}
+	}
+}
+
+// deliver sends given notification to the provider of its alert.
+// Failures are reported as events of the alert, notifications are not retried.
+func (d *NotificationDispatcher) deliver(ctx context.Context, queued alertNotification) {
+	ctx, span := tracer.Start(ctx, "controllers.NotificationDispatcher.deliver", trace.WithLinks(queued.link))
+	defer span.End()
+
+	alert, e := queued.alert, queued.event
+
+	span.SetAttributes(
+		attribute.String("alert.name", alert.Name),
+		attribute.String("alert.namespace", alert.Namespace),
+	)
+
+	logger := d.logger.WithValues("alert", client.ObjectKeyFromObject(alert), "feed", e.feed.Name, "event", e.event)
+
+	n, err := renderNotification(alert, e)
+	if err != nil {
+		span.RecordError(err)
+		d.Recorder.Event(alert, corev1.EventTypeWarning, eventInvalidAlert, err.Error())
+		return
+	}
+
+	provider := new(skynewzdevv1alpha1.NotificationProvider)
+	if err := d.Get(ctx, types.NamespacedName{Name: alert.Spec.ProviderRef.Name, Namespace: alert.Namespace}, provider); err != nil {
+		span.RecordError(err)
+		d.Recorder.Eventf(alert, corev1.EventTypeWarning, eventUnableToSendNotification, "cannot get provider %q: %s", alert.Spec.ProviderRef.Name, err)
+		return
+	}
+
+	sender, err := makeNotifier(ctx, d, d.HTTPClient, provider)
+	if err != nil {
+		span.RecordError(err)
+		d.Recorder.Event(alert, corev1.EventTypeWarning, eventUnableToSendNotification, err.Error())
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	if err := sender.Notify(sendCtx, n); err != nil {
+		span.RecordError(err)
+		logger.Error(err, "unable to send notification")
+		d.Recorder.Event(alert, corev1.EventTypeWarning, eventUnableToSendNotification, err.Error())
+		return
+	}
+
+	logger.Info("Notification sent")
+	alert.Status.LastSent = &metav1.Time{Time: e.at}
+	if err := d.Status().Update(ctx, alert); err != nil {
+		// only the last sent time is lost
+		logger.Error(err, "unable to update alert status")
+	}
+}
+
+// allow tells whether given alert may send one more notification at given time.
+// Limiters are reset when the alert spec changes.
+func (d *NotificationDispatcher) allow(alert *skynewzdevv1alpha1.Alert, now time.Time) bool {
+	limit := alert.Spec.RateLimit
+	if limit == nil {
+		return true
+	}
+
+	l, ok := d.limiters[alert.UID]
+	if !ok || l.generation != alert.Generation {
+		l = &alertLimiter{generation: alert.Generation, limiter: rate.NewLimiter(rate.Every(limit.Every.Duration), int(limit.Burst))}
+		d.limiters[alert.UID] = l
+	}
+
+	return l.limiter.AllowN(now, 1)
+}
+
+// pruneLimiters forgets the limiters of deleted alerts.
+func (d *NotificationDispatcher) pruneLimiters(alerts []skynewzdevv1alpha1.Alert) {
+	existing := make(map[types.UID]bool, len(alerts))
+	for _, alert := range alerts {
+		existing[alert.UID] = true
+	}
+
+	for uid := range d.limiters {
+		if !existing[uid] {
+			delete(d.limiters, uid)
+		}
+	}
+}
+
+// diffFeedEvents returns the events Alerts may notify about between two versions of a feed.
+// Feeds being previewed or deleted have no event.
+func diffFeedEvents(oldFeed, newFeed *skynewzdevv1alpha1.Feed, now time.Time) []feedEvent {
+	if newFeed.Spec.Preview || !newFeed.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	var (
+		events = make([]feedEvent, 0)
+		feed   = newFeed.DeepCopy()
+	)
+
+	oldAvailable := meta.FindStatusCondition(oldFeed.Status.Conditions, string(FeedAvailable))
+	newAvailable := meta.FindStatusCondition(newFeed.Status.Conditions, string(FeedAvailable))
+	if newAvailable != nil && (oldAvailable == nil || oldAvailable.Status != newAvailable.Status) {
+		e := feedEvent{event: skynewzdevv1alpha1.AlertEventAvailability, severity: notifier.SeverityInfo, message: "feed is available", feed: feed, at: now}
+		if newAvailable.Status != metav1.ConditionTrue {
+			e.severity = notifier.SeverityError
+			e.message = fmt.Sprintf("feed is unavailable (%s)", newAvailable.Reason)
+			if newAvailable.Message != "" {
+				e.message = fmt.Sprintf("feed is unavailable: %s", newAvailable.Message)
+			}
+		}
+
+		events = append(events, e)
+	}
+
+	if lastError := newFeed.Status.LastError; lastError != "" && lastError != oldFeed.Status.LastError {
+		events = append(events, feedEvent{
+			event:    skynewzdevv1alpha1.AlertEventError,
+			severity: notifier.SeverityError,
+			message:  fmt.Sprintf("Put.io reported an error: %s", lastError),
+			feed:     feed,
+			at:       now,
+		})
+	}
+
+	// items counted when the operator starts tracking a feed were completed before, they are not notified about
+	if completed := newFeed.Status.CompletedItemCount - oldFeed.Status.CompletedItemCount; completed > 0 && oldFeed.Status.LastItemID != nil {
+		message := fmt.Sprintf("%q downloaded", newFeed.Status.LastCompletedItem)
+		if completed > 1 {
+			message = fmt.Sprintf("%d new items downloaded, the last one is %q", completed, newFeed.Status.LastCompletedItem)
+		}
+
+		events = append(events, feedEvent{event: skynewzdevv1alpha1.AlertEventDownload, severity: notifier.SeverityInfo, message: message, feed: feed, at: now})
+	}
+
+	return events
+}
+
+// makeStaleEvent returns the Stale event of given feed at given time.
+func makeStaleEvent(feed *skynewzdevv1alpha1.Feed, now time.Time) feedEvent {
+	message := "feed was never fetched by Put.io"
+	if feed.Status.LastFetch != nil {
+		message = fmt.Sprintf("feed not fetched by Put.io for %s", now.Sub(feed.Status.LastFetch.Time).Round(time.Minute))
+	}
+
+	return feedEvent{
+		event:    skynewzdevv1alpha1.AlertEventStale,
+		severity: notifier.SeverityError,
+		message:  message,
+		feed:     feed.DeepCopy(),
+		at:       now,
+	}
+}
+
+// isFeedStale tells whether Put.io did not fetch given running feed for given duration, since its creation when
+// it was never fetched.
+func isFeedStale(feed *skynewzdevv1alpha1.Feed, staleAfter time.Duration, now time.Time) bool {
+	if feed.Spec.Preview || feed.Status.ID == nil || !feed.DeletionTimestamp.IsZero() || isFeedPaused(feed) {
+		return false
+	}
+
+	since := feed.CreationTimestamp.Time
+	if feed.Status.LastFetch != nil {
+		since = feed.Status.LastFetch.Time
+	}
+
+	return now.Sub(since) >= staleAfter
+}
+
+// alertStaleAfter returns the duration after which feeds are stale for given alert.
+func alertStaleAfter(alert *skynewzdevv1alpha1.Alert) time.Duration {
+	if alert.Spec.StaleAfter.Duration <= 0 {
+		return defaultStaleAfter
+	}
+
+	return alert.Spec.StaleAfter.Duration
+}
+
+// alertMatches tells whether given alert notifies about given event of given feed.
+// Alerts without feed selector match every feed of their namespace, those with an invalid one match none.
+func alertMatches(alert *skynewzdevv1alpha1.Alert, feed *skynewzdevv1alpha1.Feed, event skynewzdevv1alpha1.AlertEventType) bool {
+	if alert.Spec.Suspend || !alert.DeletionTimestamp.IsZero() || alert.Namespace != feed.Namespace || !alert.Subscribes(event) {
+		return false
+	}
+
+	if alert.Spec.FeedSelector == nil {
+		return true
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(alert.Spec.FeedSelector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(feed.Labels))
+}
+
+// anyAlertMatches tells whether one of given alerts notifies about given event of given feed.
+func anyAlertMatches(alerts []skynewzdevv1alpha1.Alert, feed *skynewzdevv1alpha1.Feed, event skynewzdevv1alpha1.AlertEventType) bool {
+	for i := range alerts {
+		if alertMatches(&alerts[i], feed, event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseAlertTemplate parses given alert template, the default one when empty.
+func parseAlertTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultAlertTemplate
+	}
+
+	tmpl, err := template.New("alert").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse alert template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// renderNotification returns the notification of given event rendered with the template of given alert.
+func renderNotification(alert *skynewzdevv1alpha1.Alert, e feedEvent) (notifier.Notification, error) {
+	tmpl, err := parseAlertTemplate(alert.Spec.Template)
+	if err != nil {
+		return notifier.Notification{}, err
+	}
+
+	var message strings.Builder
+	if err := tmpl.Execute(&message, alertTemplateData{Feed: e.feed, Event: e.event, Severity: e.severity, Message: e.message}); err != nil {
+		return notifier.Notification{}, fmt.Errorf("cannot render alert template: %w", err)
+	}
+
+	return notifier.Notification{
+		Subject:   fmt.Sprintf("%s event of Feed %s/%s", e.event, e.feed.Namespace, e.feed.Name),
+		Message:   message.String(),
+		Event:     string(e.event),
+		Severity:  e.severity,
+		Name:      e.feed.Name,
+		Namespace: e.feed.Namespace,
+		Timestamp: e.at,
+	}, nil
+}
@@ -0,0 +1,465 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/notifier"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_diffFeedEvents(t *testing.T) {
+	now := time.Date(2022, 10, 16, 21, 0, 0, 0, time.UTC)
+	available := func(status metav1.ConditionStatus, reason FeedConditionReason, message string) []metav1.Condition {
+		var conditions []metav1.Condition
+		meta.SetStatusCondition(&conditions, makeFeedAvailableCondition(status, reason, message))
+		return conditions
+	}
+
+	// event is the comparable part of a feedEvent.
+	type event struct {
+		Event    skynewzdevv1alpha1.AlertEventType
+		Severity notifier.Severity
+		Message  string
+	}
+
+	tests := []struct {
+		name      string
+		oldStatus skynewzdevv1alpha1.FeedStatus
+		newStatus skynewzdevv1alpha1.FeedStatus
+		preview   bool
+		want      []event
+	}{
+		{
+			name:      "unchanged",
+			oldStatus: skynewzdevv1alpha1.FeedStatus{Conditions: available(metav1.ConditionTrue, FeedSuccessfullyDeployed, "")},
+			newStatus: skynewzdevv1alpha1.FeedStatus{Conditions: available(metav1.ConditionTrue, FeedSuccessfullyDeployed, "")},
+			want:      []event{},
+		},
+		{
+			name:      "deployed",
+			newStatus: skynewzdevv1alpha1.FeedStatus{Conditions: available(metav1.ConditionTrue, FeedSuccessfullyDeployed, "")},
+			want:      []event{{Event: skynewzdevv1alpha1.AlertEventAvailability, Severity: notifier.SeverityInfo, Message: "feed is available"}},
+		},
+		{
+			name:      "failed",
+			oldStatus: skynewzdevv1alpha1.FeedStatus{Conditions: available(metav1.ConditionTrue, FeedSuccessfullyDeployed, "")},
+			newStatus: skynewzdevv1alpha1.FeedStatus{
+				LastError:  "404 Not Found",
+				Conditions: available(metav1.ConditionFalse, FeedFailedToDeploy, "404 Not Found"),
+			},
+			want: []event{
+				{Event: skynewzdevv1alpha1.AlertEventAvailability, Severity: notifier.SeverityError, Message: "feed is unavailable: 404 Not Found"},
+				{Event: skynewzdevv1alpha1.AlertEventError, Severity: notifier.SeverityError, Message: "Put.io reported an error: 404 Not Found"},
+			},
+		},
+		{
+			name:      "unavailable without message",
+			oldStatus: skynewzdevv1alpha1.FeedStatus{Conditions: available(metav1.ConditionTrue, FeedSuccessfullyDeployed, "")},
+			newStatus: skynewzdevv1alpha1.FeedStatus{Conditions: available(metav1.ConditionFalse, FeedAccountUnavailable, "")},
+			want:      []event{{Event: skynewzdevv1alpha1.AlertEventAvailability, Severity: notifier.SeverityError, Message: "feed is unavailable (AccountUnavailable)"}},
+		},
+		{
+			name:      "same error",
+			oldStatus: skynewzdevv1alpha1.FeedStatus{LastError: "404 Not Found"},
+			newStatus: skynewzdevv1alpha1.FeedStatus{LastError: "404 Not Found"},
+			want:      []event{},
+		},
+		{
+			name:      "new item",
+			oldStatus: skynewzdevv1alpha1.FeedStatus{CompletedItemCount: 2, LastItemID: uintToPtr(11)},
+			newStatus: skynewzdevv1alpha1.FeedStatus{CompletedItemCount: 3, LastItemID: uintToPtr(12), LastCompletedItem: "House.of.the.Dragon.S01E03"},
+			want:      []event{{Event: skynewzdevv1alpha1.AlertEventDownload, Severity: notifier.SeverityInfo, Message: `"House.of.the.Dragon.S01E03" downloaded`}},
+		},
+		{
+			name:      "new items",
+			oldStatus: skynewzdevv1alpha1.FeedStatus{CompletedItemCount: 1, LastItemID: uintToPtr(10)},
+			newStatus: skynewzdevv1alpha1.FeedStatus{CompletedItemCount: 3, LastItemID: uintToPtr(12), LastCompletedItem: "House.of.the.Dragon.S01E03"},
+			want: []event{{
+				Event:    skynewzdevv1alpha1.AlertEventDownload,
+				Severity: notifier.SeverityInfo,
+				Message:  `2 new items downloaded, the last one is "House.of.the.Dragon.S01E03"`,
+			}},
+		},
+		{
+			name:      "items counted for the first time",
+			oldStatus: skynewzdevv1alpha1.FeedStatus{},
+			newStatus: skynewzdevv1alpha1.FeedStatus{CompletedItemCount: 3, LastItemID: uintToPtr(12), LastCompletedItem: "House.of.the.Dragon.S01E03"},
+			want:      []event{},
+		},
+		{
+			name:      "preview",
+			newStatus: skynewzdevv1alpha1.FeedStatus{Conditions: available(metav1.ConditionTrue, FeedPreviewing, "")},
+			preview:   true,
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldFeed := &skynewzdevv1alpha1.Feed{Spec: skynewzdevv1alpha1.FeedSpec{Preview: tt.preview}, Status: tt.oldStatus}
+			newFeed := &skynewzdevv1alpha1.Feed{Spec: skynewzdevv1alpha1.FeedSpec{Preview: tt.preview}, Status: tt.newStatus}
+
+			var got []event
+			if events := diffFeedEvents(oldFeed, newFeed, now); events != nil {
+				got = make([]event, len(events))
+				for i, e := range events {
+					got[i] = event{Event: e.event, Severity: e.severity, Message: e.message}
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("diffFeedEvents() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_isFeedStale(t *testing.T) {
+	now := time.Date(2022, 10, 16, 21, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(d)}
+	}
+
+	tests := []struct {
+		name string
+		feed skynewzdevv1alpha1.Feed
+		want bool
+	}{
+		{
+			name: "recently fetched",
+			feed: skynewzdevv1alpha1.Feed{Status: skynewzdevv1alpha1.FeedStatus{ID: uintToPtr(42), LastFetch: at(-time.Hour)}},
+			want: false,
+		},
+		{
+			name: "not fetched for long",
+			feed: skynewzdevv1alpha1.Feed{Status: skynewzdevv1alpha1.FeedStatus{ID: uintToPtr(42), LastFetch: at(-25 * time.Hour)}},
+			want: true,
+		},
+		{
+			name: "never fetched since creation",
+			feed: skynewzdevv1alpha1.Feed{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: *at(-48 * time.Hour)},
+				Status:     skynewzdevv1alpha1.FeedStatus{ID: uintToPtr(42)},
+			},
+			want: true,
+		},
+		{
+			name: "paused",
+			feed: skynewzdevv1alpha1.Feed{
+				Spec:   skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(true)},
+				Status: skynewzdevv1alpha1.FeedStatus{ID: uintToPtr(42), LastFetch: at(-25 * time.Hour)},
+			},
+			want: false,
+		},
+		{
+			name: "not created at Put.io",
+			feed: skynewzdevv1alpha1.Feed{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: *at(-48 * time.Hour)}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFeedStale(&tt.feed, 24*time.Hour, now); got != tt.want {
+				t.Errorf("isFeedStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_alertMatches(t *testing.T) {
+	feed := &skynewzdevv1alpha1.Feed{ObjectMeta: metav1.ObjectMeta{Name: "house-of-the-dragon", Namespace: "default", Labels: map[string]string{"app": "putio"}}}
+
+	tests := []struct {
+		name      string
+		namespace string
+		spec      skynewzdevv1alpha1.AlertSpec
+		event     skynewzdevv1alpha1.AlertEventType
+		want      bool
+	}{
+		{
+			name:      "every feed and event",
+			namespace: "default",
+			event:     skynewzdevv1alpha1.AlertEventError,
+			want:      true,
+		},
+		{
+			name:      "other namespace",
+			namespace: "media",
+			event:     skynewzdevv1alpha1.AlertEventError,
+			want:      false,
+		},
+		{
+			name:      "selected feed",
+			namespace: "default",
+			spec:      skynewzdevv1alpha1.AlertSpec{FeedSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "putio"}}},
+			event:     skynewzdevv1alpha1.AlertEventError,
+			want:      true,
+		},
+		{
+			name:      "feed not selected",
+			namespace: "default",
+			spec:      skynewzdevv1alpha1.AlertSpec{FeedSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}}},
+			event:     skynewzdevv1alpha1.AlertEventError,
+			want:      false,
+		},
+		{
+			name:      "event not subscribed",
+			namespace: "default",
+			spec:      skynewzdevv1alpha1.AlertSpec{Events: []skynewzdevv1alpha1.AlertEventType{skynewzdevv1alpha1.AlertEventDownload}},
+			event:     skynewzdevv1alpha1.AlertEventError,
+			want:      false,
+		},
+		{
+			name:      "suspended",
+			namespace: "default",
+			spec:      skynewzdevv1alpha1.AlertSpec{Suspend: true},
+			event:     skynewzdevv1alpha1.AlertEventError,
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &skynewzdevv1alpha1.Alert{ObjectMeta: metav1.ObjectMeta{Name: "alert", Namespace: tt.namespace}, Spec: tt.spec}
+			if got := alertMatches(alert, feed, tt.event); got != tt.want {
+				t.Errorf("alertMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_renderNotification(t *testing.T) {
+	now := time.Date(2022, 10, 16, 21, 0, 0, 0, time.UTC)
+	e := feedEvent{
+		event:    skynewzdevv1alpha1.AlertEventError,
+		severity: notifier.SeverityError,
+		message:  "Put.io reported an error: 404 Not Found",
+		feed: &skynewzdevv1alpha1.Feed{
+			ObjectMeta: metav1.ObjectMeta{Name: "house-of-the-dragon", Namespace: "default"},
+			Spec:       skynewzdevv1alpha1.FeedSpec{Title: "House of the Dragon"},
+		},
+		at: now,
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "default template",
+			template: "",
+			want:     "[default/house-of-the-dragon] Put.io reported an error: 404 Not Found",
+		},
+		{
+			name:     "custom template",
+			template: "{{ .Severity }}: {{ .Feed.Spec.Title }} ({{ .Event }})",
+			want:     "error: House of the Dragon (Error)",
+		},
+		{
+			name:     "unknown field",
+			template: "{{ .Feed.Unknown }}",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &skynewzdevv1alpha1.Alert{Spec: skynewzdevv1alpha1.AlertSpec{Template: tt.template}}
+			got, err := renderNotification(alert, e)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderNotification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			want := notifier.Notification{
+				Subject:   "Error event of Feed default/house-of-the-dragon",
+				Message:   tt.want,
+				Event:     "Error",
+				Severity:  notifier.SeverityError,
+				Name:      "house-of-the-dragon",
+				Namespace: "default",
+				Timestamp: now,
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("renderNotification() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNotificationDispatcher_allow(t *testing.T) {
+	now := time.Date(2022, 10, 16, 21, 0, 0, 0, time.UTC)
+	alert := &skynewzdevv1alpha1.Alert{
+		ObjectMeta: metav1.ObjectMeta{UID: types.UID("alert"), Generation: 1},
+		Spec: skynewzdevv1alpha1.AlertSpec{
+			RateLimit: &skynewzdevv1alpha1.AlertRateLimit{Burst: 2, Every: metav1.Duration{Duration: time.Minute}},
+		},
+	}
+
+	d := &NotificationDispatcher{limiters: make(map[types.UID]*alertLimiter)}
+	steps := []struct {
+		at   time.Time
+		want bool
+	}{
+		{at: now, want: true},
+		{at: now, want: true},
+		{at: now.Add(time.Second), want: false},
+		{at: now.Add(time.Minute), want: true},
+		{at: now.Add(time.Minute), want: false},
+	}
+	for i, step := range steps {
+		if got := d.allow(alert, step.at); got != step.want {
+			t.Errorf("allow() at step %d = %v, want %v", i, got, step.want)
+		}
+	}
+
+	// a new generation resets the limiter
+	alert.Generation = 2
+	if !d.allow(alert, now.Add(time.Minute)) {
+		t.Error("allow() = false after the alert changed, want true")
+	}
+
+	d.pruneLimiters(nil)
+	if len(d.limiters) != 0 {
+		t.Errorf("pruneLimiters() kept %d limiters of deleted alerts", len(d.limiters))
+	}
+
+	unlimited := &skynewzdevv1alpha1.Alert{ObjectMeta: metav1.ObjectMeta{UID: types.UID("unlimited")}}
+	for i := 0; i < 10; i++ {
+		if !d.allow(unlimited, now) {
+			t.Fatal("allow() = false without rate limit, want true")
+		}
+	}
+}
+
+func TestNotificationDispatcher_notify(t *testing.T) {
+	now := time.Date(2022, 10, 16, 21, 0, 0, 0, time.UTC)
+	feed := &skynewzdevv1alpha1.Feed{ObjectMeta: metav1.ObjectMeta{Name: "andor", Namespace: "notify"}}
+	slow := &skynewzdevv1alpha1.Alert{ObjectMeta: metav1.ObjectMeta{Name: "slow", Namespace: "notify", UID: types.UID("slow")}}
+	fast := &skynewzdevv1alpha1.Alert{ObjectMeta: metav1.ObjectMeta{Name: "fast", Namespace: "notify", UID: types.UID("fast")}}
+
+	// workers are not started, the queue of the slow alert is full
+	recorder := record.NewFakeRecorder(10)
+	d := &NotificationDispatcher{
+		Recorder: recorder,
+		logger:   logr.Discard(),
+		limiters: make(map[types.UID]*alertLimiter),
+		workers: map[types.UID]*alertWorker{
+			slow.UID: {namespace: "notify", notifications: make(chan alertNotification), stop: func() {}},
+			fast.UID: {namespace: "notify", notifications: make(chan alertNotification, 1), stop: func() {}},
+		},
+	}
+
+	e := feedEvent{event: skynewzdevv1alpha1.AlertEventDownload, feed: feed, at: now}
+	d.notify(context.Background(), slow, e)
+	d.notify(context.Background(), fast, e)
+
+	if got := len(d.workers[fast.UID].notifications); got != 1 {
+		t.Errorf("notify() queued %d notifications for the fast alert, want 1", got)
+	}
+
+	if got := testutil.ToFloat64(droppedNotifications.WithLabelValues("notify", dropReasonAlertQueueFull)); got != 1 {
+		t.Errorf("putio_notifications_dropped_total = %v, want 1", got)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, "Warning "+eventNotificationDropped) {
+			t.Errorf("notify() recorded %q, want a %s event", event, eventNotificationDropped)
+		}
+	default:
+		t.Errorf("notify() recorded no event for the dropped notification")
+	}
+}
+
+func TestNotificationDispatcher_enqueueFeedEvents(t *testing.T) {
+	oldFeed := &skynewzdevv1alpha1.Feed{ObjectMeta: metav1.ObjectMeta{Name: "andor", Namespace: "enqueue"}}
+	newFeed := oldFeed.DeepCopy()
+	newFeed.Status.LastError = "404 Not Found"
+
+	recorder := record.NewFakeRecorder(10)
+	d := &NotificationDispatcher{Recorder: recorder, logger: logr.Discard(), events: make(chan feedEvent, 1)}
+
+	d.enqueueFeedEvents(oldFeed, newFeed)
+	d.enqueueFeedEvents(oldFeed, newFeed) // the queue is full
+
+	if got := len(d.events); got != 1 {
+		t.Errorf("enqueueFeedEvents() queued %d events, want 1", got)
+	}
+
+	if got := testutil.ToFloat64(droppedNotifications.WithLabelValues("enqueue", dropReasonQueueFull)); got != 1 {
+		t.Errorf("putio_notifications_dropped_total = %v, want 1", got)
+	}
+
+	if got := len(recorder.Events); got != 1 {
+		t.Errorf("enqueueFeedEvents() recorded %d events, want 1", got)
+	}
+}
+
+func TestNotificationDispatcher_stopWorkers(t *testing.T) {
+	stopped := make(map[string]bool)
+	worker := func(namespace, name string) *alertWorker {
+		return &alertWorker{namespace: namespace, stop: func() { stopped[name] = true }}
+	}
+
+	d := &NotificationDispatcher{workers: map[types.UID]*alertWorker{
+		"kept":    worker("default", "kept"),
+		"deleted": worker("default", "deleted"),
+		"other":   worker("other", "other"),
+	}}
+
+	kept := skynewzdevv1alpha1.Alert{ObjectMeta: metav1.ObjectMeta{UID: "kept"}}
+	d.stopWorkers("default", []skynewzdevv1alpha1.Alert{kept})
+	if diff := cmp.Diff(map[string]bool{"deleted": true}, stopped); diff != "" {
+		t.Errorf("stopWorkers() mismatch (-want +got):\n%s", diff)
+	}
+
+	// every namespace
+	d.stopWorkers("", nil)
+	if len(d.workers) != 0 {
+		t.Errorf("stopWorkers() kept %d workers of deleted alerts", len(d.workers))
+	}
+}
+
+func Test_makeNotifier(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("cannot decode payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	provider := &skynewzdevv1alpha1.NotificationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "default"},
+		Spec:       skynewzdevv1alpha1.NotificationProviderSpec{Type: skynewzdevv1alpha1.NotificationProviderSlack, Address: server.URL + "/hooks/token"},
+	}
+
+	// no secret is read, so no client is needed
+	sender, err := makeNotifier(context.Background(), nil, server.Client(), provider)
+	if err != nil {
+		t.Fatalf("makeNotifier() error = %v", err)
+	}
+
+	if err := sender.Notify(context.Background(), notifier.Notification{Message: "feed is available"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if diff := cmp.Diff(map[string]any{"text": "feed is available"}, got); diff != "" {
+		t.Errorf("Notify() payload mismatch (-want +got):\n%s", diff)
+	}
+}
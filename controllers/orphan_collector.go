@@ -0,0 +1,293 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// OrphanPolicy tells what to do with managed Put.io feeds without a matching Feed.
+type OrphanPolicy string
+
+const (
+	// OrphanPolicyReport only reports orphaned feeds through events and metrics.
+	OrphanPolicyReport OrphanPolicy = "report"
+	// OrphanPolicyDelete deletes orphaned feeds from Put.io.
+	OrphanPolicyDelete OrphanPolicy = "delete"
+)
+
+var (
+	orphanedFeeds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "putio_orphaned_feeds",
+		Help: "Number of Put.io feeds managed by the operator without a matching Feed, per account.",
+	}, []string{"account"})
+
+	orphanedFeedsDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "putio_orphaned_feeds_deleted_total",
+		Help: "Number of orphaned Put.io feeds deleted, per account.",
+	}, []string{"account"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedFeeds, orphanedFeedsDeleted)
+}
+
+var (
+	_ manager.Runnable               = &OrphanCollector{}
+	_ manager.LeaderElectionRunnable = &OrphanCollector{}
+)
+
+// OrphanCollector periodically looks for Put.io feeds managed by the operator
+// which have no matching Feed anymore, e.g. after the finalizer was force-removed.
+type OrphanCollector struct {
+	client.Client
+	Recorder record.EventRecorder
+
+	// Interval between two collections.
+	Interval time.Duration
+
+	// Policy applied to orphaned feeds.
+	Policy OrphanPolicy
+
+	// DryRun reports the feeds the Delete policy would delete without deleting them.
+	DryRun bool
+
+	// TitleTemplate recognizes the Put.io feeds managed by the operator. Default to DefaultFeedTitleTemplate.
+	TitleTemplate *FeedTitleTemplate
+}
+
+// orphanAccount is a Put.io token orphaned feeds are looked for with.
+type orphanAccount struct {
+	// name identifies the account in metrics.
+	name string
+	// object receives the account events.
+	object    runtime.Object
+	namespace string
+	secretRef skynewzdevv1alpha1.AuthSecretReference
+}
+
+// Start runs a collection every interval until given context is done.
+func (c *OrphanCollector) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("orphan-collector")
+	ctx = log.IntoContext(ctx, logger)
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.collect(ctx); err != nil {
+			logger.Error(err, "unable to collect orphaned feeds")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only one replica deletes orphaned feeds.
+func (c *OrphanCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// collect looks for orphaned feeds in every known account.
+func (c *OrphanCollector) collect(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "controllers.OrphanCollector.collect")
+	defer span.End()
+
+	feeds := new(skynewzdevv1alpha1.FeedList)
+	if err := c.List(ctx, feeds); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot list feeds: %w", err)
+	}
+
+	accounts, err := c.listAccounts(ctx, feeds)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	for _, account := range accounts {
+		if err := c.collectAccount(ctx, account, feeds); err != nil {
+			span.RecordError(err)
+			log.FromContext(ctx).Error(err, "unable to collect orphaned feeds", "account", account.name)
+		}
+	}
+
+	return nil
+}
+
+// listAccounts returns every PutioAccount and every secret referenced by a Feed, once each.
+func (c *OrphanCollector) listAccounts(ctx context.Context, feeds *skynewzdevv1alpha1.FeedList) ([]orphanAccount, error) {
+	ctx, span := tracer.Start(ctx, "controllers.OrphanCollector.listAccounts")
+	defer span.End()
+
+	putioAccounts := new(skynewzdevv1alpha1.PutioAccountList)
+	if err := c.List(ctx, putioAccounts); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("cannot list accounts: %w", err)
+	}
+
+	var (
+		accounts = make([]orphanAccount, 0)
+		seen     = make(map[string]bool)
+	)
+
+	for i := range putioAccounts.Items {
+		account := &putioAccounts.Items[i]
+		ref := account.Spec.TokenSecretRef
+
+		key := ref.Namespace + "/" + ref.Name + "/" + ref.Key
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		accounts = append(accounts, orphanAccount{
+			name:      account.Name,
+			object:    account,
+			namespace: ref.Namespace,
+			secretRef: skynewzdevv1alpha1.AuthSecretReference{Name: ref.Name, Key: ref.Key},
+		})
+	}
+
+	for i := range feeds.Items {
+		feed := &feeds.Items[i]
+		if feed.Spec.AuthSecretRef == nil {
+			continue
+		}
+
+		ref := *feed.Spec.AuthSecretRef
+		key := feed.Namespace + "/" + ref.Name + "/" + ref.Key
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		accounts = append(accounts, orphanAccount{
+			name:      feed.Namespace + "/" + ref.Name,
+			object:    &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: feed.Namespace}},
+			namespace: feed.Namespace,
+			secretRef: ref,
+		})
+	}
+
+	span.SetAttributes(attribute.Int("accounts", len(accounts)))
+	return accounts, nil
+}
+
+// collectAccount applies the policy to the orphaned feeds of given account.
+func (c *OrphanCollector) collectAccount(ctx context.Context, account orphanAccount, feeds *skynewzdevv1alpha1.FeedList) error {
+	ctx, span := tracer.Start(ctx, "controllers.OrphanCollector.collectAccount")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("account", account.name))
+	logger := log.FromContext(ctx).WithValues("account", account.name)
+
+	putioClient, err := makePutioClientFromSecret(ctx, c, account.namespace, account.secretRef)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	putioFeeds, err := putioClient.Rss.List(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("unable to list Put.io feeds: %w", err)
+	}
+
+	titles := c.TitleTemplate
+	if titles == nil {
+		titles = defaultFeedTitleTemplate
+	}
+
+	orphans := findOrphanedFeeds(ctx, putioFeeds, feeds.Items, titles)
+	orphanedFeeds.WithLabelValues(account.name).Set(float64(len(orphans)))
+	span.SetAttributes(attribute.Int("orphans", len(orphans)))
+
+	for _, orphan := range orphans {
+		switch {
+		case c.Policy != OrphanPolicyDelete:
+			logger.Info("Found orphaned Put.io feed", "id", *orphan.ID, "title", orphan.Title)
+			c.Recorder.Eventf(account.object, corev1.EventTypeWarning, eventOrphanedFeedFound, "Put.io feed %d %q has no matching Feed", *orphan.ID, orphan.Title)
+		case c.DryRun:
+			logger.Info("Would delete orphaned Put.io feed", "id", *orphan.ID, "title", orphan.Title)
+			c.Recorder.Eventf(account.object, corev1.EventTypeNormal, eventOrphanedFeedFound, "dry-run: would delete Put.io feed %d %q which has no matching Feed", *orphan.ID, orphan.Title)
+		default:
+			logger.Info("Deleting orphaned Put.io feed", "id", *orphan.ID, "title", orphan.Title)
+			if err := putioClient.Rss.Delete(ctx, *orphan.ID); err != nil {
+				span.RecordError(err)
+				c.Recorder.Eventf(account.object, corev1.EventTypeWarning, eventUnableToDeleteOrphanedFeed, "unable to delete Put.io feed %d: %s", *orphan.ID, err)
+				continue
+			}
+
+			orphanedFeedsDeleted.WithLabelValues(account.name).Inc()
+			c.Recorder.Eventf(account.object, corev1.EventTypeNormal, eventOrphanedFeedDeleted, "deleted Put.io feed %d %q which had no matching Feed", *orphan.ID, orphan.Title)
+		}
+	}
+
+	return nil
+}
+
+// findOrphanedFeeds returns the managed Put.io feeds which match none of given feeds,
+// by status ID or, for feeds not created yet, by title.
+func findOrphanedFeeds(ctx context.Context, putioFeeds []*putio.Feed, feeds []skynewzdevv1alpha1.Feed, titles *FeedTitleTemplate) []*putio.Feed {
+	_, span := tracer.Start(ctx, "controllers.findOrphanedFeeds")
+	defer span.End()
+
+	var (
+		ids     = make(map[uint]bool)
+		pending = make(map[string]bool)
+	)
+
+	for _, feed := range feeds {
+		if feed.Status.ID != nil {
+			ids[*feed.Status.ID] = true
+		} else {
+			pending[feed.Spec.Title] = true
+		}
+	}
+
+	orphans := make([]*putio.Feed, 0)
+	for _, putioFeed := range putioFeeds {
+		title, managed := titles.ParseManaged(putioFeed.Title)
+		if !managed || putioFeed.ID == nil || ids[*putioFeed.ID] || pending[title] {
+			continue
+		}
+
+		orphans = append(orphans, putioFeed)
+	}
+
+	return orphans
+}
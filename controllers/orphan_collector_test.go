@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_findOrphanedFeeds(t *testing.T) {
+	putioFeeds := []*putio.Feed{
+		{ID: uintToPtr(1), Title: "foo|1|managed by Kubernetes/putio-operator"},
+		{ID: uintToPtr(2), Title: "bar (managed by Kubernetes/putio-operator)"},
+		{ID: uintToPtr(3), Title: "created manually"},
+		{ID: uintToPtr(4), Title: "baz|1|managed by Kubernetes/putio-operator"},
+	}
+
+	type args struct {
+		putioFeeds []*putio.Feed
+		feeds      []skynewzdevv1alpha1.Feed
+	}
+	tests := []struct {
+		name string
+		args args
+		want []*putio.Feed
+	}{
+		{
+			name: "no Feed",
+			args: args{putioFeeds: putioFeeds, feeds: nil},
+			want: []*putio.Feed{putioFeeds[0], putioFeeds[1], putioFeeds[3]},
+		},
+		{
+			name: "matched by ID",
+			args: args{
+				putioFeeds: putioFeeds,
+				feeds: []skynewzdevv1alpha1.Feed{
+					{Status: skynewzdevv1alpha1.FeedStatus{ID: uintToPtr(1)}},
+					{Status: skynewzdevv1alpha1.FeedStatus{ID: uintToPtr(4)}},
+				},
+			},
+			want: []*putio.Feed{putioFeeds[1]},
+		},
+		{
+			name: "matched by title while not created yet",
+			args: args{
+				putioFeeds: putioFeeds,
+				feeds: []skynewzdevv1alpha1.Feed{
+					{Spec: skynewzdevv1alpha1.FeedSpec{Title: "bar"}},
+					{Status: skynewzdevv1alpha1.FeedStatus{ID: uintToPtr(1)}},
+					{Status: skynewzdevv1alpha1.FeedStatus{ID: uintToPtr(4)}},
+				},
+			},
+			want: []*putio.Feed{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findOrphanedFeeds(context.Background(), tt.args.putioFeeds, tt.args.feeds, defaultFeedTitleTemplate)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("findOrphanedFeeds() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PutioAccountReconciler reconciles a PutioAccount object.
+type PutioAccountReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// ResyncInterval is the period after which the account is checked again against Put.io.
+	// Zero disables periodic resync.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=putioaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=putioaccounts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile validates the account token against Put.io and reports account information in status.
+func (r *PutioAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, "controllers.PutioAccountReconciler.Reconcile")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("account.name", req.Name))
+
+	logger := log.FromContext(ctx)
+
+	account := new(skynewzdevv1alpha1.PutioAccount)
+	if err := r.Get(ctx, req.NamespacedName, account); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	tokenRef := account.Spec.TokenSecretRef
+	putioClient, err := makePutioClientFromSecret(ctx, r, tokenRef.Namespace, skynewzdevv1alpha1.AuthSecretReference{Name: tokenRef.Name, Key: tokenRef.Key})
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(account, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
+		meta.SetStatusCondition(&account.Status.Conditions, makeAccountReadyCondition(metav1.ConditionFalse, AccountTokenMissing, err.Error()))
+		if err := r.Status().Update(ctx, account); err != nil {
+			logger.Error(err, "unable to update account status")
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Verifying account against Put.io")
+	info, err := putioClient.Account.Info(ctx)
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(account, corev1.EventTypeWarning, eventUnableToVerifyAccount, err.Error())
+		meta.SetStatusCondition(&account.Status.Conditions, makeAccountReadyCondition(metav1.ConditionFalse, AccountTokenRejected, err.Error()))
+		if err := r.Status().Update(ctx, account); err != nil {
+			logger.Error(err, "unable to update account status")
+		}
+
+		return ctrl.Result{}, fmt.Errorf("unable to get Put.io account info: %w", err)
+	}
+
+	account.Status.Username = info.Username
+	account.Status.Mail = info.Mail
+	account.Status.DiskSize = info.Disk.Size
+	account.Status.DiskUsed = info.Disk.Used
+	account.Status.DiskAvailable = info.Disk.Avail
+	account.Status.LastCheck = &metav1.Time{Time: time.Now()}
+
+	if !meta.IsStatusConditionTrue(account.Status.Conditions, string(AccountReady)) {
+		r.Recorder.Eventf(account, corev1.EventTypeNormal, eventAccountVerified, "token accepted for user %q", info.Username)
+	}
+	meta.SetStatusCondition(&account.Status.Conditions, makeAccountReadyCondition(metav1.ConditionTrue, AccountTokenAccepted, ""))
+
+	if err := r.Status().Update(ctx, account); err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err //nolint:wrapcheck
+	}
+
+	logger.Info("Account successfully reconciled")
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PutioAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "controllers.PutioAccountReconciler.SetupWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&skynewzdevv1alpha1.PutioAccount{}).
+		Complete(r)
+}
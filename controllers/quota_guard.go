@@ -0,0 +1,257 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var (
+	_ manager.Runnable               = &QuotaGuard{}
+	_ manager.LeaderElectionRunnable = &QuotaGuard{}
+)
+
+// QuotaGuard periodically reads the disk usage of each account Feeds use, and pauses
+// every Feed of an account nearly full until enough space is freed, through their QuotaPaused condition.
+// The spec of the Feeds is left untouched, the FeedReconciler pauses and resumes them at Put.io.
+type QuotaGuard struct {
+	client.Client
+	Recorder record.EventRecorder
+
+	// Interval between two checks.
+	Interval time.Duration
+
+	// HighWaterMark is the percentage of used disk above which feeds are paused.
+	HighWaterMark float64
+
+	// LowWaterMark is the percentage of used disk below which paused feeds are resumed.
+	LowWaterMark float64
+}
+
+// quotaAccount groups the feeds using the same token.
+type quotaAccount struct {
+	// name identifies the account in logs.
+	name  string
+	feeds []*skynewzdevv1alpha1.Feed
+}
+
+// Start runs a check every interval until given context is done.
+func (g *QuotaGuard) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("quota-guard")
+	ctx = log.IntoContext(ctx, logger)
+
+	ticker := time.NewTicker(g.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := g.check(ctx); err != nil {
+			logger.Error(err, "unable to check disk quotas")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only one replica pauses and resumes feeds.
+func (g *QuotaGuard) NeedLeaderElection() bool {
+	return true
+}
+
+// check compares the disk usage of every account Feeds use with the water marks.
+func (g *QuotaGuard) check(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "controllers.QuotaGuard.check")
+	defer span.End()
+
+	feeds := new(skynewzdevv1alpha1.FeedList)
+	if err := g.List(ctx, feeds); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot list feeds: %w", err)
+	}
+
+	accounts := groupFeedsByAccount(feeds.Items)
+	span.SetAttributes(attribute.Int("accounts", len(accounts)))
+
+	for _, account := range accounts {
+		if err := g.checkAccount(ctx, account); err != nil {
+			span.RecordError(err)
+			log.FromContext(ctx).Error(err, "unable to check disk quota", "account", account.name)
+		}
+	}
+
+	return nil
+}
+
+// checkAccount pauses or resumes the feeds of given account from its disk usage.
+func (g *QuotaGuard) checkAccount(ctx context.Context, account quotaAccount) error {
+	ctx, span := tracer.Start(ctx, "controllers.QuotaGuard.checkAccount")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("account", account.name))
+
+	putioClient, err := g.makePutioClient(ctx, account.feeds[0])
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	info, err := putioClient.Account.Info(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("cannot get account info: %w", err)
+	}
+
+	if info.Disk.Size <= 0 {
+		return nil
+	}
+
+	used := float64(info.Disk.Used) / float64(info.Disk.Size) * 100
+	span.SetAttributes(attribute.Float64("disk.used_percent", used))
+
+	wasPaused := false
+	for _, feed := range account.feeds {
+		wasPaused = wasPaused || isQuotaPaused(feed)
+	}
+
+	paused := decideQuotaPause(wasPaused, used, g.HighWaterMark, g.LowWaterMark)
+	condition := makeQuotaPausedCondition(paused, used, g.HighWaterMark, g.LowWaterMark)
+
+	for _, feed := range account.feeds {
+		if err := g.setQuotaPaused(ctx, feed, condition); err != nil {
+			span.RecordError(err)
+			log.FromContext(ctx).Error(err, "unable to update feed quota condition", "feed", client.ObjectKeyFromObject(feed))
+		}
+	}
+
+	return nil
+}
+
+// setQuotaPaused sets the QuotaPaused condition of given feed when its status changes,
+// which triggers its reconciliation.
+func (g *QuotaGuard) setQuotaPaused(ctx context.Context, feed *skynewzdevv1alpha1.Feed, condition metav1.Condition) error {
+	ctx, span := tracer.Start(ctx, "controllers.QuotaGuard.setQuotaPaused")
+	defer span.End()
+
+	current := meta.FindStatusCondition(feed.Status.Conditions, string(FeedQuotaPaused))
+	if current != nil && current.Status == condition.Status {
+		return nil
+	}
+
+	meta.SetStatusCondition(&feed.Status.Conditions, condition)
+	if err := g.Status().Update(ctx, feed); err != nil {
+		span.RecordError(err)
+		return err //nolint:wrapcheck
+	}
+
+	switch {
+	case condition.Status == metav1.ConditionTrue:
+		g.Recorder.Event(feed, corev1.EventTypeWarning, eventFeedQuotaPaused, condition.Message)
+	case current != nil:
+		g.Recorder.Event(feed, corev1.EventTypeNormal, eventFeedQuotaResumed, condition.Message)
+	}
+
+	return nil
+}
+
+// makePutioClient authenticates with the account of given feed.
+func (g *QuotaGuard) makePutioClient(ctx context.Context, feed *skynewzdevv1alpha1.Feed) (*putio.Client, error) {
+	if feed.Spec.AccountRef != nil {
+		return makePutioClientFromAccount(ctx, g, feed.Namespace, *feed.Spec.AccountRef)
+	}
+
+	return makePutioClientFromSecret(ctx, g, feed.Namespace, *feed.Spec.AuthSecretRef)
+}
+
+// groupFeedsByAccount groups given feeds by PutioAccount, or by secret holding their token.
+// Feeds being previewed or deleted, or without credentials, are left out.
+func groupFeedsByAccount(feeds []skynewzdevv1alpha1.Feed) []quotaAccount {
+	var (
+		accounts = make([]quotaAccount, 0)
+		index    = make(map[string]int)
+	)
+
+	for i := range feeds {
+		feed := &feeds[i]
+
+		var name string
+		switch {
+		case feed.Spec.Preview || !feed.DeletionTimestamp.IsZero():
+			continue
+		case feed.Spec.AccountRef != nil:
+			name = feed.Spec.AccountRef.Name
+		case feed.Spec.AuthSecretRef != nil:
+			name = feed.Namespace + "/" + feed.Spec.AuthSecretRef.Name + "/" + feed.Spec.AuthSecretRef.Key
+		default:
+			continue
+		}
+
+		if i, ok := index[name]; ok {
+			accounts[i].feeds = append(accounts[i].feeds, feed)
+			continue
+		}
+
+		index[name] = len(accounts)
+		accounts = append(accounts, quotaAccount{name: name, feeds: []*skynewzdevv1alpha1.Feed{feed}})
+	}
+
+	return accounts
+}
+
+// decideQuotaPause tells whether the feeds of an account using given percentage of its disk should be paused.
+// Between the water marks, feeds stay as they were so that they do not flap.
+func decideQuotaPause(paused bool, used, highWaterMark, lowWaterMark float64) bool {
+	switch {
+	case used >= highWaterMark:
+		return true
+	case used < lowWaterMark:
+		return false
+	default:
+		return paused
+	}
+}
+
+// makeQuotaPausedCondition reports whether feeds are paused for given disk usage.
+func makeQuotaPausedCondition(paused bool, used, highWaterMark, lowWaterMark float64) metav1.Condition {
+	if paused {
+		message := fmt.Sprintf("disk %.1f%% used, feeds are paused until it goes below %.1f%%", used, lowWaterMark)
+		return makeFeedQuotaPausedCondition(metav1.ConditionTrue, FeedQuotaExceeded, message)
+	}
+
+	message := fmt.Sprintf("disk %.1f%% used, feeds are paused above %.1f%%", used, highWaterMark)
+	return makeFeedQuotaPausedCondition(metav1.ConditionFalse, FeedQuotaAvailable, message)
+}
+
+// isQuotaPaused tells whether the QuotaGuard paused given feed.
+func isQuotaPaused(feed *skynewzdevv1alpha1.Feed) bool {
+	return meta.IsStatusConditionTrue(feed.Status.Conditions, string(FeedQuotaPaused))
+}
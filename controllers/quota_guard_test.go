@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_decideQuotaPause(t *testing.T) {
+	type args struct {
+		paused bool
+		used   float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "below low water mark", args: args{paused: false, used: 50}, want: false},
+		{name: "resumed below low water mark", args: args{paused: true, used: 89.9}, want: false},
+		{name: "between water marks while running", args: args{paused: false, used: 92}, want: false},
+		{name: "between water marks while paused", args: args{paused: true, used: 92}, want: true},
+		{name: "at low water mark while paused", args: args{paused: true, used: 90}, want: true},
+		{name: "at high water mark", args: args{paused: false, used: 95}, want: true},
+		{name: "full", args: args{paused: false, used: 100}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decideQuotaPause(tt.args.paused, tt.args.used, 95, 90); got != tt.want {
+				t.Errorf("decideQuotaPause() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_makeQuotaPausedCondition(t *testing.T) {
+	tests := []struct {
+		name   string
+		paused bool
+		want   metav1.Condition
+	}{
+		{
+			name:   "paused",
+			paused: true,
+			want: metav1.Condition{
+				Type:    string(FeedQuotaPaused),
+				Status:  metav1.ConditionTrue,
+				Reason:  string(FeedQuotaExceeded),
+				Message: "disk 96.5% used, feeds are paused until it goes below 90.0%",
+			},
+		},
+		{
+			name:   "not paused",
+			paused: false,
+			want: metav1.Condition{
+				Type:    string(FeedQuotaPaused),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(FeedQuotaAvailable),
+				Message: "disk 96.5% used, feeds are paused above 95.0%",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := makeQuotaPausedCondition(tt.paused, 96.5, 95, 90)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("makeQuotaPausedCondition() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_groupFeedsByAccount(t *testing.T) {
+	now := metav1.Now()
+	feeds := []skynewzdevv1alpha1.Feed{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"},
+			Spec:       skynewzdevv1alpha1.FeedSpec{AuthSecretRef: &skynewzdevv1alpha1.AuthSecretReference{Name: "putio", Key: "token"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"},
+			Spec:       skynewzdevv1alpha1.FeedSpec{AccountRef: &skynewzdevv1alpha1.AccountReference{Name: "family"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "other"},
+			Spec:       skynewzdevv1alpha1.FeedSpec{AuthSecretRef: &skynewzdevv1alpha1.AuthSecretReference{Name: "putio", Key: "token"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: "media"},
+			Spec:       skynewzdevv1alpha1.FeedSpec{AccountRef: &skynewzdevv1alpha1.AccountReference{Name: "family"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "e", Namespace: "default"},
+			Spec:       skynewzdevv1alpha1.FeedSpec{AuthSecretRef: &skynewzdevv1alpha1.AuthSecretReference{Name: "putio", Key: "token"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "default"},
+			Spec:       skynewzdevv1alpha1.FeedSpec{Preview: true, AccountRef: &skynewzdevv1alpha1.AccountReference{Name: "family"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleting", Namespace: "default", DeletionTimestamp: &now},
+			Spec:       skynewzdevv1alpha1.FeedSpec{AccountRef: &skynewzdevv1alpha1.AccountReference{Name: "family"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-credentials", Namespace: "default"},
+		},
+	}
+
+	got := groupFeedsByAccount(feeds)
+	want := map[string][]string{
+		"default/putio/token": {"a", "e"},
+		"family":              {"b", "d"},
+		"other/putio/token":   {"c"},
+	}
+
+	gotNames := make(map[string][]string, len(got))
+	for _, account := range got {
+		for _, feed := range account.feeds {
+			gotNames[account.name] = append(gotNames[account.name], feed.Name)
+		}
+	}
+
+	if diff := cmp.Diff(want, gotNames); diff != "" {
+		t.Errorf("groupFeedsByAccount() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_isFeedPaused(t *testing.T) {
+	quotaPaused := []metav1.Condition{{Type: string(FeedQuotaPaused), Status: metav1.ConditionTrue}}
+	quotaResumed := []metav1.Condition{{Type: string(FeedQuotaPaused), Status: metav1.ConditionFalse}}
+
+	tests := []struct {
+		name string
+		feed *skynewzdevv1alpha1.Feed
+		want bool
+	}{
+		{
+			name: "running",
+			feed: &skynewzdevv1alpha1.Feed{Spec: skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(false)}},
+			want: false,
+		},
+		{
+			name: "paused from spec",
+			feed: &skynewzdevv1alpha1.Feed{Spec: skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(true)}},
+			want: true,
+		},
+		{
+			name: "paused by quota",
+			feed: &skynewzdevv1alpha1.Feed{
+				Spec:   skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(false)},
+				Status: skynewzdevv1alpha1.FeedStatus{Conditions: quotaPaused},
+			},
+			want: true,
+		},
+		{
+			name: "resumed by quota",
+			feed: &skynewzdevv1alpha1.Feed{
+				Spec:   skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(false)},
+				Status: skynewzdevv1alpha1.FeedStatus{Conditions: quotaResumed},
+			},
+			want: false,
+		},
+		{
+			name: "paused by schedule",
+			feed: &skynewzdevv1alpha1.Feed{
+				Spec: skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(false)},
+				Status: skynewzdevv1alpha1.FeedStatus{Conditions: []metav1.Condition{
+					{Type: string(FeedScheduleActive), Status: metav1.ConditionFalse},
+				}},
+			},
+			want: true,
+		},
+		{
+			name: "paused from spec while resumed by quota",
+			feed: &skynewzdevv1alpha1.Feed{
+				Spec:   skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(true)},
+				Status: skynewzdevv1alpha1.FeedStatus{Conditions: quotaResumed},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFeedPaused(tt.feed); got != tt.want {
+				t.Errorf("isFeedPaused() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_detectDrift_quotaPaused(t *testing.T) {
+	feed := &skynewzdevv1alpha1.Feed{
+		Spec: skynewzdevv1alpha1.FeedSpec{
+			Title:                "foo",
+			Keyword:              "bar",
+			ParentDirID:          uintToPtr(0),
+			DeleteOldFiles:       boolToPtr(false),
+			DontProcessWholeFeed: boolToPtr(false),
+			Paused:               boolToPtr(false),
+		},
+	}
+	putioFeed := makePutioFeedFromSpec(context.Background(), feed, "https://example.com/rss", defaultFeedTitleTemplate)
+	running := computeSpecHash(context.Background(), feed, "https://example.com/rss", defaultFeedTitleTemplate)
+
+	feed.Status.Conditions = []metav1.Condition{makeQuotaPausedCondition(true, 99, 95, 90)}
+
+	if got := computeSpecHash(context.Background(), feed, "https://example.com/rss", defaultFeedTitleTemplate); got == running {
+		t.Errorf("computeSpecHash() = %v, want a different hash once paused by quota", got)
+	}
+
+	want := []string{"paused false instead of true"}
+	got := detectDrift(context.Background(), putioFeed, feed, "https://example.com/rss", defaultFeedTitleTemplate)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("detectDrift() mismatch (-want +got):\n%s", diff)
+	}
+
+	putioFeed.Paused = true
+	if got := detectDrift(context.Background(), putioFeed, feed, "https://example.com/rss", defaultFeedTitleTemplate); len(got) != 0 {
+		t.Errorf("detectDrift() = %v, want no drift", got)
+	}
+}
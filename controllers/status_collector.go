@@ -0,0 +1,162 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// statusCollectTimeout bounds the time spent listing resources on each scrape.
+const statusCollectTimeout = time.Second * 10
+
+// Feed metrics are labelled with feed_namespace and feed, the namespace label being
+// set by Prometheus to the namespace of the operator.
+var (
+	feedFailedItemsDesc = prometheus.NewDesc(
+		"putio_feed_failed_items",
+		"Number of feed items Put.io failed to transfer.",
+		[]string{"feed_namespace", "feed"}, nil,
+	)
+	feedLastFetchAgeDesc = prometheus.NewDesc(
+		"putio_feed_seconds_since_last_fetch",
+		"Seconds since Put.io last fetched the RSS feed.",
+		[]string{"feed_namespace", "feed"}, nil,
+	)
+	feedPausedDesc = prometheus.NewDesc(
+		"putio_feed_paused",
+		"Whether the feed is paused, by its spec, its schedule, on expiry or by the quota check.",
+		[]string{"feed_namespace", "feed"}, nil,
+	)
+	feedLastErrorDesc = prometheus.NewDesc(
+		"putio_feed_last_error",
+		"Whether Put.io reported an error while processing the RSS feed.",
+		[]string{"feed_namespace", "feed"}, nil,
+	)
+	accountDiskSizeDesc = prometheus.NewDesc(
+		"putio_account_disk_size_bytes",
+		"Disk size of the Put.io account.",
+		[]string{"account"}, nil,
+	)
+	accountDiskUsedDesc = prometheus.NewDesc(
+		"putio_account_disk_used_bytes",
+		"Used disk space of the Put.io account.",
+		[]string{"account"}, nil,
+	)
+	accountDiskAvailableDesc = prometheus.NewDesc(
+		"putio_account_disk_available_bytes",
+		"Available disk space of the Put.io account.",
+		[]string{"account"}, nil,
+	)
+)
+
+var _ prometheus.Collector = &StatusCollector{}
+
+// StatusCollector exposes the health of Feeds and the quota of PutioAccounts, read from their status on each scrape.
+// It does not call Put.io itself, the values are as fresh as the last reconciliation.
+type StatusCollector struct {
+	client.Reader
+
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+}
+
+// NewStatusCollector returns a StatusCollector reading resources with given reader, usually the manager cache.
+func NewStatusCollector(reader client.Reader) *StatusCollector {
+	return &StatusCollector{Reader: reader, now: time.Now}
+}
+
+// Describe implements prometheus.Collector.
+func (c *StatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- feedFailedItemsDesc
+	ch <- feedLastFetchAgeDesc
+	ch <- feedPausedDesc
+	ch <- feedLastErrorDesc
+	ch <- accountDiskSizeDesc
+	ch <- accountDiskUsedDesc
+	ch <- accountDiskAvailableDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *StatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusCollectTimeout)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "controllers.StatusCollector.Collect")
+	defer span.End()
+
+	logger := log.FromContext(ctx).WithName("status-collector")
+
+	var feeds skynewzdevv1alpha1.FeedList
+	if err := c.List(ctx, &feeds); err != nil {
+		logger.Error(err, "unable to list feeds")
+		span.RecordError(err)
+	}
+
+	for i := range feeds.Items {
+		c.collectFeed(ch, &feeds.Items[i])
+	}
+
+	var accounts skynewzdevv1alpha1.PutioAccountList
+	if err := c.List(ctx, &accounts); err != nil {
+		logger.Error(err, "unable to list Put.io accounts")
+		span.RecordError(err)
+	}
+
+	for i := range accounts.Items {
+		c.collectAccount(ch, &accounts.Items[i])
+	}
+}
+
+// collectFeed sends the metrics of given feed.
+func (c *StatusCollector) collectFeed(ch chan<- prometheus.Metric, feed *skynewzdevv1alpha1.Feed) {
+	labels := []string{feed.Namespace, feed.Name}
+
+	ch <- prometheus.MustNewConstMetric(feedFailedItemsDesc, prometheus.GaugeValue, float64(feed.Status.FailedItemCount), labels...)
+	ch <- prometheus.MustNewConstMetric(feedPausedDesc, prometheus.GaugeValue, boolToFloat(isFeedPaused(feed)), labels...)
+	ch <- prometheus.MustNewConstMetric(feedLastErrorDesc, prometheus.GaugeValue, boolToFloat(feed.Status.LastError != ""), labels...)
+
+	// never fetched yet
+	if feed.Status.LastFetch != nil {
+		age := c.now().Sub(feed.Status.LastFetch.Time).Seconds()
+		ch <- prometheus.MustNewConstMetric(feedLastFetchAgeDesc, prometheus.GaugeValue, age, labels...)
+	}
+}
+
+// collectAccount sends the quota metrics of given account, once it has been checked.
+func (c *StatusCollector) collectAccount(ch chan<- prometheus.Metric, account *skynewzdevv1alpha1.PutioAccount) {
+	if account.Status.LastCheck == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(accountDiskSizeDesc, prometheus.GaugeValue, float64(account.Status.DiskSize), account.Name)
+	ch <- prometheus.MustNewConstMetric(accountDiskUsedDesc, prometheus.GaugeValue, float64(account.Status.DiskUsed), account.Name)
+	ch <- prometheus.MustNewConstMetric(accountDiskAvailableDesc, prometheus.GaugeValue, float64(account.Status.DiskAvailable), account.Name)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
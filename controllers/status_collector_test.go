@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// listReader is a client.Reader listing fixed resources.
+type listReader struct {
+	client.Reader
+	feeds     []skynewzdevv1alpha1.Feed
+	accounts  []skynewzdevv1alpha1.PutioAccount
+	alerts    []skynewzdevv1alpha1.Alert
+	providers []skynewzdevv1alpha1.NotificationProvider
+}
+
+func (r *listReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	switch l := list.(type) {
+	case *skynewzdevv1alpha1.FeedList:
+		l.Items = r.feeds
+	case *skynewzdevv1alpha1.PutioAccountList:
+		l.Items = r.accounts
+	case *skynewzdevv1alpha1.AlertList:
+		l.Items = r.alerts
+	case *skynewzdevv1alpha1.NotificationProviderList:
+		l.Items = r.providers
+	}
+
+	return nil
+}
+
+func TestStatusCollector_Collect(t *testing.T) {
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	reader := &listReader{
+		feeds: []skynewzdevv1alpha1.Feed{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "house-of-the-dragon", Namespace: "default"},
+				Spec:       skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(true)},
+				Status: skynewzdevv1alpha1.FeedStatus{
+					LastFetch:       &metav1.Time{Time: now.Add(-time.Minute * 5)},
+					LastError:       "unable to fetch",
+					FailedItemCount: 3,
+				},
+			},
+			{
+				// never fetched
+				ObjectMeta: metav1.ObjectMeta{Name: "the-rings-of-power", Namespace: "default"},
+			},
+			{
+				// paused by the quota check, not by its spec
+				ObjectMeta: metav1.ObjectMeta{Name: "andor", Namespace: "default"},
+				Spec:       skynewzdevv1alpha1.FeedSpec{Paused: boolToPtr(false)},
+				Status: skynewzdevv1alpha1.FeedStatus{
+					Conditions: []metav1.Condition{{Type: string(FeedQuotaPaused), Status: metav1.ConditionTrue}},
+				},
+			},
+		},
+		accounts: []skynewzdevv1alpha1.PutioAccount{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+				Status: skynewzdevv1alpha1.PutioAccountStatus{
+					DiskSize:      1000,
+					DiskUsed:      400,
+					DiskAvailable: 600,
+					LastCheck:     &metav1.Time{Time: now},
+				},
+			},
+			{
+				// never checked
+				ObjectMeta: metav1.ObjectMeta{Name: "unchecked"},
+			},
+		},
+	}
+
+	expected := `
+# HELP putio_account_disk_available_bytes Available disk space of the Put.io account.
+# TYPE putio_account_disk_available_bytes gauge
+putio_account_disk_available_bytes{account="shared"} 600
+# HELP putio_account_disk_size_bytes Disk size of the Put.io account.
+# TYPE putio_account_disk_size_bytes gauge
+putio_account_disk_size_bytes{account="shared"} 1000
+# HELP putio_account_disk_used_bytes Used disk space of the Put.io account.
+# TYPE putio_account_disk_used_bytes gauge
+putio_account_disk_used_bytes{account="shared"} 400
+# HELP putio_feed_failed_items Number of feed items Put.io failed to transfer.
+# TYPE putio_feed_failed_items gauge
+putio_feed_failed_items{feed="andor",feed_namespace="default"} 0
+putio_feed_failed_items{feed="house-of-the-dragon",feed_namespace="default"} 3
+putio_feed_failed_items{feed="the-rings-of-power",feed_namespace="default"} 0
+# HELP putio_feed_last_error Whether Put.io reported an error while processing the RSS feed.
+# TYPE putio_feed_last_error gauge
+putio_feed_last_error{feed="andor",feed_namespace="default"} 0
+putio_feed_last_error{feed="house-of-the-dragon",feed_namespace="default"} 1
+putio_feed_last_error{feed="the-rings-of-power",feed_namespace="default"} 0
+# HELP putio_feed_paused Whether the feed is paused, by its spec, its schedule, on expiry or by the quota check.
+# TYPE putio_feed_paused gauge
+putio_feed_paused{feed="andor",feed_namespace="default"} 1
+putio_feed_paused{feed="house-of-the-dragon",feed_namespace="default"} 1
+putio_feed_paused{feed="the-rings-of-power",feed_namespace="default"} 0
+# HELP putio_feed_seconds_since_last_fetch Seconds since Put.io last fetched the RSS feed.
+# TYPE putio_feed_seconds_since_last_fetch gauge
+putio_feed_seconds_since_last_fetch{feed="house-of-the-dragon",feed_namespace="default"} 300
+`
+
+	c := &StatusCollector{Reader: reader, now: func() time.Time { return now }}
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Errorf("Collect() unexpected metrics: %v", err)
+	}
+}
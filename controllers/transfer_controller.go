@@ -0,0 +1,341 @@
+/*
+Copyright 2022 Quentin Lemaire <quentin@lemairepro.fr>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// transferPollInterval is how often an unfinished transfer is read from Put.io.
+const transferPollInterval = time.Second * 30
+
+// TransferReconciler reconciles a Transfer object.
+type TransferReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=transfers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=transfers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=putio.skynewz.dev,resources=transfers/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile adds the transfer to Put.io, then follows its progress until it is finished.
+//
+//nolint:cyclop
+func (r *TransferReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, "controllers.TransferReconciler.Reconcile")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("transfer.name", req.Name),
+		attribute.String("transfer.namespace", req.Namespace),
+	)
+
+	logger := log.FromContext(ctx)
+
+	// get the transfer from Kubernetes
+	transfer := new(skynewzdevv1alpha1.Transfer)
+	if err := r.Get(ctx, req.NamespacedName, transfer); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	r.Recorder.Event(transfer, corev1.EventTypeNormal, eventReconciliationStarted, "starting reconciliation")
+
+	// examine DeletionTimestamp to determine if object is under deletion
+	if transfer.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(transfer, transferFinalizerAnnotation) {
+			controllerutil.AddFinalizer(transfer, transferFinalizerAnnotation)
+			if err := r.Update(ctx, transfer); err != nil {
+				r.Recorder.Eventf(transfer, corev1.EventTypeWarning, eventUnableToAddFinalizer, err.Error())
+				span.RecordError(err)
+				return ctrl.Result{}, err //nolint:wrapcheck
+			}
+			r.Recorder.Event(transfer, corev1.EventTypeNormal, eventAddedFinalizer, "transfer finalizer added")
+		}
+	} else {
+		// The object is being deleted
+		if controllerutil.ContainsFinalizer(transfer, transferFinalizerAnnotation) {
+			r.Recorder.Event(transfer, corev1.EventTypeNormal, eventCancelTransferAtPutio, "cancelling transfer at putio")
+			if err := r.cancelTransfer(ctx, transfer); err != nil {
+				r.Recorder.Event(transfer, corev1.EventTypeWarning, eventUnableToCancelTransferAtPutio, err.Error())
+				span.RecordError(err)
+				return ctrl.Result{RequeueAfter: time.Minute}, err
+			}
+			r.Recorder.Event(transfer, corev1.EventTypeNormal, eventSuccessfullyCanceledAtPutio, "transfer successfully cancelled")
+
+			// remove our finalizer from the list and update it.
+			controllerutil.RemoveFinalizer(transfer, transferFinalizerAnnotation)
+			if err := r.Update(ctx, transfer); err != nil {
+				r.Recorder.Event(transfer, corev1.EventTypeWarning, eventUnableToDeleteFinalizer, err.Error())
+				span.RecordError(err)
+				return ctrl.Result{}, err //nolint:wrapcheck
+			}
+		}
+
+		// Stop reconciliation as the item is being deleted
+		return ctrl.Result{}, nil
+	}
+
+	// nothing left to follow
+	if isTransferFinished(transfer) {
+		logger.Info("Transfer already finished", "status", transfer.Status.Status)
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("Setting up put.io client with transfer secret")
+	putioClient, err := makePutioClientFromSecret(ctx, r, req.Namespace, transfer.AuthSecretRef())
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Eventf(transfer, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	putioTransfer, err := r.addOrGetTransfer(ctx, transfer, putioClient)
+	if err != nil {
+		span.RecordError(err)
+		return ctrl.Result{}, err
+	}
+
+	// transfer has been cleaned at Put.io before we observed its end
+	if putioTransfer == nil {
+		logger.Info("Put.io transfer not found anymore, keeping last known status")
+		return ctrl.Result{}, nil
+	}
+
+	var retried bool
+	if putioTransfer.Status == putio.TransferStatusError && transfer.Status.Retried < transfer.Spec.Retries {
+		r.Recorder.Eventf(transfer, corev1.EventTypeNormal, eventRetryTransferAtPutio, "retrying failed transfer (%d/%d)", transfer.Status.Retried+1, transfer.Spec.Retries)
+		putioTransfer, err = putioClient.Transfers.Retry(ctx, *putioTransfer.ID)
+		if err != nil {
+			span.RecordError(err)
+			r.Recorder.Event(transfer, corev1.EventTypeWarning, eventUnableToRetryTransferAtPutio, err.Error())
+			return ctrl.Result{}, fmt.Errorf("unable to retry transfer at Put.io: %w", err)
+		}
+		retried = true
+	}
+
+	if err := r.updateTransferStatus(ctx, transfer, putioTransfer, retried); err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(transfer, corev1.EventTypeWarning, eventUnableToUpdateTransferStatus, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if isTransferFinished(transfer) {
+		logger.Info("Transfer finished", "status", transfer.Status.Status)
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: transferPollInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TransferReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	_, span := tracer.Start(context.Background(), "controllers.TransferReconciler.SetupWithManager")
+	defer span.End()
+
+	//nolint:wrapcheck
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&skynewzdevv1alpha1.Transfer{}).
+		Complete(r)
+}
+
+// addOrGetTransfer adds the transfer to Put.io when it does not have an ID yet, reads it otherwise.
+// A nil transfer is returned when it cannot be found at Put.io anymore.
+func (r *TransferReconciler) addOrGetTransfer(ctx context.Context, transfer *skynewzdevv1alpha1.Transfer, putioClient *putio.Client) (*putio.Transfer, error) {
+	ctx, span := tracer.Start(ctx, "controllers.TransferReconciler.addOrGetTransfer")
+	defer span.End()
+
+	logger := log.FromContext(ctx)
+
+	if transfer.Status.ID != nil {
+		span.SetAttributes(attribute.Int("transfer.id", int(*transfer.Status.ID)))
+		putioTransfer, err := putioClient.Transfers.Get(ctx, *transfer.Status.ID)
+		if err != nil {
+			if putio.IsNotFound(err) {
+				return nil, nil //nolint:nilnil
+			}
+
+			r.Recorder.Event(transfer, corev1.EventTypeWarning, eventUnableToGetTransferAtPutio, err.Error())
+			return nil, fmt.Errorf("unable to read Put.io transfer: %w", err)
+		}
+
+		return putioTransfer, nil
+	}
+
+	span.SetAttributes(attribute.String("action", "create"))
+	logger.Info("Adding transfer to Put.io")
+	r.Recorder.Event(transfer, corev1.EventTypeNormal, eventAddTransferAtPutio, "adding transfer at putio")
+
+	var parentDirID uint
+	if transfer.Spec.ParentDirID != nil {
+		parentDirID = *transfer.Spec.ParentDirID
+	}
+
+	putioTransfer, err := putioClient.Transfers.Add(ctx, transfer.Spec.URL, parentDirID)
+	if err != nil {
+		r.Recorder.Event(transfer, corev1.EventTypeWarning, eventUnableToAddTransferAtPutio, err.Error())
+		meta.SetStatusCondition(&transfer.Status.Conditions, makeTransferCompletedCondition(metav1.ConditionFalse, TransferFailedToBeCreated, err.Error()))
+		if err := r.Status().Update(ctx, transfer); err != nil {
+			logger.Error(err, "unable to update transfer status")
+		}
+
+		return nil, fmt.Errorf("unable to add transfer to Put.io: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("transfer.id", int(*putioTransfer.ID)))
+	r.Recorder.Event(transfer, corev1.EventTypeNormal, eventSuccessfullyAddedAtPutio, "transfer successfully added")
+	logger.Info("Put.io transfer successfully added", "id", putioTransfer.ID)
+
+	if err := r.recordTransferID(ctx, transfer, *putioTransfer.ID); err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(transfer, corev1.EventTypeWarning, eventUnableToUpdateTransferStatus, err.Error())
+		return nil, fmt.Errorf("unable to record Put.io transfer %d: %w", *putioTransfer.ID, err)
+	}
+
+	return putioTransfer, nil
+}
+
+// recordTransferID saves the ID of the transfer just added to Put.io, retrying on conflicts,
+// so the next reconciliation follows it instead of adding the URL again.
+func (r *TransferReconciler) recordTransferID(ctx context.Context, transfer *skynewzdevv1alpha1.Transfer, id uint) error {
+	ctx, span := tracer.Start(ctx, "controllers.TransferReconciler.recordTransferID")
+	defer span.End()
+
+	key := client.ObjectKeyFromObject(transfer)
+	first := true
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error { //nolint:wrapcheck
+		// read the latest version on conflicts only, the caller already holds a fresh one
+		if !first {
+			if err := r.Get(ctx, key, transfer); err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
+		first = false
+
+		transfer.Status.ID = &id
+		return r.Status().Update(ctx, transfer) //nolint:wrapcheck
+	})
+}
+
+// updateTransferStatus mirrors the Put.io transfer into the status, counting a retry when retried is set.
+func (r *TransferReconciler) updateTransferStatus(ctx context.Context, transfer *skynewzdevv1alpha1.Transfer, putioTransfer *putio.Transfer, retried bool) error {
+	ctx, span := tracer.Start(ctx, "controllers.TransferReconciler.updateTransferStatus")
+	defer span.End()
+
+	log.FromContext(ctx).Info("Updating transfer status")
+
+	wasFinished := isTransferFinished(transfer)
+
+	if retried {
+		transfer.Status.Retried++
+	}
+
+	transfer.Status.ID = putioTransfer.ID
+	transfer.Status.Name = putioTransfer.Name
+	transfer.Status.Status = putioTransfer.Status
+	transfer.Status.PercentDone = putioTransfer.PercentDone
+	transfer.Status.DownloadSpeed = putioTransfer.DownloadSpeed
+	transfer.Status.UploadSpeed = putioTransfer.UploadSpeed
+	transfer.Status.FileID = putioTransfer.FileID
+	transfer.Status.ErrorMessage = putioTransfer.ErrorMessage
+
+	switch putioTransfer.Status {
+	case putio.TransferStatusCompleted, putio.TransferStatusSeeding:
+		meta.SetStatusCondition(&transfer.Status.Conditions, makeTransferCompletedCondition(metav1.ConditionTrue, TransferSucceeded, ""))
+	case putio.TransferStatusError:
+		meta.SetStatusCondition(&transfer.Status.Conditions, makeTransferCompletedCondition(metav1.ConditionFalse, TransferFailed, putioTransfer.ErrorMessage))
+	default:
+		meta.SetStatusCondition(&transfer.Status.Conditions, makeTransferCompletedCondition(metav1.ConditionFalse, TransferInProgress, putioTransfer.StatusMessage))
+	}
+
+	if !wasFinished && isTransferFinished(transfer) {
+		if putioTransfer.Status == putio.TransferStatusError {
+			r.Recorder.Event(transfer, corev1.EventTypeWarning, eventTransferFailed, putioTransfer.ErrorMessage)
+		} else {
+			r.Recorder.Event(transfer, corev1.EventTypeNormal, eventTransferSuccessfullyCompleted, "transfer successfully completed")
+		}
+	}
+
+	return r.Client.Status().Update(ctx, transfer) //nolint:wrapcheck
+}
+
+// cancelTransfer cancels the transfer at Put.io when it was added there. Transfers never added need no credentials,
+// and a missing token secret does not block the deletion: the transfer is left at Put.io with a warning event.
+func (r *TransferReconciler) cancelTransfer(ctx context.Context, transfer *skynewzdevv1alpha1.Transfer) error {
+	ctx, span := tracer.Start(ctx, "controllers.TransferReconciler.cancelTransfer")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("action", "delete"))
+
+	if transfer.Status.ID == nil {
+		return nil
+	}
+
+	span.SetAttributes(attribute.Int("transfer.id", int(*transfer.Status.ID)))
+
+	putioClient, err := makePutioClientFromSecret(ctx, r, transfer.Namespace, transfer.AuthSecretRef())
+	if apierrors.IsNotFound(err) {
+		r.Recorder.Eventf(transfer, corev1.EventTypeWarning, eventUnableToGetAuthSecret, "transfer %d left at Put.io: %s", *transfer.Status.ID, err)
+		return nil
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		r.Recorder.Event(transfer, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
+		return err
+	}
+
+	if err := putioClient.Transfers.Cancel(ctx, *transfer.Status.ID); err != nil && !putio.IsNotFound(err) {
+		return fmt.Errorf("failed to cancel transfer: %w", err)
+	}
+
+	return nil
+}
+
+// isTransferFinished reports whether the transfer reached a state it will not leave by itself.
+// A failed transfer is finished once it cannot be retried anymore.
+func isTransferFinished(transfer *skynewzdevv1alpha1.Transfer) bool {
+	switch transfer.Status.Status {
+	case putio.TransferStatusCompleted, putio.TransferStatusSeeding:
+		return true
+	case putio.TransferStatusError:
+		return transfer.Status.Retried >= transfer.Spec.Retries
+	default:
+		return false
+	}
+}
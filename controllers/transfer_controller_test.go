@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	skynewzdevv1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// secretlessClient is a client.Client whose secrets were deleted.
+type secretlessClient struct {
+	client.Client
+}
+
+func (secretlessClient) Get(_ context.Context, key client.ObjectKey, _ client.Object) error {
+	return apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+}
+
+func TestTransferReconciler_cancelTransfer_withoutSecret(t *testing.T) {
+	id := uint(42)
+	tests := []struct {
+		name       string
+		id         *uint
+		wantEvents int
+	}{
+		{
+			// no Put.io call, nor credentials, are needed to delete a transfer never added at Put.io
+			name:       "without ID",
+			id:         nil,
+			wantEvents: 0,
+		},
+		{
+			// the transfer is left at Put.io rather than blocking the deletion
+			name:       "with ID",
+			id:         &id,
+			wantEvents: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &TransferReconciler{Client: secretlessClient{}, Recorder: recorder}
+			transfer := &skynewzdevv1alpha1.Transfer{
+				ObjectMeta: metav1.ObjectMeta{Name: "ubuntu", Namespace: "default"},
+				Spec:       skynewzdevv1alpha1.TransferSpec{AuthSecretRef: skynewzdevv1alpha1.AuthSecretReference{Name: "putio-token", Key: "token"}},
+				Status:     skynewzdevv1alpha1.TransferStatus{ID: tt.id},
+			}
+
+			if err := r.cancelTransfer(context.Background(), transfer); err != nil {
+				t.Errorf("cancelTransfer() error = %v", err)
+			}
+
+			if got := len(recorder.Events); got != tt.wantEvents {
+				t.Errorf("cancelTransfer() recorded %d events, want %d", got, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func Test_isTransferFinished(t *testing.T) {
+	type args struct {
+		transfer *skynewzdevv1alpha1.Transfer
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "not started",
+			args: args{&skynewzdevv1alpha1.Transfer{}},
+			want: false,
+		},
+		{
+			name: "downloading",
+			args: args{&skynewzdevv1alpha1.Transfer{
+				Status: skynewzdevv1alpha1.TransferStatus{Status: putio.TransferStatusDownloading},
+			}},
+			want: false,
+		},
+		{
+			name: "completed",
+			args: args{&skynewzdevv1alpha1.Transfer{
+				Status: skynewzdevv1alpha1.TransferStatus{Status: putio.TransferStatusCompleted},
+			}},
+			want: true,
+		},
+		{
+			name: "seeding",
+			args: args{&skynewzdevv1alpha1.Transfer{
+				Status: skynewzdevv1alpha1.TransferStatus{Status: putio.TransferStatusSeeding},
+			}},
+			want: true,
+		},
+		{
+			name: "failed with retries left",
+			args: args{&skynewzdevv1alpha1.Transfer{
+				Spec:   skynewzdevv1alpha1.TransferSpec{Retries: 2},
+				Status: skynewzdevv1alpha1.TransferStatus{Status: putio.TransferStatusError, Retried: 1},
+			}},
+			want: false,
+		},
+		{
+			name: "failed without retries left",
+			args: args{&skynewzdevv1alpha1.Transfer{
+				Spec:   skynewzdevv1alpha1.TransferSpec{Retries: 2},
+				Status: skynewzdevv1alpha1.TransferStatus{Status: putio.TransferStatusError, Retried: 2},
+			}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransferFinished(tt.args.transfer); got != tt.want {
+				t.Errorf("isTransferFinished() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -30,7 +30,12 @@ func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 func NewHTTPClient(token string) *http.Client {
 	return &http.Client{Transport: &transport{
-		RoundTripper: otelhttp.NewTransport(nil), // trace requests
-		token:        token,                      // insert token on each requests
+		RoundTripper: &retryTransport{
+			RoundTripper: &metricsTransport{
+				RoundTripper: otelhttp.NewTransport(nil), // trace requests
+			}, // observe each attempt
+			bucket: bucketFor(token), // throttle requests of all clients sharing this token
+		},
+		token: token, // insert token on each requests
 	}}
 }
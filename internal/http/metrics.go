@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "putio_api_requests_total",
+		Help: "Number of requests sent to the Put.io API, by endpoint, method and status code.",
+	}, []string{"endpoint", "method", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "putio_api_request_duration_seconds",
+		Help:    "Latency of the requests sent to the Put.io API, by endpoint and method.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"endpoint", "method"})
+
+	retries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "putio_http_retries_total",
+		Help: "Number of Put.io requests retried, by reason.",
+	}, []string{"reason"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requests, requestDuration, retries)
+}
+
+// metricsTransport counts and times every request sent to Put.io, retries included.
+type metricsTransport struct {
+	http.RoundTripper
+}
+
+// RoundTrip observes given request. Network errors are counted with the "error" code.
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		endpoint = endpointOf(req)
+		start    = time.Now()
+	)
+
+	resp, err := t.RoundTripper.RoundTrip(req)
+	requestDuration.WithLabelValues(endpoint, req.Method).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	requests.WithLabelValues(endpoint, req.Method, code).Inc()
+	return resp, err //nolint:wrapcheck
+}
+
+// endpointOf returns the path of given request with IDs replaced by ":id",
+// to keep the cardinality of the endpoint label low.
+func endpointOf(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, segment := range segments {
+		if _, err := strconv.ParseUint(segment, 10, 64); err == nil {
+			segments[i] = ":id"
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
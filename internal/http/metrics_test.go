@@ -0,0 +1,64 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_endpointOf(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "without ID",
+			url:  "https://api.put.io/v2/rss/list",
+			want: "/v2/rss/list",
+		},
+		{
+			name: "with ID",
+			url:  "https://api.put.io/v2/rss/998868232/pause",
+			want: "/v2/rss/:id/pause",
+		},
+		{
+			name: "with trailing ID and query",
+			url:  "https://api.put.io/v2/files/42?parent_id=0",
+			want: "/v2/files/:id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := endpointOf(httptest.NewRequest(http.MethodGet, tt.url, nil)); got != tt.want {
+				t.Errorf("endpointOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_metricsTransport_RoundTrip(t *testing.T) {
+	const endpoint = "/v2/transfers/:id"
+
+	transport := &metricsTransport{RoundTripper: RoundTripFuncWithError(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodDelete {
+			return nil, errors.New("connection reset by peer")
+		}
+
+		return makeResponse(http.StatusNotFound, nil), nil
+	})}
+
+	_, _ = transport.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.put.io/v2/transfers/1", nil))
+	_, _ = transport.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.put.io/v2/transfers/2", nil))
+	_, _ = transport.RoundTrip(httptest.NewRequest(http.MethodDelete, "https://api.put.io/v2/transfers/1", nil))
+
+	if got := testutil.ToFloat64(requests.WithLabelValues(endpoint, http.MethodGet, "404")); got != 2 {
+		t.Errorf("RoundTrip() counted %v requests, want 2", got)
+	}
+	if got := testutil.ToFloat64(requests.WithLabelValues(endpoint, http.MethodDelete, "error")); got != 1 {
+		t.Errorf("RoundTrip() counted %v failed requests, want 1", got)
+	}
+}
@@ -0,0 +1,319 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// maxRetries is the number of times a request is retried before giving up.
+	maxRetries = 4
+	// baseDelay is the delay before the first retry, doubled on each retry.
+	baseDelay = time.Millisecond * 500
+	// maxDelay caps the delay between two attempts, longer Retry-After are not waited for.
+	maxDelay = time.Minute
+	// bucketIdleTimeout is how long the bucket of a token no request is made with is kept, rotated tokens are forgotten.
+	bucketIdleTimeout = time.Hour
+
+	// Put.io rate-limit headers.
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// retry reasons, used as metric label.
+const (
+	retryReasonRateLimited = "rate_limited"
+	retryReasonServerError = "server_error"
+	retryReasonNetwork     = "network_error"
+)
+
+var (
+	rateLimit rate.Limit = 5  // requests per second allowed for each token
+	burst                = 10 // requests allowed at once for each token
+
+	buckets   = make(map[[sha256.Size]byte]*bucket)
+	bucketsMu sync.Mutex
+)
+
+// SetRateLimit sets how many requests per second, and how many at once, are made with each token.
+// It must be called before any client is made.
+func SetRateLimit(limit float64, b int) {
+	bucketsMu.Lock()
+	defer bucketsMu.Unlock()
+
+	rateLimit, burst = rate.Limit(limit), b
+}
+
+// bucket throttles every request made with the same token, whichever client makes it.
+type bucket struct {
+	limiter *rate.Limiter
+
+	mu           sync.Mutex
+	blockedUntil time.Time // set when Put.io tells the rate limit is exhausted
+	lastUsed     time.Time
+}
+
+// bucketFor returns the bucket shared by every client of given token, and forgets the buckets left idle.
+func bucketFor(token string) *bucket {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+
+	bucketsMu.Lock()
+	defer bucketsMu.Unlock()
+
+	for k, b := range buckets {
+		if k != key && b.idle(now) {
+			delete(buckets, k)
+		}
+	}
+
+	b, ok := buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rateLimit, burst), lastUsed: now}
+		buckets[key] = b
+	}
+
+	return b
+}
+
+// idle tells whether no request has been made with the bucket for bucketIdleTimeout.
+func (b *bucket) idle(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return now.Sub(b.lastUsed) > bucketIdleTimeout && !b.blockedUntil.After(now)
+}
+
+// wait blocks until a request is allowed, sleeping with given function while the bucket is blocked.
+func (b *bucket) wait(ctx context.Context, sleep func(ctx context.Context, d time.Duration) error) error {
+	b.mu.Lock()
+	blockedUntil := b.blockedUntil
+	b.lastUsed = time.Now()
+	b.mu.Unlock()
+
+	if d := time.Until(blockedUntil); d > 0 {
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	return b.limiter.Wait(ctx) //nolint:wrapcheck
+}
+
+// block holds every request until given time.
+func (b *bucket) block(until time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// retryTransport throttles requests per token and retries the ones which failed temporarily.
+type retryTransport struct {
+	http.RoundTripper
+	bucket *bucket
+
+	// sleep waits between two attempts, replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// RoundTrip retries rate-limited requests, and idempotent requests which failed
+// with a server or network error, with a jittered exponential backoff.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		ctx    = req.Context()
+		logger = log.FromContext(ctx, "method", req.Method, "url", req.URL.String()).WithName("http")
+		wait   = t.sleep
+	)
+
+	if wait == nil {
+		wait = sleep
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := t.bucket.wait(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.RoundTripper.RoundTrip(req)
+		if resp != nil {
+			t.observeRateLimit(resp)
+		}
+
+		reason, delay := retryPolicy(req, resp, err, attempt)
+
+		// Put.io refuses every request of the token until then, whether this one is retried or not
+		if reason == retryReasonRateLimited {
+			t.bucket.block(time.Now().Add(delay))
+		}
+
+		if reason == "" || attempt >= maxRetries || delay > maxDelay {
+			return resp, err //nolint:wrapcheck
+		}
+
+		// the body has been consumed, a new one is needed to retry
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err //nolint:wrapcheck
+			}
+
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err //nolint:wrapcheck
+			}
+
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		retries.WithLabelValues(reason).Inc()
+		logger.Info("Retrying Put.io request", "reason", reason, "attempt", attempt+1, "delay", delay.String())
+
+		// the bucket is blocked until rate-limited requests can be retried
+		if reason == retryReasonRateLimited {
+			continue
+		}
+
+		if err := wait(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// observeRateLimit blocks the bucket when Put.io tells the rate limit is exhausted.
+func (t *retryTransport) observeRateLimit(resp *http.Response) {
+	if resp.Header.Get(headerRateLimitRemaining) != "0" {
+		return
+	}
+
+	if reset, ok := parseRateLimitReset(resp.Header); ok {
+		t.bucket.block(reset)
+	}
+}
+
+// retryPolicy returns why given attempt should be retried, empty if it should not, and how long to wait before.
+func retryPolicy(req *http.Request, resp *http.Response, err error, attempt int) (string, time.Duration) {
+	switch {
+	case err != nil:
+		if !isIdempotent(req) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return "", 0
+		}
+
+		return retryReasonNetwork, backoff(attempt)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// the request has not been processed, whatever its method
+		if delay, ok := parseRetryAfter(resp.Header); ok {
+			return retryReasonRateLimited, delay
+		}
+
+		if reset, ok := parseRateLimitReset(resp.Header); ok {
+			return retryReasonRateLimited, time.Until(reset)
+		}
+
+		return retryReasonRateLimited, backoff(attempt)
+	case resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented:
+		if !isIdempotent(req) {
+			return "", 0
+		}
+
+		if delay, ok := parseRetryAfter(resp.Header); ok {
+			return retryReasonServerError, delay
+		}
+
+		return retryReasonServerError, backoff(attempt)
+	default:
+		return "", 0
+	}
+}
+
+// isIdempotent tells whether given request can be sent twice without side effect.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before retrying given attempt: an exponential delay
+// with jitter, between half and all of it, so throttled clients do not retry all at once.
+func backoff(attempt int) time.Duration {
+	delay := baseDelay << attempt
+	if delay > maxDelay || delay <= 0 {
+		delay = maxDelay
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) //nolint:gosec
+}
+
+// parseRetryAfter reads the Retry-After header, given in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// parseRateLimitReset reads when the Put.io rate limit resets, given as a Unix timestamp.
+func parseRateLimitReset(header http.Header) (time.Time, bool) {
+	value := header.Get(headerRateLimitReset)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(seconds, 0), true
+}
+
+// sleep waits for given duration, or until given context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,344 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// makeResponse returns a response with given status code and headers.
+func makeResponse(code int, header map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: code, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(""))}
+	for k, v := range header {
+		resp.Header.Set(k, v)
+	}
+
+	return resp
+}
+
+type roundTripResult struct {
+	resp *http.Response
+	err  error
+}
+
+func Test_retryTransport_RoundTrip(t1 *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		results      []roundTripResult
+		wantStatus   int
+		wantErr      bool
+		wantAttempts int
+		wantDelays   []time.Duration // nil to skip check
+	}{
+		{
+			name:         "successful request",
+			method:       http.MethodGet,
+			results:      []roundTripResult{{resp: makeResponse(http.StatusOK, nil)}},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 1,
+		},
+		{
+			name:   "rate-limited request honours Retry-After",
+			method: http.MethodPost,
+			body:   "foo",
+			results: []roundTripResult{
+				{resp: makeResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "3"})},
+				{resp: makeResponse(http.StatusOK, nil)},
+			},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+			wantDelays:   []time.Duration{time.Second * 3},
+		},
+		{
+			name:   "idempotent request retried on server error",
+			method: http.MethodGet,
+			results: []roundTripResult{
+				{resp: makeResponse(http.StatusBadGateway, nil)},
+				{resp: makeResponse(http.StatusServiceUnavailable, nil)},
+				{resp: makeResponse(http.StatusOK, nil)},
+			},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+		},
+		{
+			name:   "idempotent request retried on network error",
+			method: http.MethodDelete,
+			results: []roundTripResult{
+				{err: errors.New("connection reset by peer")},
+				{resp: makeResponse(http.StatusOK, nil)},
+			},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "non-idempotent request not retried on server error",
+			method:       http.MethodPost,
+			body:         "foo",
+			results:      []roundTripResult{{resp: makeResponse(http.StatusInternalServerError, nil)}},
+			wantStatus:   http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+		{
+			name:         "non-idempotent request not retried on network error",
+			method:       http.MethodPost,
+			results:      []roundTripResult{{err: errors.New("connection reset by peer")}},
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+		{
+			name:         "client error not retried",
+			method:       http.MethodGet,
+			results:      []roundTripResult{{resp: makeResponse(http.StatusNotFound, nil)}},
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+		{
+			name:   "gives up after max retries",
+			method: http.MethodGet,
+			results: []roundTripResult{
+				{resp: makeResponse(http.StatusBadGateway, nil)},
+				{resp: makeResponse(http.StatusBadGateway, nil)},
+				{resp: makeResponse(http.StatusBadGateway, nil)},
+				{resp: makeResponse(http.StatusBadGateway, nil)},
+				{resp: makeResponse(http.StatusBadGateway, nil)},
+			},
+			wantStatus:   http.StatusBadGateway,
+			wantAttempts: maxRetries + 1,
+		},
+		{
+			name:   "does not wait for a too long Retry-After",
+			method: http.MethodGet,
+			results: []roundTripResult{
+				{resp: makeResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "3600"})},
+			},
+			wantStatus:   http.StatusTooManyRequests,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var (
+				attempts int
+				delays   []time.Duration
+			)
+
+			t := &retryTransport{
+				RoundTripper: RoundTripFuncWithError(func(req *http.Request) (*http.Response, error) {
+					if req.Body != nil {
+						if b, _ := io.ReadAll(req.Body); string(b) != tt.body {
+							t1.Errorf("RoundTrip() attempt %d got body %q, want %q", attempts, b, tt.body)
+						}
+					}
+
+					result := tt.results[attempts]
+					attempts++
+					return result.resp, result.err
+				}),
+				bucket: &bucket{limiter: rate.NewLimiter(rate.Inf, 1)},
+				sleep: func(_ context.Context, d time.Duration) error {
+					delays = append(delays, d)
+					return nil
+				},
+			}
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+
+			req, _ := http.NewRequestWithContext(context.Background(), tt.method, "https://api.put.io/v2/rss/list", body)
+			got, err := t.RoundTrip(req)
+			if (err != nil) != tt.wantErr {
+				t1.Errorf("RoundTrip() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if attempts != tt.wantAttempts {
+				t1.Errorf("RoundTrip() attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if err == nil && got.StatusCode != tt.wantStatus {
+				t1.Errorf("RoundTrip() status = %d, want %d", got.StatusCode, tt.wantStatus)
+			}
+			if tt.wantDelays != nil && !equalDurations(delays, tt.wantDelays) {
+				t1.Errorf("RoundTrip() delays = %v, want %v", delays, tt.wantDelays)
+			}
+		})
+	}
+}
+
+func Test_retryTransport_RoundTrip_rateLimitExhausted(t1 *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	b := &bucket{limiter: rate.NewLimiter(rate.Inf, 1)}
+	t := &retryTransport{
+		RoundTripper: RoundTripFunc(func(req *http.Request) *http.Response {
+			return makeResponse(http.StatusOK, map[string]string{
+				headerRateLimitRemaining: "0",
+				headerRateLimitReset:     strconv.FormatInt(reset.Unix(), 10),
+			})
+		}),
+		bucket: b,
+	}
+
+	if _, err := t.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.put.io/v2/rss/list", nil)); err != nil {
+		t1.Fatalf("RoundTrip() error = %v", err)
+	}
+	if !b.blockedUntil.Equal(reset) {
+		t1.Errorf("RoundTrip() blockedUntil = %v, want %v", b.blockedUntil, reset)
+	}
+
+	// next request waits until the reset, the context expires first
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.put.io/v2/rss/list", nil)
+	if _, err := t.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t1.Errorf("RoundTrip() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func Test_retryTransport_RoundTrip_longRetryAfter(t1 *testing.T) {
+	b := &bucket{limiter: rate.NewLimiter(rate.Inf, 1)}
+	t := &retryTransport{
+		RoundTripper: RoundTripFunc(func(req *http.Request) *http.Response {
+			return makeResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "3600"})
+		}),
+		bucket: b,
+	}
+
+	resp, err := t.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.put.io/v2/rss/list", nil))
+	if err != nil {
+		t1.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t1.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, http.StatusTooManyRequests)
+	}
+
+	// the Retry-After is not waited for, but still holds the next requests of the token
+	if until := time.Until(b.blockedUntil); until < time.Minute*59 || until > time.Hour {
+		t1.Errorf("RoundTrip() blocked for %v, want %v", until, time.Hour)
+	}
+}
+
+func Test_bucketFor(t *testing.T) {
+	if bucketFor("foo") != bucketFor("foo") {
+		t.Errorf("bucketFor() returned different buckets for the same token")
+	}
+	if bucketFor("foo") == bucketFor("bar") {
+		t.Errorf("bucketFor() returned the same bucket for different tokens")
+	}
+}
+
+func Test_bucketFor_evictsIdleBuckets(t *testing.T) {
+	idle := bucketFor("rotated")
+	idle.lastUsed = time.Now().Add(-bucketIdleTimeout * 2)
+
+	blocked := bucketFor("blocked")
+	blocked.lastUsed = time.Now().Add(-bucketIdleTimeout * 2)
+	blocked.block(time.Now().Add(time.Hour))
+
+	bucketFor("foo")
+
+	bucketsMu.Lock()
+	_, idleKept := buckets[sha256.Sum256([]byte("rotated"))]
+	_, blockedKept := buckets[sha256.Sum256([]byte("blocked"))]
+	bucketsMu.Unlock()
+
+	if idleKept {
+		t.Errorf("bucketFor() kept the bucket of an idle token")
+	}
+	if !blockedKept {
+		t.Errorf("bucketFor() evicted the bucket of a blocked token")
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{
+			name:   "missing header",
+			value:  "",
+			wantOk: false,
+		},
+		{
+			name:   "seconds",
+			value:  "120",
+			want:   time.Minute * 2,
+			wantOk: true,
+		},
+		{
+			name:   "past date",
+			value:  "Wed, 21 Oct 2015 07:28:00 GMT",
+			want:   0,
+			wantOk: true,
+		},
+		{
+			name:   "invalid value",
+			value:  "soon",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+
+			got, ok := parseRetryAfter(header)
+			if ok != tt.wantOk {
+				t.Errorf("parseRetryAfter() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("parseRetryAfter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_backoff(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		want := baseDelay << attempt
+		if want > maxDelay {
+			want = maxDelay
+		}
+
+		if got := backoff(attempt); got < want/2 || got > want {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", attempt, got, want/2, want)
+		}
+	}
+}
+
+type RoundTripFuncWithError func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripFuncWithError) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// equalDurations compares given durations to the second, as delays waiting for a deadline are measured from now.
+func equalDurations(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Round(time.Second) != b[i].Round(time.Second) {
+			return false
+		}
+	}
+
+	return true
+}
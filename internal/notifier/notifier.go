@@ -0,0 +1,56 @@
+// Package notifier delivers notifications to chat webhooks and mail servers.
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.GetTracerProvider().Tracer("notifier")
+
+var errUnexpectedStatus = errors.New("unexpected status")
+
+// Severity tells how important a Notification is.
+type Severity string
+
+const (
+	SeverityInfo  Severity = "info"
+	SeverityError Severity = "error"
+)
+
+// Notification is a message about an event of a Kubernetes object.
+type Notification struct {
+	// Subject summarizes the notification in a line, used as mail subject.
+	Subject string `json:"subject"`
+	// Message is the notification body.
+	Message string `json:"message"`
+	// Event is the kind of event notified about.
+	Event string `json:"event"`
+	// Severity of the event.
+	Severity Severity `json:"severity"`
+	// Name of the object the event is about.
+	Name string `json:"name"`
+	// Namespace of the object the event is about.
+	Namespace string `json:"namespace"`
+	// Timestamp is when the event happened.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier delivers notifications.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// redactURL only keeps the scheme and host of given URL, as webhook URLs embed tokens in their path.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "REDACTED"
+	}
+
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,145 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// defaultSMTPTimeout bounds the delivery of a mail when the context has no deadline.
+const defaultSMTPTimeout = 30 * time.Second
+
+// SMTPConfig configures the mail server notifications are sent through.
+type SMTPConfig struct {
+	Host string
+	Port int
+	From string
+	To   []string
+
+	// Username and Password authenticate with PLAIN, no authentication when Username is empty.
+	// Credentials are only sent over TLS, or to localhost.
+	Username string
+	Password string
+}
+
+// smtpNotifier mails notifications.
+type smtpNotifier struct {
+	config SMTPConfig
+}
+
+// NewSMTP returns a Notifier mailing notifications through given server.
+// STARTTLS is used when the server supports it.
+func NewSMTP(config SMTPConfig) Notifier {
+	return &smtpNotifier{config: config}
+}
+
+// Notify mails given notification to every recipient.
+func (s *smtpNotifier) Notify(ctx context.Context, n Notification) error {
+	ctx, span := tracer.Start(ctx, "notifier.smtp.Notify")
+	defer span.End()
+
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	span.SetAttributes(attribute.String("notifier.smtp.addr", addr), attribute.String("notifier.event", n.Event))
+
+	if err := s.send(ctx, addr, n); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("notifier: unable to mail notification through %s: %w", addr, err)
+	}
+
+	return nil
+}
+
+// send delivers given notification to the server at given address.
+func (s *smtpNotifier) send(ctx context.Context, addr string, n Notification) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultSMTPTimeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err //nolint:wrapcheck
+	}
+
+	c, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return err //nolint:wrapcheck
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.config.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	if s.config.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	if err := c.Mail(s.config.From); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for _, to := range s.config.To {
+		if err := c.Rcpt(to); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if _, err := w.Write(s.message(n)); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := w.Close(); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return c.Quit() //nolint:wrapcheck
+}
+
+// message returns the mail of given notification, headers included.
+func (s *smtpNotifier) message(n Notification) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("From: " + s.config.From + "\r\n")
+	buf.WriteString("To: " + strings.Join(s.config.To, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", n.Subject) + "\r\n")
+	buf.WriteString("Date: " + n.Timestamp.Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	_, _ = w.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(n.Message, "\r\n", "\n"), "\n", "\r\n")))
+	_ = w.Close()
+
+	return buf.Bytes()
+}
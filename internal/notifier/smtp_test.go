@@ -0,0 +1,173 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// smtpSession is what a fakeSMTPServer received during a session.
+type smtpSession struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer accepts a single session on a local port, offering PLAIN authentication when auth is set.
+func fakeSMTPServer(t *testing.T, auth bool) (host string, port int, sessions <-chan smtpSession) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	ch := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var (
+			session smtpSession
+			r       = textproto.NewReader(bufio.NewReader(conn))
+			reply   = func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+		)
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadLine()
+			if err != nil {
+				return
+			}
+
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				if auth {
+					reply("250-localhost")
+					reply("250 AUTH PLAIN")
+				} else {
+					reply("250 localhost")
+				}
+			case "AUTH":
+				credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+				session.auth = string(credentials)
+				reply("235 2.7.0 Authentication successful")
+			case "MAIL":
+				session.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+				reply("250 OK")
+			case "RCPT":
+				session.to = append(session.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				data, err := r.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				session.data = string(data)
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				ch <- session
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch
+}
+
+func Test_smtpNotifier_Notify(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantAuth string
+	}{
+		{name: "anonymous"},
+		{name: "authenticated", username: "putio-operator", password: "secret", wantAuth: "\x00putio-operator\x00secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port, sessions := fakeSMTPServer(t, tt.username != "")
+
+			n := makeNotification()
+			n.Message = "[default/house-of-the-dragon] feed is unavailable:\n404 Not Found"
+			notifier := NewSMTP(SMTPConfig{
+				Host:     host,
+				Port:     port,
+				From:     "putio-operator@example.com",
+				To:       []string{"team@example.com", "oncall@example.com"},
+				Username: tt.username,
+				Password: tt.password,
+			})
+			if err := notifier.Notify(context.Background(), n); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+
+			session := <-sessions
+			if session.auth != tt.wantAuth {
+				t.Errorf("Notify() authenticated with %q, want %q", session.auth, tt.wantAuth)
+			}
+			if session.from != "putio-operator@example.com" {
+				t.Errorf("Notify() sent from %q", session.from)
+			}
+			if diff := cmp.Diff([]string{"team@example.com", "oncall@example.com"}, session.to); diff != "" {
+				t.Errorf("Notify() recipients mismatch (-want +got):\n%s", diff)
+			}
+
+			msg, err := mail.ReadMessage(strings.NewReader(session.data))
+			if err != nil {
+				t.Fatalf("cannot parse mail: %v", err)
+			}
+			if got := msg.Header.Get("Subject"); got != n.Subject {
+				t.Errorf("Notify() subject = %q, want %q", got, n.Subject)
+			}
+			if got := msg.Header.Get("To"); got != "team@example.com, oncall@example.com" {
+				t.Errorf("Notify() To header = %q", got)
+			}
+
+			body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+			if err != nil {
+				t.Fatalf("cannot read mail body: %v", err)
+			}
+			// the stand-in reads the mail with LF line endings
+			if got, want := string(body), n.Message+"\n"; got != want {
+				t.Errorf("Notify() body = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func Test_smtpNotifier_Notify_unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	notifier := NewSMTP(SMTPConfig{Host: "127.0.0.1", Port: port, From: "a@example.com", To: []string{"b@example.com"}})
+	if err := notifier.Notify(context.Background(), makeNotification()); err == nil || !strings.Contains(err.Error(), strconv.Itoa(port)) {
+		t.Errorf("Notify() error = %v, want a connection error", err)
+	}
+}
@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxDiscordContent is the maximum number of characters of a Discord message.
+const maxDiscordContent = 2000
+
+// webhook posts notifications as JSON to a URL.
+type webhook struct {
+	client  *http.Client
+	url     string
+	payload func(n Notification) any
+}
+
+// slackPayload is understood by Slack incoming webhooks and the Slack-compatible ones of Mattermost or Rocket.Chat.
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// discordPayload is understood by Discord webhooks.
+type discordPayload struct {
+	Content string `json:"content"`
+}
+
+// NewGeneric returns a Notifier posting the whole Notification as JSON to given URL.
+func NewGeneric(client *http.Client, url string) Notifier {
+	return &webhook{client: client, url: url, payload: func(n Notification) any { return n }}
+}
+
+// NewSlack returns a Notifier posting the notification message to given Slack-compatible webhook URL.
+func NewSlack(client *http.Client, url string) Notifier {
+	return &webhook{client: client, url: url, payload: func(n Notification) any {
+		return slackPayload{Text: n.Message}
+	}}
+}
+
+// NewDiscord returns a Notifier posting the notification message to given Discord-compatible webhook URL.
+// Messages are truncated to the length Discord accepts.
+func NewDiscord(client *http.Client, url string) Notifier {
+	return &webhook{client: client, url: url, payload: func(n Notification) any {
+		return discordPayload{Content: truncate(n.Message, maxDiscordContent)}
+	}}
+}
+
+// Notify posts given notification. The webhook URL is redacted from the returned errors and the trace.
+func (w *webhook) Notify(ctx context.Context, n Notification) error {
+	ctx, span := tracer.Start(ctx, "notifier.webhook.Notify")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("notifier.url", redactURL(w.url)), attribute.String("notifier.event", n.Event))
+
+	body, err := json.Marshal(w.payload(n))
+	if err != nil {
+		return fmt.Errorf("notifier: unable to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notifier: invalid webhook URL %s: %w", redactURL(w.url), redactError(err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		err = redactError(err)
+		span.RecordError(err)
+		return fmt.Errorf("notifier: unable to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("notifier: %w %s from %s", errUnexpectedStatus, resp.Status, redactURL(w.url))
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// redactError redacts the URL of given *url.Error, returned by the HTTP client.
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+
+	return err
+}
+
+// truncate shortens given string to limit characters, ending it with an ellipsis when shortened.
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+
+	return string(runes[:limit-1]) + "…"
+}
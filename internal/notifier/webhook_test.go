@@ -0,0 +1,130 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func makeNotification() Notification {
+	return Notification{
+		Subject:   "Feed default/house-of-the-dragon is unavailable",
+		Message:   "[default/house-of-the-dragon] feed is unavailable: 404 Not Found",
+		Event:     "Availability",
+		Severity:  SeverityError,
+		Name:      "house-of-the-dragon",
+		Namespace: "default",
+		Timestamp: time.Date(2022, 10, 16, 21, 0, 0, 0, time.UTC),
+	}
+}
+
+func Test_webhook_Notify(t *testing.T) {
+	tests := []struct {
+		name        string
+		newNotifier func(client *http.Client, url string) Notifier
+		message     string
+		want        map[string]any
+	}{
+		{
+			name:        "generic",
+			newNotifier: NewGeneric,
+			want: map[string]any{
+				"subject":   "Feed default/house-of-the-dragon is unavailable",
+				"message":   "[default/house-of-the-dragon] feed is unavailable: 404 Not Found",
+				"event":     "Availability",
+				"severity":  "error",
+				"name":      "house-of-the-dragon",
+				"namespace": "default",
+				"timestamp": "2022-10-16T21:00:00Z",
+			},
+		},
+		{
+			name:        "slack",
+			newNotifier: NewSlack,
+			want:        map[string]any{"text": "[default/house-of-the-dragon] feed is unavailable: 404 Not Found"},
+		},
+		{
+			name:        "discord",
+			newNotifier: NewDiscord,
+			want:        map[string]any{"content": "[default/house-of-the-dragon] feed is unavailable: 404 Not Found"},
+		},
+		{
+			name:        "discord message too long",
+			newNotifier: NewDiscord,
+			message:     strings.Repeat("a", 2500),
+			want:        map[string]any{"content": strings.Repeat("a", 1999) + "…"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("unexpected request %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("cannot decode payload: %v", err)
+				}
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			n := makeNotification()
+			if tt.message != "" {
+				n.Message = tt.message
+			}
+
+			if err := tt.newNotifier(server.Client(), server.URL+"/hooks/secret-token").Notify(context.Background(), n); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Notify() payload mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_webhook_Notify_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewSlack(server.Client(), server.URL+"/hooks/secret-token").Notify(context.Background(), makeNotification())
+	if err == nil {
+		t.Fatal("Notify() error = nil, want an error")
+	}
+
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Notify() error = %v, want the webhook URL redacted", err)
+	}
+}
+
+func Test_redactURL(t *testing.T) {
+	tests := []struct {
+		rawURL string
+		want   string
+	}{
+		{rawURL: "https://hooks.slack.com/services/T000/B000/XXXX", want: "https://hooks.slack.com"},
+		{rawURL: "https://discord.com/api/webhooks/123/token?wait=true", want: "https://discord.com"},
+		{rawURL: "not a url", want: "REDACTED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rawURL, func(t *testing.T) {
+			if got := redactURL(tt.rawURL); got != tt.want {
+				t.Errorf("redactURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package putio
 import (
 	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/putdotio/go-putio"
 )
@@ -29,3 +30,10 @@ func IsNotFound(err error) bool {
 	var e *putio.ErrorResponse
 	return errors.As(err, &e) && e.Type == notFound
 }
+
+// IsUnauthorized check whether given error is due to a missing, invalid or revoked token.
+func IsUnauthorized(err error) bool {
+	var e *putio.ErrorResponse
+	return errors.As(err, &e) && e.Response != nil &&
+		(e.Response.StatusCode == http.StatusUnauthorized || e.Response.StatusCode == http.StatusForbidden)
+}
@@ -2,6 +2,7 @@ package putio
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/putdotio/go-putio"
@@ -35,3 +36,42 @@ func TestIsNotFound(t *testing.T) {
 		})
 	}
 }
+
+func TestIsUnauthorized(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "is unauthorized error",
+			args: args{fmt.Errorf("putio: response error: %w", &putio.ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnauthorized}})},
+			want: true,
+		},
+		{
+			name: "is forbidden error",
+			args: args{&putio.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}}},
+			want: true,
+		},
+		{
+			name: "is not unauthorized error",
+			args: args{&putio.ErrorResponse{Response: &http.Response{StatusCode: http.StatusInternalServerError}}},
+			want: false,
+		},
+		{
+			name: "is not a Put.io error",
+			args: args{fmt.Errorf("Unauthorized")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnauthorized(tt.args.err); got != tt.want {
+				t.Errorf("IsUnauthorized() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -47,3 +47,12 @@ type Feed struct {
 	StartAt         Time   `json:"start_at"`
 	UpdatedAt       Time   `json:"updated_at"`
 }
+
+// FeedItem is an item of an RSS feed processed by Put.io.
+type FeedItem struct {
+	ID            uint   `json:"id"`
+	Title         string `json:"title"`
+	IsFailed      bool   `json:"is_failed"`
+	FailureReason string `json:"failure_reason"`
+	ProcessedAt   Time   `json:"processed_at"`
+}
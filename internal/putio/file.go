@@ -0,0 +1,23 @@
+package putio
+
+const directoryContentType string = "application/x-directory"
+
+type File struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	ParentID    uint   `json:"parent_id"`
+	FileType    string `json:"file_type"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+
+	// IsShared is set on files shared with the account by a friend, which the account does not own.
+	IsShared bool `json:"is_shared"`
+
+	CreatedAt Time `json:"created_at"`
+	UpdatedAt Time `json:"updated_at"`
+}
+
+// IsDir reports whether the file is a directory.
+func (f *File) IsDir() bool {
+	return f.ContentType == directoryContentType
+}
@@ -0,0 +1,168 @@
+package putio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// filesPerPage is the page size used when listing a folder.
+const filesPerPage = 1000
+
+type filesService struct {
+	client *Client
+}
+
+// List every file of given folder, following pagination.
+func (s *filesService) List(ctx context.Context, parentID uint) ([]*File, error) {
+	ctx, span := s.client.tracer.Start(ctx, "putio.filesService.List")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("parent_id", int(parentID)))
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, fmt.Sprintf("/v2/files/list?parent_id=%d&per_page=%d", parentID, filesPerPage), nil)
+	if err != nil {
+		return nil, fmt.Errorf("putio: cannot make request: %w", err)
+	}
+
+	var r struct {
+		Files  []*File `json:"files"`
+		Cursor string  `json:"cursor"`
+	}
+	_, err = s.client.Do(req, &r) //nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("putio: response error: %w", err)
+	}
+
+	files := r.Files
+	for r.Cursor != "" {
+		body, err := json.Marshal(map[string]string{"cursor": r.Cursor})
+		if err != nil {
+			return nil, fmt.Errorf("putio: cannot encode cursor: %w", err)
+		}
+
+		req, err := s.client.NewRequest(ctx, http.MethodPost, "/v2/files/list/continue", strings.NewReader(string(body)))
+		if err != nil {
+			return nil, fmt.Errorf("putio: cannot make request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		r.Files, r.Cursor = nil, ""
+		if _, err := s.client.Do(req, &r); err != nil { //nolint:bodyclose
+			return nil, fmt.Errorf("putio: response error: %w", err)
+		}
+
+		files = append(files, r.Files...)
+	}
+
+	span.SetAttributes(attribute.Int("count", len(files)))
+	return files, nil
+}
+
+// Get a file.
+func (s *filesService) Get(ctx context.Context, id uint) (*File, error) {
+	ctx, span := s.client.tracer.Start(ctx, "putio.filesService.Get")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("id", int(id)))
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "/v2/files/"+strconv.Itoa(int(id)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("putio: cannot make request: %w", err)
+	}
+
+	var r struct {
+		File *File `json:"file"`
+	}
+	_, err = s.client.Do(req, &r) //nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("putio: response error: %w", err)
+	}
+
+	return r.File, nil
+}
+
+// CreateFolder creates a folder named name into given parent folder.
+func (s *filesService) CreateFolder(ctx context.Context, name string, parentID uint) (*File, error) {
+	ctx, span := s.client.tracer.Start(ctx, "putio.filesService.CreateFolder")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("name", name), attribute.Int("parent_id", int(parentID)))
+
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("parent_id", strconv.Itoa(int(parentID)))
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, "/v2/files/create-folder", strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("putio: cannot make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var r struct {
+		File *File `json:"file"`
+	}
+	_, err = s.client.Do(req, &r) //nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("putio: response error: %w", err)
+	}
+
+	return r.File, nil
+}
+
+// Delete files permanently, skipping the trash.
+func (s *filesService) Delete(ctx context.Context, ids []uint) error {
+	ctx, span := s.client.tracer.Start(ctx, "putio.filesService.Delete")
+	defer span.End()
+
+	return s.delete(ctx, ids, true)
+}
+
+// Trash moves files to the trash, from where they can be restored until it is emptied.
+func (s *filesService) Trash(ctx context.Context, ids []uint) error {
+	ctx, span := s.client.tracer.Start(ctx, "putio.filesService.Trash")
+	defer span.End()
+
+	return s.delete(ctx, ids, false)
+}
+
+func (s *filesService) delete(ctx context.Context, ids []uint, skipTrash bool) error {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("count", len(ids)), attribute.Bool("skip_trash", skipTrash))
+
+	fileIDs := make([]string, len(ids))
+	for i, id := range ids {
+		fileIDs[i] = strconv.Itoa(int(id))
+	}
+
+	params := url.Values{}
+	params.Set("file_ids", strings.Join(fileIDs, ","))
+	params.Set("skip_trash", strconv.FormatBool(skipTrash))
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, "/v2/files/delete", strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("putio: cannot make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var r struct {
+		Status string `json:"status"`
+	}
+
+	if _, err := s.client.Do(req, &r); err != nil { //nolint:bodyclose
+		return fmt.Errorf("putio: response error: %w", err)
+	}
+
+	if r.Status != "OK" {
+		return newErrInvalidStatusReceived(r.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package putio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/putdotio/go-putio"
+	"go.opentelemetry.io/otel"
+)
+
+func Test_filesService_List(t *testing.T) {
+	type fields struct {
+		client *Client
+	}
+	type args struct {
+		ctx      context.Context
+		parentID uint
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*File
+		wantErr bool
+	}{
+		{
+			name: "expected with pagination",
+			fields: fields{
+				client: &Client{
+					Client: putio.NewClient(NewTestClient(t, func(req *http.Request) *http.Response {
+						golden := "files_list"
+						if req.URL.Path == "/v2/files/list/continue" {
+							golden = "files_list_continue"
+						}
+
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       readGoldenFile(t, golden),
+							Header:     make(http.Header),
+						}
+					})),
+					Files:  nil, // currently tested
+					tracer: otel.GetTracerProvider().Tracer("putio-testing"),
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				parentID: 0,
+			},
+			want: []*File{
+				{
+					ID:          998868232,
+					Name:        "For all mankind",
+					ParentID:    0,
+					FileType:    "FOLDER",
+					ContentType: "application/x-directory",
+					Size:        12884901888,
+					CreatedAt:   Time{time.Date(2022, time.June, 13, 0, 1, 52, 0, time.UTC)},
+					UpdatedAt:   Time{time.Date(2022, time.September, 11, 19, 46, 39, 0, time.UTC)},
+				},
+				{
+					ID:          1022542821,
+					Name:        "House.of.the.Dragon.S01E04.MULTi.1080p.WEB.H264-FW.mkv",
+					ParentID:    0,
+					FileType:    "VIDEO",
+					ContentType: "video/x-matroska",
+					Size:        2556000000,
+					CreatedAt:   Time{time.Date(2022, time.September, 11, 19, 46, 39, 0, time.UTC)},
+					UpdatedAt:   Time{time.Date(2022, time.September, 11, 19, 46, 39, 0, time.UTC)},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &filesService{
+				client: tt.fields.client,
+			}
+			got, err := s.List(tt.args.ctx, tt.args.parentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("List() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_filesService_delete(t *testing.T) {
+	tests := []struct {
+		name          string
+		skipTrash     bool
+		status        string
+		wantSkipTrash string
+		wantErr       bool
+	}{
+		{
+			name:          "delete",
+			skipTrash:     true,
+			status:        "OK",
+			wantSkipTrash: "true",
+			wantErr:       false,
+		},
+		{
+			name:          "trash",
+			skipTrash:     false,
+			status:        "OK",
+			wantSkipTrash: "false",
+			wantErr:       false,
+		},
+		{
+			name:          "unexpected status",
+			skipTrash:     true,
+			status:        "ERROR",
+			wantSkipTrash: "true",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &filesService{client: &Client{
+				Client: putio.NewClient(NewTestClient(t, func(req *http.Request) *http.Response {
+					if err := req.ParseForm(); err != nil {
+						t.Fatal(err)
+					}
+
+					if got := req.PostForm.Get("file_ids"); got != "12,34" {
+						t.Errorf("file_ids = %q, want %q", got, "12,34")
+					}
+
+					if got := req.PostForm.Get("skip_trash"); got != tt.wantSkipTrash {
+						t.Errorf("skip_trash = %q, want %q", got, tt.wantSkipTrash)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"` + tt.status + `"}`)),
+						Header:     make(http.Header),
+					}
+				})),
+				tracer: otel.GetTracerProvider().Tracer("putio-testing"),
+			}}
+
+			remove := s.Trash
+			if tt.skipTrash {
+				remove = s.Delete
+			}
+
+			if err := remove(context.Background(), []uint{12, 34}); (err != nil) != tt.wantErr {
+				t.Errorf("delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
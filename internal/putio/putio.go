@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/putdotio/go-putio"
 	"go.opentelemetry.io/otel"
@@ -18,8 +19,34 @@ import (
 // Add missing features like RSS management.
 type Client struct {
 	*putio.Client
-	Rss    RssService
-	tracer trace.Tracer
+	Rss       RssService
+	Transfers TransfersService
+	Files     FilesService
+	tracer    trace.Tracer
+}
+
+var (
+	baseURL   *url.URL // Put.io API URL, go-putio default when nil
+	baseURLMu sync.RWMutex
+)
+
+// SetBaseURL sets the Put.io API URL the clients made afterwards send requests to,
+// to use a fake Put.io API such as putiotest.
+func SetBaseURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("putio: invalid base URL: %w", err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("putio: invalid base URL %q: scheme and host are required", rawURL)
+	}
+
+	baseURLMu.Lock()
+	defer baseURLMu.Unlock()
+
+	baseURL = u
+	return nil
 }
 
 func New(ctx context.Context, httpClient *http.Client) *Client {
@@ -29,8 +56,18 @@ func New(ctx context.Context, httpClient *http.Client) *Client {
 	defer span.End()
 
 	client := putio.NewClient(httpClient)
+
+	baseURLMu.RLock()
+	if baseURL != nil {
+		u := *baseURL
+		client.BaseURL = &u
+	}
+	baseURLMu.RUnlock()
+
 	c := &Client{Client: client, tracer: tracer}
 	c.Rss = &rssService{c}
+	c.Transfers = &transfersService{c}
+	c.Files = &filesService{c}
 	return c
 }
 
@@ -82,6 +119,29 @@ func (s *rssService) Get(ctx context.Context, id uint) (*Feed, error) {
 	return r.Feed, nil
 }
 
+// Items lists the items of an RSS feed processed by Put.io.
+func (s *rssService) Items(ctx context.Context, id uint) ([]*FeedItem, error) {
+	ctx, span := s.client.tracer.Start(ctx, "putio.rssService.Items")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("id", int(id)))
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, fmt.Sprintf("/v2/rss/%d/items", id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("putio: cannot make request: %w", err)
+	}
+
+	var r struct {
+		Items []*FeedItem `json:"items"`
+	}
+	_, err = s.client.Do(req, &r) //nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("putio: response error: %w", err)
+	}
+
+	return r.Items, nil
+}
+
 // Delete a RSS feed.
 func (s *rssService) Delete(ctx context.Context, id uint) error {
 	ctx, span := s.client.tracer.Start(ctx, "putio.rssService.Delete")
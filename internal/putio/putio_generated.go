@@ -12,6 +12,8 @@ type RssService interface {
 	List(ctx context.Context) ([]*Feed, error)
 	// Get an RSS feed.
 	Get(ctx context.Context, id uint) (*Feed, error)
+	// Items lists the items of an RSS feed processed by Put.io.
+	Items(ctx context.Context, id uint) ([]*FeedItem, error)
 	// Delete a RSS feed.
 	Delete(ctx context.Context, id uint) error
 	// Create an RSS feed.
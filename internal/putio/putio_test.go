@@ -187,3 +187,47 @@ func Test_rssService_Get(t *testing.T) {
 		})
 	}
 }
+
+func Test_rssService_Items(t *testing.T) {
+	var gotPath string
+	s := &rssService{
+		client: &Client{
+			Client: putio.NewClient(NewTestClient(t, func(req *http.Request) *http.Response {
+				gotPath = req.URL.Path
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       readGoldenFile(t, "items"),
+					Header:     make(http.Header),
+				}
+			})),
+			tracer: otel.GetTracerProvider().Tracer("putio-testing"),
+		},
+	}
+
+	got, err := s.Items(context.Background(), 125559)
+	if err != nil {
+		t.Fatalf("Items() error = %v", err)
+	}
+
+	if gotPath != "/v2/rss/125559/items" {
+		t.Errorf("Items() requested %q, want /v2/rss/125559/items", gotPath)
+	}
+
+	want := []*FeedItem{
+		{
+			ID:          4207,
+			Title:       "For.All.Mankind.S03E10.FRENCH.2160p.WEB.FRATERNITY",
+			ProcessedAt: Time{time.Date(2022, time.September, 11, 19, 46, 39, 0, time.UTC)},
+		},
+		{
+			ID:            4101,
+			Title:         "For.All.Mankind.S03E09.FRENCH.2160p.WEB.FRATERNITY",
+			IsFailed:      true,
+			FailureReason: "Torrent is not valid",
+			ProcessedAt:   Time{time.Date(2022, time.September, 4, 19, 41, 2, 0, time.UTC)},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Items() mismatch (-want +got):\n%s", diff)
+	}
+}
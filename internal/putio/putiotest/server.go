@@ -0,0 +1,883 @@
+// Package putiotest provides a stateful fake of the Put.io API, to run the operator against in tests
+// and during local development.
+package putiotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+)
+
+// timeLayout is the layout of the times returned by Put.io.
+const timeLayout = "2006-01-02T15:04:05"
+
+// DefaultDiskSize is the disk size of the fake account, 1 TiB.
+const DefaultDiskSize int64 = 1 << 40
+
+// Fake is an in-memory Put.io API serving the RSS feeds, files, transfers and account info endpoints.
+// It is safe for concurrent use.
+type Fake struct {
+	mu sync.Mutex
+
+	token     string
+	username  string
+	diskSize  int64
+	nextID    uint
+	now       func() time.Time
+	feeds     map[uint]*putio.Feed
+	feedItems map[uint][]*putio.FeedItem
+	files     map[uint]*putio.File
+	transfers map[uint]*putio.Transfer
+	trash     map[uint]*putio.File
+	faults    []fault
+	requests  int
+}
+
+// fault is an error answered instead of handling the next matching requests.
+type fault struct {
+	path       string // path prefix, every request when empty
+	status     int
+	remaining  int
+	retryAfter time.Duration
+}
+
+// Option configures a Fake.
+type Option func(*Fake)
+
+// WithToken requires requests to be authenticated with given token. Any token is accepted by default.
+func WithToken(token string) Option {
+	return func(f *Fake) {
+		f.token = token
+	}
+}
+
+// WithUsername sets the username of the account.
+func WithUsername(username string) Option {
+	return func(f *Fake) {
+		f.username = username
+	}
+}
+
+// WithDiskSize sets the disk size of the account, in bytes.
+func WithDiskSize(size int64) Option {
+	return func(f *Fake) {
+		f.diskSize = size
+	}
+}
+
+// New returns an empty Fake, holding only the root directory.
+func New(opts ...Option) *Fake {
+	f := &Fake{
+		username:  "putiotest",
+		diskSize:  DefaultDiskSize,
+		nextID:    1,
+		now:       time.Now,
+		feeds:     make(map[uint]*putio.Feed),
+		feedItems: make(map[uint][]*putio.FeedItem),
+		files:     map[uint]*putio.File{0: {ID: 0, Name: "Your Files", ContentType: "application/x-directory", FileType: "FOLDER"}},
+		transfers: make(map[uint]*putio.Transfer),
+		trash:     make(map[uint]*putio.File),
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+// NewServer starts a test server serving a new Fake. Point the Put.io client to its URL with putio.SetBaseURL.
+func NewServer(opts ...Option) (*httptest.Server, *Fake) {
+	f := New(opts...)
+	return httptest.NewServer(f), f
+}
+
+// FailNext answers the next times requests whose path starts with path, every path when empty,
+// with given status code instead of handling them.
+func (f *Fake) FailNext(path string, status, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = append(f.faults, fault{path: path, status: status, remaining: times})
+}
+
+// RateLimitNext answers the next times requests with 429 Too Many Requests, asking to retry after given delay.
+func (f *Fake) RateLimitNext(times int, retryAfter time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = append(f.faults, fault{status: http.StatusTooManyRequests, remaining: times, retryAfter: retryAfter})
+}
+
+// Requests returns the number of requests received, including the failed ones.
+func (f *Fake) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests
+}
+
+// AddFeed stores a copy of given feed, assigning it an ID, and returns the ID.
+func (f *Fake) AddFeed(feed putio.Feed) uint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.newID()
+	feed.ID = &id
+	f.feeds[id] = &feed
+	return id
+}
+
+// Feed returns a copy of the feed with given ID, false when there is none.
+func (f *Fake) Feed(id uint) (putio.Feed, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	feed, ok := f.feeds[id]
+	if !ok {
+		return putio.Feed{}, false
+	}
+
+	return *feed, true
+}
+
+// Feeds returns a copy of every feed, sorted by ID.
+func (f *Fake) Feeds() []putio.Feed {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	feeds := make([]putio.Feed, 0, len(f.feeds))
+	for _, id := range sortedIDs(f.feeds) {
+		feeds = append(feeds, *f.feeds[id])
+	}
+
+	return feeds
+}
+
+// SetFeedError sets the last error Put.io reports for the feed with given ID.
+func (f *Fake) SetFeedError(id uint, lastError string, failedItemCount uint) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if feed, ok := f.feeds[id]; ok {
+		feed.LastError = lastError
+		feed.FailedItemCount = failedItemCount
+	}
+}
+
+// AddFeedItem records an item processed for the feed with given ID, failed or not, and returns its ID.
+func (f *Fake) AddFeedItem(feedID uint, title string, failed bool) uint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.newID()
+	item := &putio.FeedItem{ID: id, Title: title, IsFailed: failed, ProcessedAt: putio.Time{Time: f.now().UTC()}}
+	if failed {
+		item.FailureReason = "Transfer failed"
+	}
+
+	// the most recent first
+	f.feedItems[feedID] = append([]*putio.FeedItem{item}, f.feedItems[feedID]...)
+	return id
+}
+
+// AddFile stores a copy of given file, assigning it an ID, and returns the ID.
+func (f *Fake) AddFile(file putio.File) uint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.newID()
+	file.ID = id
+	if file.FileType == "" {
+		file.FileType = "FILE"
+	}
+
+	f.files[id] = &file
+	return id
+}
+
+// AddFolder creates a folder named name in given parent folder and returns its ID.
+func (f *Fake) AddFolder(name string, parentID uint) uint {
+	return f.AddFile(putio.File{Name: name, ParentID: parentID, ContentType: "application/x-directory", FileType: "FOLDER"})
+}
+
+// File returns a copy of the file with given ID, false when there is none.
+func (f *Fake) File(id uint) (putio.File, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, ok := f.files[id]
+	if !ok {
+		return putio.File{}, false
+	}
+
+	return *file, true
+}
+
+// Files returns a copy of every file but the root directory, sorted by ID.
+func (f *Fake) Files() []putio.File {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	files := make([]putio.File, 0, len(f.files))
+	for _, id := range sortedIDs(f.files) {
+		if id != 0 {
+			files = append(files, *f.files[id])
+		}
+	}
+
+	return files
+}
+
+// Trashed returns a copy of every file moved to the trash, sorted by ID.
+func (f *Fake) Trashed() []putio.File {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	files := make([]putio.File, 0, len(f.trash))
+	for _, id := range sortedIDs(f.trash) {
+		files = append(files, *f.trash[id])
+	}
+
+	return files
+}
+
+// Transfer returns a copy of the transfer with given ID, false when there is none.
+func (f *Fake) Transfer(id uint) (putio.Transfer, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	transfer, ok := f.transfers[id]
+	if !ok {
+		return putio.Transfer{}, false
+	}
+
+	return *transfer, true
+}
+
+// SetTransferStatus moves the transfer with given ID to given status. A completed transfer gets a file
+// of given size in its save folder.
+func (f *Fake) SetTransferStatus(id uint, status, errorMessage string, size int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	transfer, ok := f.transfers[id]
+	if !ok {
+		return
+	}
+
+	transfer.Status = status
+	transfer.ErrorMessage = errorMessage
+	transfer.Size = size
+	switch status {
+	case putio.TransferStatusCompleted, putio.TransferStatusSeeding:
+		transfer.PercentDone = 100
+		transfer.Downloaded = size
+		transfer.FinishedAt = putio.Time{Time: f.now().UTC()}
+		if transfer.FileID == nil {
+			fileID := f.newID()
+			f.files[fileID] = &putio.File{ID: fileID, Name: transfer.Name, ParentID: transfer.SaveParentID, Size: size, FileType: "FILE"}
+			transfer.FileID = &fileID
+		}
+	case putio.TransferStatusError:
+		transfer.FinishedAt = putio.Time{Time: f.now().UTC()}
+	}
+}
+
+// diskUsed sums the size of the stored files.
+func (f *Fake) diskUsed() int64 {
+	var used int64
+	for _, file := range f.files {
+		if file.ContentType != "application/x-directory" {
+			used += file.Size
+		}
+	}
+
+	return used
+}
+
+func (f *Fake) newID() uint {
+	id := f.nextID
+	f.nextID++
+	return id
+}
+
+func sortedIDs[T any](m map[uint]T) []uint {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// ServeHTTP implements http.Handler.
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+
+	if f.token != "" && r.Header.Get("Authorization") != "Bearer "+f.token {
+		writeError(w, http.StatusUnauthorized, "invalid_grant", "Invalid or expired token")
+		return
+	}
+
+	if f.injectFault(w, r) {
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "v2" {
+		writeError(w, http.StatusNotFound, "NotFound", "Not found")
+		return
+	}
+
+	switch segments[1] {
+	case "account":
+		f.serveAccount(w, r, segments[2:])
+	case "rss":
+		f.serveRss(w, r, segments[2:])
+	case "files":
+		f.serveFiles(w, r, segments[2:])
+	case "transfers":
+		f.serveTransfers(w, r, segments[2:])
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "Not found")
+	}
+}
+
+// injectFault answers the request with the first matching fault, telling whether it did.
+func (f *Fake) injectFault(w http.ResponseWriter, r *http.Request) bool {
+	for i := range f.faults {
+		fault := &f.faults[i]
+		if fault.remaining == 0 || !strings.HasPrefix(r.URL.Path, fault.path) {
+			continue
+		}
+
+		fault.remaining--
+		if fault.status == http.StatusTooManyRequests {
+			retryAfter := int(fault.retryAfter.Seconds())
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(f.now().Add(fault.retryAfter).Unix(), 10))
+			writeError(w, fault.status, "RateLimited", "Too many requests")
+			return true
+		}
+
+		writeError(w, fault.status, strings.ReplaceAll(http.StatusText(fault.status), " ", ""), "Injected failure")
+		return true
+	}
+
+	return false
+}
+
+func (f *Fake) serveAccount(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method != http.MethodGet || len(segments) != 1 || segments[0] != "info" {
+		writeError(w, http.StatusNotFound, "NotFound", "Not found")
+		return
+	}
+
+	used := f.diskUsed()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"info": map[string]interface{}{
+			"account_active": true,
+			"username":       f.username,
+			"mail":           f.username + "@example.com",
+			"disk": map[string]int64{
+				"size":  f.diskSize,
+				"used":  used,
+				"avail": f.diskSize - used,
+			},
+		},
+	})
+}
+
+func (f *Fake) serveRss(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 1 && segments[0] == "list" && r.Method == http.MethodGet:
+		feeds := make([]interface{}, 0, len(f.feeds))
+		for _, id := range sortedIDs(f.feeds) {
+			feeds = append(feeds, marshalFeed(f.feeds[id]))
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"feeds": feeds})
+	case len(segments) == 1 && segments[0] == "create" && r.Method == http.MethodPost:
+		feed := new(putio.Feed)
+		if !readFeedForm(w, r, feed) {
+			return
+		}
+
+		id := f.newID()
+		now := putio.Time{Time: f.now().UTC()}
+		feed.ID = &id
+		feed.CreatedAt, feed.UpdatedAt, feed.StartAt = now, now, now
+		f.feeds[id] = feed
+		writeJSON(w, http.StatusOK, map[string]interface{}{"feed": marshalFeed(feed)})
+	case len(segments) >= 1:
+		id, err := strconv.ParseUint(segments[0], 10, 0)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "NotFound", "Not found")
+			return
+		}
+
+		feed, ok := f.feeds[uint(id)]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NotFound", "RSS feed not found")
+			return
+		}
+
+		f.serveFeed(w, r, feed, segments[1:])
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "Not found")
+	}
+}
+
+func (f *Fake) serveFeed(w http.ResponseWriter, r *http.Request, feed *putio.Feed, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"feed": marshalFeed(feed)})
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		updated := *feed
+		if !readFeedForm(w, r, &updated) {
+			return
+		}
+
+		updated.UpdatedAt = putio.Time{Time: f.now().UTC()}
+		*feed = updated
+		writeJSON(w, http.StatusOK, nil)
+	case len(segments) == 1 && segments[0] == "items" && r.Method == http.MethodGet:
+		items := make([]interface{}, 0, len(f.feedItems[*feed.ID]))
+		for _, item := range f.feedItems[*feed.ID] {
+			items = append(items, marshalFeedItem(item))
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+	case len(segments) == 1 && segments[0] == "delete" && r.Method == http.MethodPost:
+		delete(f.feeds, *feed.ID)
+		delete(f.feedItems, *feed.ID)
+		writeJSON(w, http.StatusOK, nil)
+	case len(segments) == 1 && segments[0] == "pause" && r.Method == http.MethodPost:
+		if !feed.Paused {
+			feed.Paused = true
+			feed.PausedAt = putio.Time{Time: f.now().UTC()}
+		}
+
+		writeJSON(w, http.StatusOK, nil)
+	case len(segments) == 1 && segments[0] == "resume" && r.Method == http.MethodPost:
+		feed.Paused = false
+		feed.PausedAt = putio.Time{}
+		writeJSON(w, http.StatusOK, nil)
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "Not found")
+	}
+}
+
+// readFeedForm reads the fields of a feed from the form of given request, answering an error when invalid.
+func readFeedForm(w http.ResponseWriter, r *http.Request, feed *putio.Feed) bool {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidForm", err.Error())
+		return false
+	}
+
+	for _, required := range []string{"title", "rss_source_url", "keyword"} {
+		if r.PostForm.Get(required) == "" {
+			writeError(w, http.StatusBadRequest, "MissingParameter", fmt.Sprintf("%s is required", required))
+			return false
+		}
+	}
+
+	parentDirID, err := strconv.ParseUint(r.PostForm.Get("parent_dir_id"), 10, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidParameter", "parent_dir_id must be an integer")
+		return false
+	}
+
+	feed.Title = r.PostForm.Get("title")
+	feed.RssSourceURL = r.PostForm.Get("rss_source_url")
+	feed.ParentDirID = uint(parentDirID)
+	feed.DeleteOldFiles = r.PostForm.Get("delete_old_files") == "true"
+	feed.DontProcessWholeFeed = r.PostForm.Get("dont_process_whole_feed") == "true"
+	feed.Keyword = r.PostForm.Get("keyword")
+	feed.UnwantedKeywords = r.PostForm.Get("unwanted_keywords")
+	return true
+}
+
+func (f *Fake) serveFiles(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 1 && segments[0] == "list" && r.Method == http.MethodGet:
+		parentID, err := strconv.ParseUint(r.URL.Query().Get("parent_id"), 10, 0)
+		if err != nil {
+			parentID = 0
+		}
+
+		if _, ok := f.files[uint(parentID)]; !ok {
+			writeError(w, http.StatusNotFound, "NotFound", "File not found")
+			return
+		}
+
+		perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if err != nil || perPage <= 0 {
+			perPage = 1000
+		}
+
+		f.writeFilesPage(w, uint(parentID), 0, perPage)
+	case len(segments) == 2 && segments[0] == "list" && segments[1] == "continue" && r.Method == http.MethodPost:
+		var body struct {
+			Cursor string `json:"cursor"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidCursor", "Invalid cursor")
+			return
+		}
+
+		var parentID uint
+		var offset, perPage int
+		if _, err := fmt.Sscanf(body.Cursor, "%d:%d:%d", &parentID, &offset, &perPage); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidCursor", "Invalid cursor")
+			return
+		}
+
+		f.writeFilesPage(w, parentID, offset, perPage)
+	case len(segments) == 1 && segments[0] == "create-folder" && r.Method == http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidForm", err.Error())
+			return
+		}
+
+		parentID, err := strconv.ParseUint(r.PostForm.Get("parent_id"), 10, 0)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidParameter", "parent_id must be an integer")
+			return
+		}
+
+		if parent, ok := f.files[uint(parentID)]; !ok || !parent.IsDir() {
+			writeError(w, http.StatusNotFound, "NotFound", "Parent folder not found")
+			return
+		}
+
+		name := r.PostForm.Get("name")
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "MissingParameter", "name is required")
+			return
+		}
+
+		id := f.newID()
+		now := putio.Time{Time: f.now().UTC()}
+		folder := &putio.File{ID: id, Name: name, ParentID: uint(parentID), ContentType: "application/x-directory", FileType: "FOLDER", CreatedAt: now, UpdatedAt: now}
+		f.files[id] = folder
+		writeJSON(w, http.StatusOK, map[string]interface{}{"file": marshalFile(folder)})
+	case len(segments) == 1 && segments[0] == "delete" && r.Method == http.MethodPost:
+		f.deleteFiles(w, r)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		id, err := strconv.ParseUint(segments[0], 10, 0)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "NotFound", "Not found")
+			return
+		}
+
+		file, ok := f.files[uint(id)]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NotFound", "File not found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"file": marshalFile(file)})
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "Not found")
+	}
+}
+
+// deleteFiles removes the files given by the form of the request along with their content,
+// moving them to the trash unless skip_trash is set.
+func (f *Fake) deleteFiles(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidForm", err.Error())
+		return
+	}
+
+	ids := make([]uint, 0)
+	for _, value := range strings.Split(r.PostForm.Get("file_ids"), ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(value), 10, 0)
+		if err != nil || id == 0 {
+			writeError(w, http.StatusBadRequest, "InvalidParameter", "file_ids must list file IDs")
+			return
+		}
+
+		if _, ok := f.files[uint(id)]; !ok {
+			writeError(w, http.StatusNotFound, "NotFound", "File not found")
+			return
+		}
+
+		ids = append(ids, uint(id))
+	}
+
+	skipTrash := r.PostForm.Get("skip_trash") == "true"
+	for len(ids) > 0 {
+		id := ids[0]
+		ids = ids[1:]
+
+		if file, ok := f.files[id]; ok {
+			if !skipTrash {
+				f.trash[id] = file
+			}
+
+			delete(f.files, id)
+		}
+
+		for childID, child := range f.files {
+			if child.ParentID == id {
+				ids = append(ids, childID)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, nil)
+}
+
+// writeFilesPage answers the children of given folder from offset, with a cursor to the next page if any.
+func (f *Fake) writeFilesPage(w http.ResponseWriter, parentID uint, offset, perPage int) {
+	children := make([]*putio.File, 0)
+	for _, id := range sortedIDs(f.files) {
+		if file := f.files[id]; id != 0 && file.ParentID == parentID {
+			children = append(children, file)
+		}
+	}
+
+	var cursor interface{}
+	if offset > len(children) {
+		offset = len(children)
+	}
+
+	end := offset + perPage
+	if end < len(children) {
+		cursor = fmt.Sprintf("%d:%d:%d", parentID, end, perPage)
+	} else {
+		end = len(children)
+	}
+
+	files := make([]interface{}, 0, end-offset)
+	for _, file := range children[offset:end] {
+		files = append(files, marshalFile(file))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"files":  files,
+		"parent": marshalFile(f.files[parentID]),
+		"cursor": cursor,
+		"total":  len(children),
+	})
+}
+
+func (f *Fake) serveTransfers(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 1 && segments[0] == "add" && r.Method == http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidForm", err.Error())
+			return
+		}
+
+		source := r.PostForm.Get("url")
+		if source == "" {
+			writeError(w, http.StatusBadRequest, "MissingParameter", "url is required")
+			return
+		}
+
+		saveParentID, err := strconv.ParseUint(r.PostForm.Get("save_parent_id"), 10, 0)
+		if err != nil {
+			saveParentID = 0
+		}
+
+		if _, ok := f.files[uint(saveParentID)]; !ok {
+			writeError(w, http.StatusNotFound, "NotFound", "Parent folder not found")
+			return
+		}
+
+		id := f.newID()
+		transfer := &putio.Transfer{
+			ID:           &id,
+			Name:         transferName(source),
+			Source:       source,
+			SaveParentID: uint(saveParentID),
+			Status:       putio.TransferStatusInQueue,
+			CreatedAt:    putio.Time{Time: f.now().UTC()},
+		}
+		f.transfers[id] = transfer
+		writeJSON(w, http.StatusOK, map[string]interface{}{"transfer": marshalTransfer(transfer)})
+	case len(segments) == 1 && segments[0] == "cancel" && r.Method == http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidForm", err.Error())
+			return
+		}
+
+		for _, value := range strings.Split(r.PostForm.Get("transfer_ids"), ",") {
+			if id, err := strconv.ParseUint(strings.TrimSpace(value), 10, 0); err == nil {
+				delete(f.transfers, uint(id))
+			}
+		}
+
+		writeJSON(w, http.StatusOK, nil)
+	case len(segments) == 1 && segments[0] == "retry" && r.Method == http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidForm", err.Error())
+			return
+		}
+
+		id, _ := strconv.ParseUint(r.PostForm.Get("id"), 10, 0)
+		transfer, ok := f.transfers[uint(id)]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NotFound", "Transfer not found")
+			return
+		}
+
+		transfer.Status = putio.TransferStatusInQueue
+		transfer.ErrorMessage = ""
+		transfer.FinishedAt = putio.Time{}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"transfer": marshalTransfer(transfer)})
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		id, err := strconv.ParseUint(segments[0], 10, 0)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "NotFound", "Not found")
+			return
+		}
+
+		transfer, ok := f.transfers[uint(id)]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NotFound", "Transfer not found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{"transfer": marshalTransfer(transfer)})
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", "Not found")
+	}
+}
+
+// transferName names a transfer after the last segment of its source.
+func transferName(source string) string {
+	source = strings.TrimRight(source, "/")
+	if i := strings.LastIndexAny(source, "/:"); i >= 0 {
+		return source[i+1:]
+	}
+
+	return source
+}
+
+func marshalFeed(feed *putio.Feed) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                      feed.ID,
+		"title":                   feed.Title,
+		"rss_source_url":          feed.RssSourceURL,
+		"parent_dir_id":           feed.ParentDirID,
+		"delete_old_files":        feed.DeleteOldFiles,
+		"dont_process_whole_feed": feed.DontProcessWholeFeed,
+		"keyword":                 feed.Keyword,
+		"unwanted_keywords":       feed.UnwantedKeywords,
+		"paused":                  feed.Paused,
+		"extract":                 feed.Extract,
+		"failed_item_count":       feed.FailedItemCount,
+		"last_error":              nullString(feed.LastError),
+		"last_fetch":              formatTime(feed.LastFetch),
+		"created_at":              formatTime(feed.CreatedAt),
+		"paused_at":               formatTime(feed.PausedAt),
+		"start_at":                formatTime(feed.StartAt),
+		"updated_at":              formatTime(feed.UpdatedAt),
+	}
+}
+
+func marshalFeedItem(item *putio.FeedItem) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             item.ID,
+		"title":          item.Title,
+		"is_failed":      item.IsFailed,
+		"failure_reason": nullString(item.FailureReason),
+		"processed_at":   formatTime(item.ProcessedAt),
+	}
+}
+
+func marshalFile(file *putio.File) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           file.ID,
+		"name":         file.Name,
+		"parent_id":    file.ParentID,
+		"file_type":    file.FileType,
+		"content_type": file.ContentType,
+		"size":         file.Size,
+		"is_shared":    file.IsShared,
+		"created_at":   formatTime(file.CreatedAt),
+		"updated_at":   formatTime(file.UpdatedAt),
+	}
+}
+
+func marshalTransfer(transfer *putio.Transfer) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             transfer.ID,
+		"name":           transfer.Name,
+		"source":         transfer.Source,
+		"save_parent_id": transfer.SaveParentID,
+		"status":         transfer.Status,
+		"status_message": transfer.StatusMessage,
+		"error_message":  nullString(transfer.ErrorMessage),
+		"percent_done":   transfer.PercentDone,
+		"down_speed":     transfer.DownloadSpeed,
+		"up_speed":       transfer.UploadSpeed,
+		"size":           transfer.Size,
+		"downloaded":     transfer.Downloaded,
+		"estimated_time": transfer.EstimatedTime,
+		"file_id":        transfer.FileID,
+		"created_at":     formatTime(transfer.CreatedAt),
+		"finished_at":    formatTime(transfer.FinishedAt),
+	}
+}
+
+// formatTime formats given time the way Put.io does, null when unset.
+func formatTime(t putio.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t.UTC().Format(timeLayout)
+}
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// writeJSON answers given body along with the OK status Put.io adds to every successful response.
+func writeJSON(w http.ResponseWriter, code int, body map[string]interface{}) {
+	if body == nil {
+		body = make(map[string]interface{}, 1)
+	}
+
+	body["status"] = "OK"
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError answers an error payload decoded by go-putio into an ErrorResponse.
+func writeError(w http.ResponseWriter, code int, errorType, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":        "ERROR",
+		"status_code":   code,
+		"error_type":    errorType,
+		"error_message": message,
+		"error_id":      strconv.FormatInt(time.Now().UnixNano(), 36),
+		"extra":         map[string]interface{}{},
+	})
+}
@@ -0,0 +1,268 @@
+package putiotest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	putiohttp "github.com/SkYNewZ/putio-operator/internal/http"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
+	goputio "github.com/putdotio/go-putio"
+)
+
+// newClient returns a Put.io client sending requests to a new fake, authenticated with given token.
+func newClient(t *testing.T, token string, opts ...Option) (*putio.Client, *Fake) {
+	t.Helper()
+
+	server, fake := NewServer(opts...)
+	t.Cleanup(server.Close)
+
+	if err := putio.SetBaseURL(server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	client := putio.New(context.Background(), putiohttp.NewHTTPClient(token))
+	return client, fake
+}
+
+func TestFake_rss(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newClient(t, "token", WithToken("token"))
+
+	created, err := client.Rss.Create(ctx, &putio.Feed{
+		Title:            "Foo",
+		RssSourceURL:     "https://example.com/rss",
+		Keyword:          "foo",
+		UnwantedKeywords: "bar",
+		DeleteOldFiles:   true,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if created.ID == nil || created.CreatedAt.IsZero() {
+		t.Fatalf("Create() = %+v, want an ID and a creation time", created)
+	}
+
+	id := *created.ID
+	created.Keyword = "foo|baz"
+	if err := client.Rss.Update(ctx, created, id); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if err := client.Rss.Pause(ctx, id); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+
+	got, err := client.Rss.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if got.Keyword != "foo|baz" || !got.Paused || got.PausedAt.IsZero() || !got.DeleteOldFiles {
+		t.Errorf("Get() = %+v, want the updated and paused feed", got)
+	}
+
+	if err := client.Rss.Resume(ctx, id); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	if feed, _ := fake.Feed(id); feed.Paused {
+		t.Errorf("Feed() paused after Resume()")
+	}
+
+	fake.AddFeedItem(id, "Foo.S01E01", false)
+	fake.AddFeedItem(id, "Foo.S01E02", true)
+
+	items, err := client.Rss.Items(ctx, id)
+	if err != nil {
+		t.Fatalf("Items() error = %v", err)
+	}
+
+	if len(items) != 2 || items[0].Title != "Foo.S01E02" || !items[0].IsFailed || items[1].IsFailed || items[1].ProcessedAt.IsZero() {
+		t.Errorf("Items() = %+v, want both items, the most recent first", items)
+	}
+
+	feeds, err := client.Rss.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(feeds) != 1 {
+		t.Errorf("List() returned %d feeds, want 1", len(feeds))
+	}
+
+	if err := client.Rss.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := client.Rss.Get(ctx, id); !putio.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want a NotFound error", err)
+	}
+}
+
+func TestFake_rssValidation(t *testing.T) {
+	client, _ := newClient(t, "token")
+
+	_, err := client.Rss.Create(context.Background(), &putio.Feed{Title: "Foo", RssSourceURL: "https://example.com/rss"})
+
+	var errorResponse *goputio.ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Type != "MissingParameter" {
+		t.Errorf("Create() error = %v, want a MissingParameter error", err)
+	}
+}
+
+func TestFake_unauthorized(t *testing.T) {
+	client, _ := newClient(t, "wrong", WithToken("token"))
+
+	if _, err := client.Rss.List(context.Background()); !putio.IsUnauthorized(err) {
+		t.Errorf("List() error = %v, want an unauthorized error", err)
+	}
+}
+
+func TestFake_files(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newClient(t, "token")
+
+	movies, err := client.Files.CreateFolder(ctx, "Movies", 0)
+	if err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	if !movies.IsDir() {
+		t.Errorf("CreateFolder() = %+v, want a folder", movies)
+	}
+
+	// more files than a page holds, listed through cursors
+	for i := 0; i < 1005; i++ {
+		fake.AddFile(putio.File{Name: "movie.mkv", ParentID: movies.ID, Size: 1})
+	}
+
+	files, err := client.Files.List(ctx, movies.ID)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(files) != 1005 {
+		t.Errorf("List() returned %d files, want 1005", len(files))
+	}
+
+	if _, err := client.Files.CreateFolder(ctx, "Shows", 999999); !putio.IsNotFound(err) {
+		t.Errorf("CreateFolder() error = %v, want a NotFound error", err)
+	}
+
+	if err := client.Files.Trash(ctx, []uint{files[0].ID}); err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+
+	if trashed := fake.Trashed(); len(trashed) != 1 || trashed[0].ID != files[0].ID {
+		t.Errorf("Trashed() = %v, want the trashed file", trashed)
+	}
+
+	if err := client.Files.Delete(ctx, []uint{files[1].ID}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := client.Files.Get(ctx, files[1].ID); !putio.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want a NotFound error", err)
+	}
+
+	info, err := client.Account.Info(ctx)
+	if err != nil {
+		t.Fatalf("Account.Info() error = %v", err)
+	}
+
+	if info.Disk.Used != 1003 || info.Disk.Avail != DefaultDiskSize-1003 {
+		t.Errorf("Account.Info() disk = %+v, want 1003 bytes used", info.Disk)
+	}
+}
+
+func TestFake_transfers(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newClient(t, "token")
+
+	transfer, err := client.Transfers.Add(ctx, "magnet:?xt=urn:btih:foo", 0)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if transfer.Status != putio.TransferStatusInQueue {
+		t.Errorf("Add() status = %q, want %q", transfer.Status, putio.TransferStatusInQueue)
+	}
+
+	fake.SetTransferStatus(*transfer.ID, putio.TransferStatusCompleted, "", 42)
+
+	got, err := client.Transfers.Get(ctx, *transfer.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if got.Status != putio.TransferStatusCompleted || got.FileID == nil || got.FinishedAt.IsZero() {
+		t.Errorf("Get() = %+v, want a completed transfer with a file", got)
+	}
+
+	if file, ok := fake.File(*got.FileID); !ok || file.Size != 42 {
+		t.Errorf("File() = %+v, want the downloaded file", file)
+	}
+
+	if err := client.Transfers.Cancel(ctx, *transfer.ID); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	if _, err := client.Transfers.Get(ctx, *transfer.ID); !putio.IsNotFound(err) {
+		t.Errorf("Get() error = %v, want a NotFound error", err)
+	}
+}
+
+func TestFake_faults(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newClient(t, "faults")
+
+	// rate-limited requests are retried once Retry-After elapsed
+	fake.RateLimitNext(1, 0)
+	if _, err := client.Rss.List(ctx); err != nil {
+		t.Fatalf("List() error = %v, want the rate-limited request retried", err)
+	}
+
+	if got := fake.Requests(); got != 2 {
+		t.Errorf("Requests() = %d, want 2", got)
+	}
+
+	// non idempotent requests failing with a server error are not retried
+	fake.FailNext("/v2/rss/create", http.StatusServiceUnavailable, 1)
+	_, err := client.Rss.Create(ctx, &putio.Feed{Title: "Foo", RssSourceURL: "https://example.com/rss", Keyword: "foo"})
+
+	var errorResponse *goputio.ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Response.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Create() error = %v, want a 503 error", err)
+	}
+
+	if feeds := fake.Feeds(); len(feeds) != 0 {
+		t.Errorf("Feeds() = %v, want no feed created", feeds)
+	}
+}
+
+func TestFake_timeFormat(t *testing.T) {
+	fake := New()
+	fake.now = func() time.Time { return time.Date(2022, 7, 14, 10, 30, 0, 0, time.UTC) }
+
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	if err := putio.SetBaseURL(server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	client := putio.New(context.Background(), http.DefaultClient)
+	feed, err := client.Rss.Create(context.Background(), &putio.Feed{Title: "Foo", RssSourceURL: "https://example.com/rss", Keyword: "foo"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if want := fake.now(); !feed.CreatedAt.Equal(want) {
+		t.Errorf("Create() created_at = %v, want %v", feed.CreatedAt, want)
+	}
+}
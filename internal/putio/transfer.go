@@ -0,0 +1,32 @@
+package putio
+
+// Transfer statuses reported by Put.io.
+const (
+	TransferStatusInQueue     string = "IN_QUEUE"
+	TransferStatusWaiting     string = "WAITING"
+	TransferStatusDownloading string = "DOWNLOADING"
+	TransferStatusCompleting  string = "COMPLETING"
+	TransferStatusSeeding     string = "SEEDING"
+	TransferStatusCompleted   string = "COMPLETED"
+	TransferStatusError       string = "ERROR"
+)
+
+type Transfer struct {
+	ID            *uint  `json:"id"`
+	Name          string `json:"name"`
+	Source        string `json:"source"`
+	SaveParentID  uint   `json:"save_parent_id"`
+	Status        string `json:"status"`
+	StatusMessage string `json:"status_message"`
+	ErrorMessage  string `json:"error_message"`
+	PercentDone   int    `json:"percent_done"`
+	DownloadSpeed int64  `json:"down_speed"`
+	UploadSpeed   int64  `json:"up_speed"`
+	Size          int64  `json:"size"`
+	Downloaded    int64  `json:"downloaded"`
+	EstimatedTime int64  `json:"estimated_time"`
+	FileID        *uint  `json:"file_id"`
+
+	CreatedAt  Time `json:"created_at"`
+	FinishedAt Time `json:"finished_at"`
+}
@@ -0,0 +1,125 @@
+package putio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type transfersService struct {
+	client *Client
+}
+
+// Add a new transfer from given URL or magnet link, downloaded into given parent folder.
+func (s *transfersService) Add(ctx context.Context, sourceURL string, parentDirID uint) (*Transfer, error) {
+	ctx, span := s.client.tracer.Start(ctx, "putio.transfersService.Add")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("parent_dir_id", int(parentDirID)))
+
+	params := url.Values{}
+	params.Set("url", sourceURL)
+	params.Set("save_parent_id", strconv.Itoa(int(parentDirID)))
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, "/v2/transfers/add", strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("putio: cannot make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var r struct {
+		Transfer *Transfer `json:"transfer"`
+	}
+	_, err = s.client.Do(req, &r) //nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("putio: response error: %w", err)
+	}
+
+	return r.Transfer, nil
+}
+
+// Get a transfer.
+func (s *transfersService) Get(ctx context.Context, id uint) (*Transfer, error) {
+	ctx, span := s.client.tracer.Start(ctx, "putio.transfersService.Get")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("id", int(id)))
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, "/v2/transfers/"+strconv.Itoa(int(id)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("putio: cannot make request: %w", err)
+	}
+
+	var r struct {
+		Transfer *Transfer `json:"transfer"`
+	}
+	_, err = s.client.Do(req, &r) //nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("putio: response error: %w", err)
+	}
+
+	return r.Transfer, nil
+}
+
+// Cancel a transfer. Files already downloaded are kept.
+func (s *transfersService) Cancel(ctx context.Context, id uint) error {
+	ctx, span := s.client.tracer.Start(ctx, "putio.transfersService.Cancel")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("id", int(id)))
+
+	params := url.Values{}
+	params.Set("transfer_ids", strconv.Itoa(int(id)))
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, "/v2/transfers/cancel", strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("putio: cannot make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var r struct {
+		Status string `json:"status"`
+	}
+
+	if _, err := s.client.Do(req, &r); err != nil { //nolint:bodyclose
+		return fmt.Errorf("putio: response error: %w", err)
+	}
+
+	if r.Status != "OK" {
+		return newErrInvalidStatusReceived(r.Status)
+	}
+
+	return nil
+}
+
+// Retry a failed transfer.
+func (s *transfersService) Retry(ctx context.Context, id uint) (*Transfer, error) {
+	ctx, span := s.client.tracer.Start(ctx, "putio.transfersService.Retry")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("id", int(id)))
+
+	params := url.Values{}
+	params.Set("id", strconv.Itoa(int(id)))
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, "/v2/transfers/retry", strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("putio: cannot make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var r struct {
+		Transfer *Transfer `json:"transfer"`
+	}
+	_, err = s.client.Do(req, &r) //nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("putio: response error: %w", err)
+	}
+
+	return r.Transfer, nil
+}
@@ -0,0 +1,283 @@
+package putio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/putdotio/go-putio"
+	"go.opentelemetry.io/otel"
+)
+
+func makeExpectedTransfer() *Transfer {
+	transferID := uint(98421)
+	return &Transfer{
+		ID:            &transferID,
+		Name:          "House.of.the.Dragon.S01E04.MULTi.1080p.WEB.H264-FW",
+		Source:        "magnet:?xt=urn:btih:c9e15763f722f23e98a29decdfae341b98d53056",
+		SaveParentID:  1022542820,
+		Status:        TransferStatusDownloading,
+		StatusMessage: "Downloading at 4 MB/s",
+		ErrorMessage:  "",
+		PercentDone:   42,
+		DownloadSpeed: 4194304,
+		UploadSpeed:   1024,
+		Size:          2556000000,
+		Downloaded:    1073741824,
+		EstimatedTime: 360,
+		FileID:        nil,
+		CreatedAt:     Time{time.Date(2022, time.September, 12, 8, 12, 3, 0, time.UTC)},
+		FinishedAt:    Time{},
+	}
+}
+
+func Test_transfersService_Add(t *testing.T) {
+	type fields struct {
+		client *Client
+	}
+	type args struct {
+		ctx         context.Context
+		sourceURL   string
+		parentDirID uint
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *Transfer
+		wantErr bool
+	}{
+		{
+			name: "expected",
+			fields: fields{
+				client: &Client{
+					Client: putio.NewClient(NewTestClient(t, func(req *http.Request) *http.Response {
+						if err := req.ParseForm(); err != nil {
+							t.Fatal(err)
+						}
+
+						if got := req.PostForm.Get("save_parent_id"); got != "1022542820" {
+							t.Errorf("save_parent_id = %q, want %q", got, "1022542820")
+						}
+
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       readGoldenFile(t, "transfer"),
+							Header:     make(http.Header),
+						}
+					})),
+					Transfers: nil, // currently tested
+					tracer:    otel.GetTracerProvider().Tracer("putio-testing"),
+				},
+			},
+			args: args{
+				ctx:         context.Background(),
+				sourceURL:   "magnet:?xt=urn:btih:c9e15763f722f23e98a29decdfae341b98d53056",
+				parentDirID: 1022542820,
+			},
+			want:    makeExpectedTransfer(),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &transfersService{
+				client: tt.fields.client,
+			}
+			got, err := s.Add(tt.args.ctx, tt.args.sourceURL, tt.args.parentDirID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Add() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_transfersService_Get(t *testing.T) {
+	type fields struct {
+		client *Client
+	}
+	type args struct {
+		ctx context.Context
+		id  uint
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *Transfer
+		wantErr bool
+	}{
+		{
+			name: "expected",
+			fields: fields{
+				client: &Client{
+					Client: putio.NewClient(NewTestClient(t, func(req *http.Request) *http.Response {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       readGoldenFile(t, "transfer"),
+							Header:     make(http.Header),
+						}
+					})),
+					Transfers: nil, // currently tested
+					tracer:    otel.GetTracerProvider().Tracer("putio-testing"),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  98421,
+			},
+			want:    makeExpectedTransfer(),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &transfersService{
+				client: tt.fields.client,
+			}
+			got, err := s.Get(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_transfersService_Cancel(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		wantErr bool
+	}{
+		{
+			name:    "expected",
+			status:  "OK",
+			wantErr: false,
+		},
+		{
+			name:    "unexpected status",
+			status:  "ERROR",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &transfersService{client: &Client{
+				Client: putio.NewClient(NewTestClient(t, func(req *http.Request) *http.Response {
+					if req.Method != http.MethodPost || req.URL.Path != "/v2/transfers/cancel" {
+						t.Errorf("request = %s %s, want %s %s", req.Method, req.URL.Path, http.MethodPost, "/v2/transfers/cancel")
+					}
+
+					if err := req.ParseForm(); err != nil {
+						t.Fatal(err)
+					}
+
+					if got := req.PostForm.Get("transfer_ids"); got != "98421" {
+						t.Errorf("transfer_ids = %q, want %q", got, "98421")
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"status":"` + tt.status + `"}`)),
+						Header:     make(http.Header),
+					}
+				})),
+				tracer: otel.GetTracerProvider().Tracer("putio-testing"),
+			}}
+
+			if err := s.Cancel(context.Background(), 98421); (err != nil) != tt.wantErr {
+				t.Errorf("Cancel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_transfersService_Retry(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		id  uint
+	}
+	tests := []struct {
+		name       string
+		statusCode int
+		args       args
+		want       *Transfer
+		wantErr    bool
+	}{
+		{
+			name:       "expected",
+			statusCode: http.StatusOK,
+			args: args{
+				ctx: context.Background(),
+				id:  98421,
+			},
+			want:    makeExpectedTransfer(),
+			wantErr: false,
+		},
+		{
+			name:       "not found",
+			statusCode: http.StatusNotFound,
+			args: args{
+				ctx: context.Background(),
+				id:  98421,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &transfersService{client: &Client{
+				Client: putio.NewClient(NewTestClient(t, func(req *http.Request) *http.Response {
+					if req.Method != http.MethodPost || req.URL.Path != "/v2/transfers/retry" {
+						t.Errorf("request = %s %s, want %s %s", req.Method, req.URL.Path, http.MethodPost, "/v2/transfers/retry")
+					}
+
+					if err := req.ParseForm(); err != nil {
+						t.Fatal(err)
+					}
+
+					if got := req.PostForm.Get("id"); got != "98421" {
+						t.Errorf("id = %q, want %q", got, "98421")
+					}
+
+					if tt.statusCode != http.StatusOK {
+						return &http.Response{
+							StatusCode: tt.statusCode,
+							Body:       io.NopCloser(strings.NewReader(`{"status":"ERROR","error_type":"NotFound"}`)),
+							Header:     make(http.Header),
+						}
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       readGoldenFile(t, "transfer"),
+						Header:     make(http.Header),
+					}
+				})),
+				tracer: otel.GetTracerProvider().Tracer("putio-testing"),
+			}}
+
+			got, err := s.Retry(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Retry() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Retry() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
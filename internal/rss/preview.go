@@ -0,0 +1,53 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/SkYNewZ/putio-operator/api/v1alpha1"
+)
+
+// Preview is the result of matching the items of a feed against keywords.
+type Preview struct {
+	// Items of the feed.
+	Items []Item
+	// Matches are the items Put.io would transfer.
+	Matches []Item
+}
+
+// MakePreview fetches the feed at given URL and matches its items against given
+// Put.io keyword and unwanted keywords expressions, the way Put.io does.
+func MakePreview(ctx context.Context, client *http.Client, url, keyword, unwanted string) (*Preview, error) {
+	items, err := Fetch(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return Match(items, keyword, unwanted)
+}
+
+// Match returns the items whose title matches keyword and does not match unwanted.
+// Matching is case-insensitive, an empty unwanted expression excludes nothing.
+func Match(items []Item, keyword, unwanted string) (*Preview, error) {
+	wanted, err := v1alpha1.ParseKeywordExpression(keyword)
+	if err != nil {
+		return nil, fmt.Errorf("rss: invalid keyword: %w", err)
+	}
+
+	var unwantedExpr v1alpha1.KeywordExpression
+	if unwanted != "" {
+		if unwantedExpr, err = v1alpha1.ParseKeywordExpression(unwanted); err != nil {
+			return nil, fmt.Errorf("rss: invalid unwanted keywords: %w", err)
+		}
+	}
+
+	preview := &Preview{Items: items}
+	for _, item := range items {
+		if wanted.Match(item.Title) && !unwantedExpr.Match(item.Title) {
+			preview.Matches = append(preview.Matches, item)
+		}
+	}
+
+	return preview, nil
+}
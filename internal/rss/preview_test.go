@@ -0,0 +1,94 @@
+package rss
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMakePreview(t *testing.T) {
+	server := newFixtureServer(t)
+
+	tests := []struct {
+		name      string
+		path      string
+		keyword   string
+		unwanted  string
+		wantItems int
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "single keyword",
+			path:      "/rss.xml",
+			keyword:   "House.of.the.Dragon",
+			wantItems: 4,
+			want: []string{
+				"House.of.the.Dragon.S01E01.MULTi.1080p.WEB.H264-FW",
+				"House.of.the.Dragon.S01E01.MULTi.2160p.HDR.WEB.H265-FW",
+				"House.of.the.Dragon.S01E02.VOSTFR.1080p.WEB.H264-FW",
+			},
+		},
+		{
+			name:      "conjunction and unwanted keywords",
+			path:      "/rss.xml",
+			keyword:   "House.of.the.Dragon&MULTi",
+			unwanted:  "HDR",
+			wantItems: 4,
+			want:      []string{"House.of.the.Dragon.S01E01.MULTi.1080p.WEB.H264-FW"},
+		},
+		{
+			name:      "alternatives ignoring case",
+			path:      "/atom.xml",
+			keyword:   "HOUSE.OF.THE.DRAGON, Rings.of.Power&S01E02",
+			wantItems: 2,
+			want: []string{
+				"house.of.the.dragon.s01e03.multi.1080p.web.h264-fw",
+				"The.Lord.of.the.Rings.The.Rings.of.Power.S01E02.MULTi.1080p.WEB.H264-FW",
+			},
+		},
+		{
+			name:      "nothing matches",
+			path:      "/rss.xml",
+			keyword:   "Andor",
+			wantItems: 4,
+		},
+		{
+			name:    "invalid keyword",
+			path:    "/rss.xml",
+			keyword: "House.of.the.Dragon&",
+			wantErr: true,
+		},
+		{
+			name:    "unreachable feed",
+			path:    "/missing.xml",
+			keyword: "House.of.the.Dragon",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MakePreview(context.Background(), server.Client(), server.URL+tt.path, tt.keyword, tt.unwanted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MakePreview() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if len(got.Items) != tt.wantItems {
+				t.Errorf("MakePreview() got %d items, want %d", len(got.Items), tt.wantItems)
+			}
+
+			var titles []string
+			for _, item := range got.Matches {
+				titles = append(titles, item.Title)
+			}
+
+			if !reflect.DeepEqual(titles, tt.want) {
+				t.Errorf("MakePreview() matches = %v, want %v", titles, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package rss
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxFeedSize bounds the size of the fetched feeds.
+const maxFeedSize = 10 << 20
+
+var tracer = otel.GetTracerProvider().Tracer("rss")
+
+var (
+	errUnexpectedStatus  = errors.New("unexpected status")
+	errUnsupportedScheme = errors.New("only http and https URLs can be fetched")
+	errUnknownFormat     = errors.New("neither an RSS nor an Atom feed")
+)
+
+// Item is an entry of an RSS or Atom feed.
+type Item struct {
+	Title     string
+	Link      string
+	Published time.Time
+}
+
+// document holds the elements of RSS 2.0 and Atom documents we are interested in.
+type document struct {
+	XMLName xml.Name
+	// RSS
+	Channel struct {
+		Items []struct {
+			Title   string `xml:"title"`
+			Link    string `xml:"link"`
+			PubDate string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+	// Atom
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// Fetch downloads and parses the feed at given URL.
+// The URL is redacted from the returned errors and the trace, as trackers embed credentials in it.
+func Fetch(ctx context.Context, client *http.Client, rawURL string) ([]Item, error) {
+	ctx, span := tracer.Start(ctx, "rss.Fetch")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("rss.url", RedactURL(rawURL)))
+
+	if u, err := url.Parse(rawURL); err == nil && u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("rss: %w", errUnsupportedScheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rss: invalid request: %w", redactError(err))
+	}
+
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	resp, err := client.Do(req)
+	if err != nil {
+		err = redactError(err)
+		span.RecordError(err)
+		return nil, fmt.Errorf("rss: unable to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rss: %w %s", errUnexpectedStatus, resp.Status)
+	}
+
+	return Parse(io.LimitReader(resp.Body, maxFeedSize))
+}
+
+// RedactURL hides the query string and password of given URL, where trackers put passkeys.
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "REDACTED"
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery = "REDACTED"
+	}
+
+	u.Fragment = ""
+	return u.Redacted()
+}
+
+// redactError redacts the URL of given *url.Error, returned by the HTTP client.
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactURL(urlErr.URL)
+	}
+
+	return err
+}
+
+// Parse reads the items of an RSS 2.0 or Atom document.
+func Parse(r io.Reader) ([]Item, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("rss: unable to parse feed: %w", err)
+	}
+
+	switch doc.XMLName.Local {
+	case "rss":
+		items := make([]Item, 0, len(doc.Channel.Items))
+		for _, i := range doc.Channel.Items {
+			items = append(items, Item{
+				Title:     strings.TrimSpace(i.Title),
+				Link:      strings.TrimSpace(i.Link),
+				Published: parseTime(i.PubDate, time.RFC1123Z, time.RFC1123),
+			})
+		}
+
+		return items, nil
+	case "feed":
+		items := make([]Item, 0, len(doc.Entries))
+		for _, e := range doc.Entries {
+			item := Item{
+				Title:     strings.TrimSpace(e.Title),
+				Published: parseTime(e.Published, time.RFC3339),
+			}
+
+			if item.Published.IsZero() {
+				item.Published = parseTime(e.Updated, time.RFC3339)
+			}
+
+			for _, l := range e.Links {
+				if l.Rel == "" || l.Rel == "alternate" || l.Rel == "enclosure" {
+					item.Link = l.Href
+					break
+				}
+			}
+
+			items = append(items, item)
+		}
+
+		return items, nil
+	default:
+		return nil, fmt.Errorf("rss: %w, got <%s>", errUnknownFormat, doc.XMLName.Local)
+	}
+}
+
+// parseTime parses given value with the first matching layout, zero time if none matches.
+func parseTime(value string, layouts ...string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -2,49 +2,199 @@ package tracing
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 )
 
+// Exporters, named after the OTEL_TRACES_EXPORTER values.
+const (
+	ExporterOTLP    = "otlp"
+	ExporterConsole = "console"
+	ExporterStdout  = "stdout" // alias of console
+	ExporterNone    = "none"
+)
+
+// OTLP protocols, named after the OTEL_EXPORTER_OTLP_PROTOCOL values.
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+// Standard environment variables, they take precedence over the Config.
+// See https://opentelemetry.io/docs/reference/specification/sdk-environment-variables/
+const (
+	envTracesExporter = "OTEL_TRACES_EXPORTER"
+	envProtocol       = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	envTracesProtocol = "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"
+	envEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	envTracesSampler  = "OTEL_TRACES_SAMPLER"
+)
+
+var (
+	errUnknownExporter = errors.New("unknown exporter")
+	errUnknownProtocol = errors.New("unknown otlp protocol")
+)
+
+// Config configures how traces are sampled and exported.
+type Config struct {
+	// Exporter is one of otlp, console (or stdout) and none. Defaults to otlp.
+	Exporter string
+
+	// Protocol of the otlp exporter, grpc or http/protobuf. Defaults to http/protobuf.
+	Protocol string
+
+	// Endpoint of the otlp exporter, as host:port.
+	// Defaults to localhost:4317 for grpc and localhost:4318 for http/protobuf.
+	Endpoint string
+
+	// Insecure disables TLS towards the otlp endpoint.
+	Insecure bool
+
+	// SamplingRatio of the traces started by the operator, traces started by a caller follow its decision.
+	// Defaults to 1, every trace is sampled.
+	SamplingRatio *float64
+}
+
+// withEnv returns the config overridden by the standard environment variables.
+// Endpoints and samplers set in the environment are read by the exporters and the SDK themselves.
+func (c Config) withEnv() Config {
+	if v := os.Getenv(envTracesExporter); v != "" {
+		c.Exporter = v
+	}
+
+	if v := firstEnv(envTracesProtocol, envProtocol); v != "" {
+		c.Protocol = v
+	}
+
+	if firstEnv(envTracesEndpoint, envEndpoint) != "" {
+		c.Endpoint, c.Insecure = "", false
+	}
+
+	if os.Getenv(envTracesSampler) != "" {
+		c.SamplingRatio = nil
+	}
+
+	if c.Exporter == "" {
+		c.Exporter = ExporterOTLP
+	}
+
+	if c.Protocol == "" {
+		c.Protocol = ProtocolHTTPProtobuf
+	}
+
+	return c
+}
+
 // ConfigureTracing
-// 1. Configures a tracer exporter (console, jaeger, ...)
+// 1. Configures a tracer exporter (otlp, console, ...) and a sampler from given config and the environment
 // 2. Setup tracer provider
-// 3. Globally register this provider and returns it.
-func ConfigureTracing(_ context.Context, serviceName, serviceVersion string) (*sdktrace.TracerProvider, error) {
-	exporter, err := makeExporter()
+// 3. Globally register this provider and the W3C trace context propagator, and returns the provider.
+func ConfigureTracing(ctx context.Context, serviceName, serviceVersion string, config Config) (*sdktrace.TracerProvider, error) {
+	config = config.withEnv()
+
+	exporter, err := makeExporter(ctx, config)
 	if err != nil {
 		return nil, err
 	}
 
-	provider, err := makeTracerProvider(serviceName, serviceVersion, exporter)
+	provider, err := makeTracerProvider(serviceName, serviceVersion, exporter, makeSampler(config))
 	if err != nil {
 		return nil, err
 	}
 
 	// globally register the tracer exporter
 	otel.SetTracerProvider(provider)
+
+	// propagate the trace context on outgoing requests
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 	return provider, nil
 }
 
-// makeExporter configures the Jaeger exporter
-// - OTEL_EXPORTER_JAEGER_AGENT_HOST is used for the agent address host
-// - OTEL_EXPORTER_JAEGER_AGENT_PORT is used for the agent address port
-// See https://github.com/open-telemetry/opentelemetry-go/tree/v1.9.0/exporters/jaeger#environment-variables
-func makeExporter() (sdktrace.SpanExporter, error) {
-	exp, err := jaeger.New(jaeger.WithAgentEndpoint())
+// makeExporter configures the exporter given by config, nil for none.
+// See https://github.com/open-telemetry/opentelemetry-go/tree/v1.19.0/exporters/otlp/otlptrace#environment-variables
+// for the environment variables read by the otlp exporter.
+func makeExporter(ctx context.Context, config Config) (sdktrace.SpanExporter, error) {
+	switch config.Exporter {
+	case ExporterOTLP:
+		return makeOTLPExporter(ctx, config)
+	case ExporterConsole, ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("tracing: cannot init stdout exporter: %w", err)
+		}
+
+		return exp, nil
+	case ExporterNone:
+		return nil, nil //nolint:nilnil
+	default:
+		return nil, fmt.Errorf("tracing: %w %q", errUnknownExporter, config.Exporter)
+	}
+}
+
+func makeOTLPExporter(ctx context.Context, config Config) (sdktrace.SpanExporter, error) {
+	var client otlptrace.Client
+
+	switch config.Protocol {
+	case ProtocolGRPC:
+		var opts []otlptracegrpc.Option
+		if config.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(config.Endpoint))
+		}
+
+		if config.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		client = otlptracegrpc.NewClient(opts...)
+	case ProtocolHTTPProtobuf:
+		var opts []otlptracehttp.Option
+		if config.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(config.Endpoint))
+		}
+
+		if config.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		client = otlptracehttp.NewClient(opts...)
+	default:
+		return nil, fmt.Errorf("tracing: %w %q", errUnknownProtocol, config.Protocol)
+	}
+
+	exp, err := otlptrace.New(ctx, client)
 	if err != nil {
-		return nil, fmt.Errorf("tracing: cannot init jaeger exporter: %w", err)
+		return nil, fmt.Errorf("tracing: cannot init otlp exporter: %w", err)
 	}
 
 	return exp, nil
 }
 
-func makeTracerProvider(serviceName, serviceVersion string, exporter sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+// makeSampler returns a parent-based ratio sampler, nil to let the SDK read OTEL_TRACES_SAMPLER.
+func makeSampler(config Config) sdktrace.Sampler {
+	if config.SamplingRatio == nil {
+		if os.Getenv(envTracesSampler) != "" {
+			return nil
+		}
+
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	}
+
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*config.SamplingRatio))
+}
+
+func makeTracerProvider(serviceName, serviceVersion string, exporter sdktrace.SpanExporter, sampler sdktrace.Sampler) (*sdktrace.TracerProvider, error) {
 	r, err := resource.Merge(
 		resource.Default(),
 		resource.NewWithAttributes(
@@ -57,8 +207,25 @@ func makeTracerProvider(serviceName, serviceVersion string, exporter sdktrace.Sp
 		return nil, fmt.Errorf("tracing: failed to configure tracer provider: %w", err)
 	}
 
-	return sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithResource(r),
-	), nil
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(r)}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	if sampler != nil {
+		opts = append(opts, sdktrace.WithSampler(sampler))
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+// firstEnv returns the value of the first environment variable set.
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+
+	return ""
 }
@@ -0,0 +1,158 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func floatToPtr(f float64) *float64 {
+	return &f
+}
+
+func TestConfig_withEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		env    map[string]string
+		want   Config
+	}{
+		{
+			name:   "defaults",
+			config: Config{},
+			want:   Config{Exporter: ExporterOTLP, Protocol: ProtocolHTTPProtobuf},
+		},
+		{
+			name:   "config file only",
+			config: Config{Exporter: ExporterOTLP, Protocol: ProtocolGRPC, Endpoint: "collector:4317", Insecure: true, SamplingRatio: floatToPtr(0.1)},
+			want:   Config{Exporter: ExporterOTLP, Protocol: ProtocolGRPC, Endpoint: "collector:4317", Insecure: true, SamplingRatio: floatToPtr(0.1)},
+		},
+		{
+			name:   "environment overrides config file",
+			config: Config{Exporter: ExporterOTLP, Protocol: ProtocolGRPC, Endpoint: "collector:4317", Insecure: true, SamplingRatio: floatToPtr(0.1)},
+			env: map[string]string{
+				envTracesExporter: ExporterOTLP,
+				envProtocol:       ProtocolHTTPProtobuf,
+				envEndpoint:       "http://collector:4318",
+				envTracesSampler:  "always_off",
+			},
+			want: Config{Exporter: ExporterOTLP, Protocol: ProtocolHTTPProtobuf},
+		},
+		{
+			name:   "traces protocol takes precedence",
+			config: Config{},
+			env: map[string]string{
+				envTracesExporter: ExporterNone,
+				envProtocol:       ProtocolHTTPProtobuf,
+				envTracesProtocol: ProtocolGRPC,
+			},
+			want: Config{Exporter: ExporterNone, Protocol: ProtocolGRPC},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{envTracesExporter, envProtocol, envTracesProtocol, envEndpoint, envTracesEndpoint, envTracesSampler} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			if got := tt.config.withEnv(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_makeExporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantNil bool
+		wantErr error
+	}{
+		{
+			name:   "otlp grpc",
+			config: Config{Exporter: ExporterOTLP, Protocol: ProtocolGRPC, Endpoint: "localhost:4317", Insecure: true},
+		},
+		{
+			name:   "otlp http",
+			config: Config{Exporter: ExporterOTLP, Protocol: ProtocolHTTPProtobuf, Endpoint: "localhost:4318", Insecure: true},
+		},
+		{
+			name:   "console",
+			config: Config{Exporter: ExporterConsole},
+		},
+		{
+			name:    "none",
+			config:  Config{Exporter: ExporterNone},
+			wantNil: true,
+		},
+		{
+			name:    "unknown exporter",
+			config:  Config{Exporter: "jaeger"},
+			wantNil: true,
+			wantErr: errUnknownExporter,
+		},
+		{
+			name:    "unknown protocol",
+			config:  Config{Exporter: ExporterOTLP, Protocol: "http/json"},
+			wantNil: true,
+			wantErr: errUnknownProtocol,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := makeExporter(context.Background(), tt.config)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("makeExporter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if (got == nil) != tt.wantNil {
+				t.Errorf("makeExporter() = %v, wantNil %v", got, tt.wantNil)
+			}
+			if got != nil {
+				_ = got.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func Test_makeSampler(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		env    string
+		want   string
+	}{
+		{
+			name:   "defaults to always sample",
+			config: Config{},
+			want:   "ParentBased{root:AlwaysOnSampler,remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}",
+		},
+		{
+			name:   "ratio",
+			config: Config{SamplingRatio: floatToPtr(0.25)},
+			want:   "ParentBased{root:TraceIDRatioBased{0.25},remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}",
+		},
+		{
+			name:   "left to the SDK",
+			config: Config{},
+			env:    "always_off",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envTracesSampler, tt.env)
+
+			var got string
+			if sampler := makeSampler(tt.config); sampler != nil {
+				got = sampler.Description()
+			}
+
+			if got != tt.want {
+				t.Errorf("makeSampler() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
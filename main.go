@@ -23,14 +23,21 @@ import (
 	"os"
 	"time"
 
+	// Embed the time zone database, so that feed schedules do not depend on the image.
+	_ "time/tzdata"
+
 	"github.com/go-logr/zapr"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
+	configv1alpha1 "github.com/SkYNewZ/putio-operator/api/config/v1alpha1"
 	putiov1alpha1 "github.com/SkYNewZ/putio-operator/api/v1alpha1"
+	putiov1beta1 "github.com/SkYNewZ/putio-operator/api/v1beta1"
 	"github.com/SkYNewZ/putio-operator/controllers"
+	putiohttp "github.com/SkYNewZ/putio-operator/internal/http"
 	"github.com/SkYNewZ/putio-operator/internal/logger"
+	"github.com/SkYNewZ/putio-operator/internal/putio"
 	"github.com/SkYNewZ/putio-operator/internal/sentry"
 	"github.com/SkYNewZ/putio-operator/internal/tracing"
 
@@ -40,6 +47,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -58,14 +66,33 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(putiov1alpha1.AddToScheme(scheme))
+	utilruntime.Must(putiov1beta1.AddToScheme(scheme))
+	utilruntime.Must(configv1alpha1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 //nolint:cyclop
 func main() {
 	var (
-		configFile string
-		version    bool
+		configFile     string
+		version        bool
+		resyncInterval time.Duration
+
+		titleTemplate string
+
+		orphanInterval time.Duration
+		orphanPolicy   string
+		orphanDryRun   bool
+
+		quotaInterval      time.Duration
+		quotaHighWaterMark float64
+		quotaLowWaterMark  float64
+
+		staleCheckInterval time.Duration
+
+		putioRateLimit float64
+		putioBurst     int
+		putioAPIURL    string
 	)
 
 	flag.BoolVar(&version, "version", false, "Show current version")
@@ -73,6 +100,31 @@ func main() {
 		"The controller will load its initial configuration from this file. "+
 			"Omit this flag to use the default configuration values. "+
 			"Command-line flags override configuration from this file.")
+	flag.DurationVar(&resyncInterval, "resync-interval", time.Minute*10,
+		"How often each feed is compared with Put.io to detect and correct drift. Set to 0 to disable.")
+	flag.StringVar(&titleTemplate, "feed-title-template", controllers.DefaultFeedTitleTemplate,
+		"Go template of the Put.io feed titles, given .Title, .Name and .Namespace of the Feed. "+
+			"Use '{{ .Title }}' to leave titles clean, the operator then cannot recognize orphaned feeds.")
+	flag.DurationVar(&orphanInterval, "orphan-collection-interval", time.Hour,
+		"How often Put.io feeds managed by the operator without a matching Feed are looked for. Set to 0 to disable.")
+	flag.StringVar(&orphanPolicy, "orphan-policy", string(controllers.OrphanPolicyReport),
+		"What to do with orphaned Put.io feeds: 'report' them through events and metrics, or 'delete' them.")
+	flag.BoolVar(&orphanDryRun, "orphan-dry-run", false,
+		"Only report the orphaned Put.io feeds the 'delete' policy would delete.")
+	flag.DurationVar(&quotaInterval, "quota-check-interval", time.Minute*5,
+		"How often the disk usage of the Put.io accounts Feeds use is checked. Set to 0 to disable.")
+	flag.Float64Var(&quotaHighWaterMark, "quota-high-water-mark", 95,
+		"Percentage of used disk above which the Feeds of an account are paused.")
+	flag.Float64Var(&quotaLowWaterMark, "quota-low-water-mark", 90,
+		"Percentage of used disk below which Feeds paused by the quota check are resumed.")
+	flag.DurationVar(&staleCheckInterval, "stale-check-interval", time.Minute*10,
+		"How often Feeds are checked for staleness, for the Alerts notifying about Stale events. Set to 0 to disable.")
+	flag.Float64Var(&putioRateLimit, "putio-rate-limit", 5,
+		"Maximum number of Put.io requests per second made with the same token, shared by all reconcilers.")
+	flag.IntVar(&putioBurst, "putio-burst", 10,
+		"Maximum number of Put.io requests made at once with the same token.")
+	flag.StringVar(&putioAPIURL, "putio-api-url", "",
+		"URL of the Put.io API, to run against a fake Put.io API such as cmd/fake-putio. Defaults to https://api.put.io.")
 
 	opts := zap.Options{Development: os.Getenv("DEBUG") == "1"}
 	opts.BindFlags(flag.CommandLine)
@@ -108,14 +160,33 @@ func main() {
 	ctrl.SetLogger(newLogger) // reset the final logger as default
 
 	options := ctrl.Options{Scheme: scheme}
+	operatorConfig := configv1alpha1.OperatorConfig{}
 	if configFile != "" {
-		options, err = options.AndFrom(ctrl.ConfigFile().AtPath(configFile))
+		options, err = options.AndFrom(ctrl.ConfigFile().AtPath(configFile).OfKind(&operatorConfig))
 		if err != nil {
 			setupLog.Error(err, "unable to load the config file")
 			os.Exit(1)
 		}
 	}
 
+	if putioRateLimit <= 0 || putioBurst <= 0 {
+		setupLog.Error(nil, "invalid Put.io rate limit", "rate", putioRateLimit, "burst", putioBurst)
+		os.Exit(1)
+	}
+	putiohttp.SetRateLimit(putioRateLimit, putioBurst)
+	if putioAPIURL != "" {
+		if err := putio.SetBaseURL(putioAPIURL); err != nil {
+			setupLog.Error(err, "invalid Put.io API URL")
+			os.Exit(1)
+		}
+	}
+
+	titles, err := controllers.NewFeedTitleTemplate(titleTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid feed title template")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -126,14 +197,130 @@ func main() {
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("feed-reconciler"),
+
+		ResyncInterval: resyncInterval,
+		TitleTemplate:  titles,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Feed")
 		os.Exit(1)
 	}
+	if err = (&controllers.PutioAccountReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("putioaccount-reconciler"),
+
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PutioAccount")
+		os.Exit(1)
+	}
+	if err = (&controllers.FolderReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("folder-reconciler"),
+
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Folder")
+		os.Exit(1)
+	}
+	if err = (&controllers.FeedTemplateReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("feedtemplate-reconciler"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FeedTemplate")
+		os.Exit(1)
+	}
+	if err = (&controllers.FolderRetentionPolicyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("folderretentionpolicy-reconciler"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FolderRetentionPolicy")
+		os.Exit(1)
+	}
+	if err = (&controllers.TransferReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("transfer-reconciler"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Transfer")
+		os.Exit(1)
+	}
+	if err = (&controllers.AlertReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("alert-reconciler"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Alert")
+		os.Exit(1)
+	}
+	if err = mgr.Add(&controllers.NotificationDispatcher{
+		Client:             mgr.GetClient(),
+		Recorder:           mgr.GetEventRecorderFor("notification-dispatcher"),
+		Informers:          mgr.GetCache(),
+		StaleCheckInterval: staleCheckInterval,
+	}); err != nil {
+		setupLog.Error(err, "unable to add notification dispatcher")
+		os.Exit(1)
+	}
+	if orphanInterval > 0 {
+		switch policy := controllers.OrphanPolicy(orphanPolicy); policy {
+		case controllers.OrphanPolicyReport, controllers.OrphanPolicyDelete:
+			if err = mgr.Add(&controllers.OrphanCollector{
+				Client:   mgr.GetClient(),
+				Recorder: mgr.GetEventRecorderFor("orphan-collector"),
+				Interval: orphanInterval,
+				Policy:   policy,
+				DryRun:   orphanDryRun,
+
+				TitleTemplate: titles,
+			}); err != nil {
+				setupLog.Error(err, "unable to add orphan collector")
+				os.Exit(1)
+			}
+		default:
+			setupLog.Error(nil, "invalid orphan policy", "policy", orphanPolicy)
+			os.Exit(1)
+		}
+	}
+	if quotaInterval > 0 {
+		if quotaLowWaterMark >= quotaHighWaterMark || quotaHighWaterMark > 100 {
+			setupLog.Error(nil, "invalid quota water marks", "high", quotaHighWaterMark, "low", quotaLowWaterMark)
+			os.Exit(1)
+		}
+		if err = mgr.Add(&controllers.QuotaGuard{
+			Client:        mgr.GetClient(),
+			Recorder:      mgr.GetEventRecorderFor("quota-guard"),
+			Interval:      quotaInterval,
+			HighWaterMark: quotaHighWaterMark,
+			LowWaterMark:  quotaLowWaterMark,
+		}); err != nil {
+			setupLog.Error(err, "unable to add quota guard")
+			os.Exit(1)
+		}
+	}
+	if err = metrics.Registry.Register(controllers.NewStatusCollector(mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to register status collector")
+		os.Exit(1)
+	}
 	if err = (&putiov1alpha1.Feed{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Feed")
 		os.Exit(1)
 	}
+	if err = (&putiov1alpha1.Folder{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "Folder")
+		os.Exit(1)
+	}
+	if err = (&putiov1alpha1.FeedTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "FeedTemplate")
+		os.Exit(1)
+	}
+	if err = (&putiov1alpha1.FolderRetentionPolicy{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "FolderRetentionPolicy")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -146,7 +333,13 @@ func main() {
 	}
 
 	setupLog.Info("configure tracer")
-	tracerProvider, err := tracing.ConfigureTracing(context.Background(), serviceName, serviceVersion)
+	tracerProvider, err := tracing.ConfigureTracing(context.Background(), serviceName, serviceVersion, tracing.Config{
+		Exporter:      operatorConfig.Tracing.Exporter,
+		Protocol:      operatorConfig.Tracing.Protocol,
+		Endpoint:      operatorConfig.Tracing.Endpoint,
+		Insecure:      operatorConfig.Tracing.Insecure,
+		SamplingRatio: operatorConfig.Tracing.SamplingRatio,
+	})
 	if err != nil {
 		setupLog.Error(err, "unable to setup tracer")
 		os.Exit(1)